@@ -287,10 +287,16 @@ func (d *DHCPv4) HwTypeToString() string {
 	return hwtype
 }
 
-// SetHwType returns the hardware type as defined by IANA.
+// SetHwType sets the hardware type as defined by IANA. The htype field of a
+// DHCPv4 packet is 8 bits long, so hardware types larger than 255 (e.g.
+// iana.HwTypeAEthernet) are rejected and the current type is kept.
 func (d *DHCPv4) SetHwType(hwType iana.HwTypeType) {
+	if hwType > 0xff {
+		log.Printf("Warning: DHCPv4 hwtype %d does not fit in 8 bits, ignoring it", hwType)
+		return
+	}
 	if _, ok := iana.HwTypeToString[hwType]; !ok {
-		log.Printf("Warning: Invalid DHCPv4 hwtype: %v", hwType)
+		log.Printf("Warning: Invalid DHCPv4 hwtype: %d", hwType)
 	}
 	d.hwType = hwType
 }
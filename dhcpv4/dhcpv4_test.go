@@ -148,6 +148,10 @@ func TestSettersAndGetters(t *testing.T) {
 	require.Equal(t, iana.HwTypeEthernet, d.HwType())
 	d.SetHwType(iana.HwTypeARCNET)
 	require.Equal(t, iana.HwTypeARCNET, d.HwType())
+	// hardware types that do not fit in the htype field are rejected
+	d.SetHwType(iana.HwTypeAEthernet)
+	require.Equal(t, iana.HwTypeARCNET, d.HwType())
+	require.Equal(t, byte(iana.HwTypeARCNET), d.ToBytes()[1])
 
 	// getter/setter for HwAddrLen
 	require.Equal(t, uint8(6), d.HwAddrLen())
@@ -0,0 +1,235 @@
+package dhcpv6
+
+// This module implements encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler for messages and the most common options, by
+// delegating to ToBytes and the parsers. The binary form of an option
+// includes its code and length bytes.
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// unmarshalOption parses data, which must hold exactly one option, with
+// ParseOptionCopy, and stores the result in target, a pointer to an option
+// of the type the parser registered for the option code returns
+func unmarshalOption(target Option, data []byte) error {
+	if len(data) >= 4 {
+		// ParseOption rejects options shorter than their declared length,
+		// but ignores trailing bytes
+		code := OptionCode(binary.BigEndian.Uint16(data[0:2]))
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if len(data) > length+4 {
+			return fmt.Errorf("Invalid option length for option %v. Declared %v, actual %v", code, length, len(data)-4)
+		}
+	}
+	opt, err := ParseOptionCopy(data)
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(opt) != reflect.TypeOf(target) {
+		return fmt.Errorf("Invalid option: expected %T, got %T for option %v", target, opt, opt.Code())
+	}
+	reflect.ValueOf(target).Elem().Set(reflect.ValueOf(opt).Elem())
+	return nil
+}
+
+// MarshalBinary returns the serialized message, or an error if one of its
+// options is invalid, see Options.Valid
+func (d *DHCPv6Message) MarshalBinary() ([]byte, error) {
+	if err := d.options.Valid(); err != nil {
+		return nil, err
+	}
+	return d.ToBytes(), nil
+}
+
+// UnmarshalBinary parses a message, see MessageFromBytes
+func (d *DHCPv6Message) UnmarshalBinary(data []byte) error {
+	msg, err := MessageFromBytes(append([]byte(nil), data...))
+	if err != nil {
+		return err
+	}
+	*d = *msg
+	return nil
+}
+
+// MarshalBinary returns the serialized relay message, or an error if one of
+// its options, or of the options of the relayed message, is invalid, see
+// Options.Valid
+func (r *DHCPv6Relay) MarshalBinary() ([]byte, error) {
+	if err := r.options.Valid(); err != nil {
+		return nil, err
+	}
+	return r.ToBytes(), nil
+}
+
+// UnmarshalBinary parses a relay message, see RelayMessageFromBytes
+func (r *DHCPv6Relay) UnmarshalBinary(data []byte) error {
+	relay, err := RelayMessageFromBytes(append([]byte(nil), data...))
+	if err != nil {
+		return err
+	}
+	*r = *relay
+	return nil
+}
+
+// MarshalBinary returns the serialized option
+func (og *OptionGeneric) MarshalBinary() ([]byte, error) {
+	return og.ToBytes(), nil
+}
+
+// UnmarshalBinary parses an option of any code as an OptionGeneric
+func (og *OptionGeneric) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return newOptionParseError(0, ErrOptionTooShort, "Invalid DHCPv6 option: less than 4 bytes")
+	}
+	code := OptionCode(binary.BigEndian.Uint16(data[0:2]))
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if length != len(data)-4 {
+		return fmt.Errorf("Invalid option length for option %v. Declared %v, actual %v", code, length, len(data)-4)
+	}
+	og.OptionCode = code
+	og.OptionData = append([]byte(nil), data[4:]...)
+	return nil
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptClientId) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptClientId
+func (op *OptClientId) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptServerId) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptServerId
+func (op *OptServerId) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptElapsedTime) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptElapsedTime
+func (op *OptElapsedTime) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptStatusCode) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptStatusCode
+func (op *OptStatusCode) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptRequestedOption) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptRequestedOption
+func (op *OptRequestedOption) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option, or an error if it, or one of
+// the options nested in it, is invalid, see ValidOption
+func (op *OptIANA) MarshalBinary() ([]byte, error) {
+	if err := ValidOption(op); err != nil {
+		return nil, err
+	}
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptIANA
+func (op *OptIANA) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option, or an error if it, or one of
+// the options nested in it, is invalid, see ValidOption
+func (op *OptIAForPrefixDelegation) MarshalBinary() ([]byte, error) {
+	if err := ValidOption(op); err != nil {
+		return nil, err
+	}
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptIAForPrefixDelegation
+func (op *OptIAForPrefixDelegation) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option, or an error if it, or one of
+// the options nested in it, is invalid, see ValidOption
+func (op *OptIAAddress) MarshalBinary() ([]byte, error) {
+	if err := ValidOption(op); err != nil {
+		return nil, err
+	}
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptIAAddress
+func (op *OptIAAddress) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option, or an error if it, or one of
+// the options nested in it, is invalid, see ValidOption
+func (op *OptIAPrefix) MarshalBinary() ([]byte, error) {
+	if err := ValidOption(op); err != nil {
+		return nil, err
+	}
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptIAPrefix
+func (op *OptIAPrefix) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptRapidCommit) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptRapidCommit
+func (op *OptRapidCommit) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option, or an error if it, or one of
+// the options nested in it, is invalid, see ValidOption
+func (op *OptDNSRecursiveNameServer) MarshalBinary() ([]byte, error) {
+	if err := ValidOption(op); err != nil {
+		return nil, err
+	}
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptDNSRecursiveNameServer
+func (op *OptDNSRecursiveNameServer) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
+
+// MarshalBinary returns the serialized option
+func (op *OptDomainSearchList) MarshalBinary() ([]byte, error) {
+	return op.ToBytes(), nil
+}
+
+// UnmarshalBinary parses the option, see ParseOptDomainSearchList
+func (op *OptDomainSearchList) UnmarshalBinary(data []byte) error {
+	return unmarshalOption(op, data)
+}
@@ -0,0 +1,170 @@
+package dhcpv6
+
+import (
+	"encoding"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = &DHCPv6Message{}
+	_ encoding.BinaryUnmarshaler = &DHCPv6Message{}
+	_ encoding.BinaryMarshaler   = &DHCPv6Relay{}
+	_ encoding.BinaryUnmarshaler = &DHCPv6Relay{}
+	_ encoding.BinaryMarshaler   = &OptionGeneric{}
+	_ encoding.BinaryUnmarshaler = &OptionGeneric{}
+	_ encoding.BinaryMarshaler   = &OptIANA{}
+	_ encoding.BinaryUnmarshaler = &OptIANA{}
+)
+
+func TestOptionsBinaryRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{
+		{0, 1, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5},        // OPTION_CLIENTID
+		{0, 2, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5},        // OPTION_SERVERID
+		{0, 3, 0, 12, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2},  // OPTION_IA_NA
+		{0, 6, 0, 4, 0, 23, 0, 24},                         // OPTION_ORO
+		{0, 8, 0, 2, 0xaa, 0xbb},                           // OPTION_ELAPSED_TIME
+		{0, 13, 0, 4, 0, 0, 'o', 'k'},                      // OPTION_STATUS_CODE
+		{0, 14, 0, 0},                                      // OPTION_RAPID_COMMIT
+		{0, 25, 0, 12, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2}, // OPTION_IA_PD
+		// OPTION_IAADDR
+		{0, 5, 0, 24, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 2},
+		// OPTION_IAPREFIX
+		{0, 26, 0, 25, 0, 0, 0, 1, 0, 0, 0, 2, 48, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		// DNS_RECURSIVE_NAME_SERVER
+		{0, 23, 0, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x53},
+		// DOMAIN_SEARCH_LIST
+		{0, 24, 0, 13, 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0},
+	} {
+		opt, err := ParseOption(data)
+		require.NoError(t, err)
+		// a fresh value of the same type, filled in by UnmarshalBinary
+		var target interface {
+			encoding.BinaryMarshaler
+			encoding.BinaryUnmarshaler
+		}
+		switch opt.(type) {
+		case *OptClientId:
+			target = &OptClientId{}
+		case *OptServerId:
+			target = &OptServerId{}
+		case *OptIANA:
+			target = &OptIANA{}
+		case *OptRequestedOption:
+			target = &OptRequestedOption{}
+		case *OptElapsedTime:
+			target = &OptElapsedTime{}
+		case *OptStatusCode:
+			target = &OptStatusCode{}
+		case *OptRapidCommit:
+			target = &OptRapidCommit{}
+		case *OptIAForPrefixDelegation:
+			target = &OptIAForPrefixDelegation{}
+		case *OptIAAddress:
+			target = &OptIAAddress{}
+		case *OptIAPrefix:
+			target = &OptIAPrefix{}
+		case *OptDNSRecursiveNameServer:
+			target = &OptDNSRecursiveNameServer{}
+		case *OptDomainSearchList:
+			target = &OptDomainSearchList{}
+		default:
+			t.Fatalf("Unexpected option type %T", opt)
+		}
+		require.NoError(t, target.UnmarshalBinary(data))
+		b, err := target.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+	}
+}
+
+func TestOptionsBinaryUnmarshalErrors(t *testing.T) {
+	var cid OptClientId
+	// too short
+	require.Error(t, cid.UnmarshalBinary([]byte{0, 1, 0}))
+	// wrong code, parsed as another type
+	require.Error(t, cid.UnmarshalBinary([]byte{0, 2, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5}))
+	// length mismatch
+	require.Error(t, cid.UnmarshalBinary([]byte{0, 1, 0, 11, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5}))
+	require.Error(t, cid.UnmarshalBinary([]byte{0, 1, 0, 9, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5}))
+
+	// options are parsed like ParseOption does, nested options included
+	var ia OptIANA
+	err := ia.UnmarshalBinary([]byte{
+		0, 3, 0, 18, // OPTION_IA_NA
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 1, // T1
+		0, 0, 0, 2, // T2
+		0, 5, 0, 24, 0, 0, // truncated OPTION_IAADDR
+	})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+
+	var og OptionGeneric
+	require.NoError(t, og.UnmarshalBinary([]byte{0xaa, 0xbb, 0, 2, 1, 2}))
+	require.Equal(t, OptionCode(0xaabb), og.Code())
+	require.Equal(t, []byte{1, 2}, og.OptionData)
+}
+
+func TestMessageBinaryRoundTrip(t *testing.T) {
+	d, err := NewMessage()
+	require.NoError(t, err)
+	msg := d.(*DHCPv6Message)
+	msg.AddOption(&OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6})})
+	msg.AddOption(&OptElapsedTime{})
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	var parsed DHCPv6Message
+	require.NoError(t, parsed.UnmarshalBinary(data))
+	require.Equal(t, msg.TransactionID(), parsed.TransactionID())
+	b, err := parsed.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+
+	relay, err := EncapsulateRelay(msg, RELAY_FORW, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	data, err = relay.(*DHCPv6Relay).MarshalBinary()
+	require.NoError(t, err)
+	var parsedRelay DHCPv6Relay
+	require.NoError(t, parsedRelay.UnmarshalBinary(data))
+	b, err = parsedRelay.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+
+	require.Error(t, parsed.UnmarshalBinary([]byte{1}))
+}
+
+func TestMessageBinaryInvalidOptions(t *testing.T) {
+	msg := DHCPv6Message{messageType: SOLICIT}
+	msg.AddOption(&OptDNSRecursiveNameServer{NameServers: []net.IP{net.IPv4(192, 0, 2, 1).To4()}})
+	_, err := msg.MarshalBinary()
+	require.Error(t, err)
+
+	relay, err := EncapsulateRelay(&msg, RELAY_FORW, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	_, err = relay.(*DHCPv6Relay).MarshalBinary()
+	require.Error(t, err)
+
+	msg.UpdateOption(&OptDNSRecursiveNameServer{NameServers: []net.IP{nil}})
+	_, err = msg.MarshalBinary()
+	require.Error(t, err)
+}
+
+func TestOptionBinaryInvalid(t *testing.T) {
+	for _, opt := range []encoding.BinaryMarshaler{
+		&OptDNSRecursiveNameServer{NameServers: []net.IP{nil}},
+		&OptDNSRecursiveNameServer{NameServers: []net.IP{net.IPv4(192, 0, 2, 1).To4()}},
+		&OptIAAddress{},
+		&OptIAAddress{IPv6Addr: net.IPv4(192, 0, 2, 1).To4()},
+		// an invalid IA address nested in an IA_NA
+		NewOptIANA(IAID{1, 2, 3, 4}, &OptIAAddress{}),
+		&OptIANA{T1: 7200, T2: 3600},
+	} {
+		_, err := opt.MarshalBinary()
+		require.Error(t, err, "%v", opt)
+	}
+}
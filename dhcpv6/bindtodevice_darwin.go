@@ -0,0 +1,19 @@
+//go:build darwin
+// +build darwin
+
+package dhcpv6
+
+import (
+	"net"
+	"syscall"
+)
+
+// BindToInterface binds the socket fd to the given interface, so that it only
+// sends and receives packets through it
+func BindToInterface(fd int, ifname string) error {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return err
+	}
+	return syscall.SetsockoptInt(fd, syscall.IPPROTO_IPV6, syscall.IPV6_BOUND_IF, iface.Index)
+}
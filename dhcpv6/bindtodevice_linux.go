@@ -0,0 +1,14 @@
+//go:build linux
+// +build linux
+
+package dhcpv6
+
+import (
+	"syscall"
+)
+
+// BindToInterface binds the socket fd to the given interface, so that it only
+// sends and receives packets through it
+func BindToInterface(fd int, ifname string) error {
+	return syscall.BindToDevice(fd, ifname)
+}
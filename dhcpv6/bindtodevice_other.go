@@ -0,0 +1,16 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package dhcpv6
+
+import (
+	"net"
+)
+
+// BindToInterface is a no-op on platforms that cannot bind a socket to an
+// interface. It only checks that the interface exists; the zone of the
+// link-local address the socket is bound to selects the interface instead.
+func BindToInterface(fd int, ifname string) error {
+	_, err := net.InterfaceByName(ifname)
+	return err
+}
@@ -1,7 +1,9 @@
 package dhcpv6
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"net"
 	"time"
 )
@@ -14,25 +16,112 @@ const (
 	maxUDPReceivedPacketSize  = 8192            // arbitrary size. Theoretically could be up to 65kb
 )
 
-// Broadcast destination IP addresses as defined by RFC 3315
-var (
-	AllDHCPRelayAgentsAndServers = net.ParseIP("ff02::1:2")
-	AllDHCPServers               = net.ParseIP("ff05::1:3")
-)
+// RetransmitPolicy holds the retransmission parameters of a message type, as
+// defined by RFC 8415, section 15. A zero MRT, MRC or MRD means no limit.
+type RetransmitPolicy struct {
+	IRT time.Duration // initial retransmission time
+	MRT time.Duration // maximum retransmission time
+	MRC int           // maximum retransmission count
+	MRD time.Duration // maximum retransmission duration
+}
+
+// DefaultRetransmitPolicies holds the retransmission parameters defined by
+// RFC 8415, section 7.6, for each message type a client sends. RENEW and
+// REBIND exchanges should also end when T2 is reached and when the leases
+// expire, respectively, which the client does not track.
+var DefaultRetransmitPolicies = map[MessageType]RetransmitPolicy{
+	SOLICIT:             {IRT: 1 * time.Second, MRT: 3600 * time.Second},
+	REQUEST:             {IRT: 1 * time.Second, MRT: 30 * time.Second, MRC: 10},
+	CONFIRM:             {IRT: 1 * time.Second, MRT: 4 * time.Second, MRD: 10 * time.Second},
+	RENEW:               {IRT: 10 * time.Second, MRT: 600 * time.Second},
+	REBIND:              {IRT: 10 * time.Second, MRT: 600 * time.Second},
+	RELEASE:             {IRT: 1 * time.Second, MRC: 4},
+	DECLINE:             {IRT: 1 * time.Second, MRC: 5},
+	INFORMATION_REQUEST: {IRT: 1 * time.Second, MRT: 3600 * time.Second},
+	// RFC 5007, section 5.1
+	LEASEQUERY: {IRT: 1 * time.Second, MRT: 10 * time.Second, MRC: 5},
+}
 
-// Client implements a DHCPv6 client
+// defaultRetransmitPolicy is used for message types that have no parameters
+// defined by the RFC
+var defaultRetransmitPolicy = RetransmitPolicy{IRT: 1 * time.Second}
+
+// nextRetransmissionTimeout computes the retransmission timeout following
+// prevRT, or the initial one if prevRT is zero, according to the algorithm
+// described in RFC 8415, section 15. randFactor must be in [-0.1, 0.1].
+func nextRetransmissionTimeout(policy RetransmitPolicy, msgType MessageType, prevRT time.Duration, randFactor float64) time.Duration {
+	var rt time.Duration
+	if prevRT == 0 {
+		// the first Solicit must not be sent before IRT
+		if msgType == SOLICIT && randFactor < 0 {
+			randFactor = -randFactor
+		}
+		rt = policy.IRT + time.Duration(randFactor*float64(policy.IRT))
+	} else {
+		rt = 2*prevRT + time.Duration(randFactor*float64(prevRT))
+	}
+	if policy.MRT > 0 && rt > policy.MRT {
+		rt = policy.MRT + time.Duration(randFactor*float64(policy.MRT))
+	}
+	return rt
+}
+
+// retransmissionRand returns a random factor in [-0.1, 0.1]
+func retransmissionRand() float64 {
+	return (rand.Float64()*2 - 1) / 10
+}
+
+// Client implements a DHCPv6 client. ReadTimeout is the total time to wait
+// for a reply, including retransmissions. RetransmitPolicies overrides the
+// retransmission parameters of DefaultRetransmitPolicies for the message types
+// it contains. If RapidCommitFallback is set, RapidCommit falls back to the
+// 4-message exchange when the server does not support rapid commit.
 type Client struct {
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	LocalAddr    net.Addr
-	RemoteAddr   net.Addr
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
+	LocalAddr           net.Addr
+	RemoteAddr          net.Addr
+	RetransmitPolicies  map[MessageType]RetransmitPolicy
+	RapidCommitFallback bool
+
+	// conn, if set, is used instead of a UDP socket to exchange messages
+	conn connection
+}
+
+// connection is the subset of net.PacketConn a Client needs to exchange
+// messages. It allows tests to replace the UDP socket with another
+// transport.
+type connection interface {
+	ReadFrom(b []byte) (int, net.Addr, error)
+	WriteTo(b []byte, addr net.Addr) (int, error)
+	Close() error
+	SetReadDeadline(t time.Time) error
+}
+
+// writeDeadliner is implemented by connections that support write deadlines,
+// like *net.UDPConn
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// retransmitPolicy returns the retransmission parameters to use for the given
+// message type
+func (c *Client) retransmitPolicy(msgType MessageType) RetransmitPolicy {
+	if policy, ok := c.RetransmitPolicies[msgType]; ok {
+		return policy
+	}
+	if policy, ok := DefaultRetransmitPolicies[msgType]; ok {
+		return policy
+	}
+	return defaultRetransmitPolicy
 }
 
 // NewClient returns a Client with default settings
 func NewClient() *Client {
 	return &Client{
-		ReadTimeout:  DefaultReadTimeout,
-		WriteTimeout: DefaultWriteTimeout,
+		ReadTimeout:         DefaultReadTimeout,
+		WriteTimeout:        DefaultWriteTimeout,
+		RapidCommitFallback: true,
 	}
 }
 
@@ -41,11 +130,17 @@ func NewClient() *Client {
 // be applied to the Request packet. A common use is to make sure that the
 // Request packet has the right options, see modifiers.go
 func (c *Client) Exchange(ifname string, solicit DHCPv6, modifiers ...Modifier) ([]DHCPv6, error) {
+	return c.ExchangeContext(context.Background(), ifname, solicit, modifiers...)
+}
+
+// ExchangeContext is like Exchange, but stops waiting for replies and
+// retransmitting when ctx is done, returning ctx.Err()
+func (c *Client) ExchangeContext(ctx context.Context, ifname string, solicit DHCPv6, modifiers ...Modifier) ([]DHCPv6, error) {
 	conversation := make([]DHCPv6, 0)
 	var err error
 
 	// Solicit
-	solicit, advertise, err := c.Solicit(ifname, solicit)
+	solicit, advertise, err := c.SolicitContext(ctx, ifname, solicit)
 	if solicit != nil {
 		conversation = append(conversation, solicit)
 	}
@@ -54,12 +149,66 @@ func (c *Client) Exchange(ifname string, solicit DHCPv6, modifiers ...Modifier)
 	}
 	conversation = append(conversation, advertise)
 
-	request, reply, err := c.Request(ifname, advertise, nil)
+	request, reply, err := c.RequestContext(ctx, ifname, advertise, nil, modifiers...)
 	if request != nil {
 		conversation = append(conversation, request)
 	}
-	for _, mod := range modifiers {
-		request = mod(request)
+	if err != nil {
+		return conversation, err
+	}
+	conversation = append(conversation, reply)
+	return conversation, nil
+}
+
+// RapidCommit executes a 2-way DHCPv6 exchange (SOLICIT, REPLY) using the
+// rapid commit option. If the SOLICIT packet is nil, defaults are used, and
+// the rapid commit option is added if missing. A REPLY is only accepted if it
+// carries the rapid commit option too, other REPLYs are discarded and the
+// client keeps waiting for an ADVERTISE. If the server answers with an
+// ADVERTISE instead and c.RapidCommitFallback is set, the exchange continues
+// with a REQUEST as in Exchange, otherwise an error is returned. The modifiers
+// are applied to the REQUEST packet.
+func (c *Client) RapidCommit(ifname string, solicit DHCPv6, modifiers ...Modifier) ([]DHCPv6, error) {
+	return c.RapidCommitContext(context.Background(), ifname, solicit, modifiers...)
+}
+
+// RapidCommitContext is like RapidCommit, but stops waiting for replies and
+// retransmitting when ctx is done, returning ctx.Err()
+func (c *Client) RapidCommitContext(ctx context.Context, ifname string, solicit DHCPv6, modifiers ...Modifier) ([]DHCPv6, error) {
+	conversation := make([]DHCPv6, 0)
+	var err error
+	if solicit == nil {
+		solicit, err = NewSolicitForInterface(ifname)
+		if err != nil {
+			return conversation, err
+		}
+	}
+	if !Options(solicit.Options()).HasRapidCommit() {
+		solicit = WithRapidCommit(solicit)
+	}
+
+	solicit, reply, err := c.SolicitContext(ctx, ifname, solicit)
+	if solicit != nil {
+		conversation = append(conversation, solicit)
+	}
+	if err != nil {
+		return conversation, err
+	}
+	conversation = append(conversation, reply)
+	switch reply.Type() {
+	case REPLY:
+		return conversation, nil
+	case ADVERTISE:
+		if !c.RapidCommitFallback {
+			return conversation, fmt.Errorf("Server does not support rapid commit")
+		}
+	default:
+		return conversation, fmt.Errorf("Unexpected reply type %v", reply.Type())
+	}
+
+	request, reply, err := c.RequestContext(ctx, ifname, reply, nil, modifiers...)
+	if request != nil {
+		conversation = append(conversation, request)
 	}
 	if err != nil {
 		return conversation, err
@@ -68,14 +217,19 @@ func (c *Client) Exchange(ifname string, solicit DHCPv6, modifiers ...Modifier)
 	return conversation, nil
 }
 
-func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageType) (DHCPv6, error) {
+func (c *Client) sendReceive(ctx context.Context, ifname string, packet DHCPv6, expectedType MessageType) (DHCPv6, error) {
 	if packet == nil {
 		return nil, fmt.Errorf("Packet to send cannot be nil")
 	}
+	var expectedTypes []MessageType
 	if expectedType == MSGTYPE_NONE {
 		// infer the expected type from the packet being sent
 		if packet.Type() == SOLICIT {
 			expectedType = ADVERTISE
+			if Options(packet.Options()).HasRapidCommit() {
+				// the server may commit right away and answer with a REPLY
+				expectedTypes = append(expectedTypes, REPLY)
+			}
 		} else if packet.Type() == REQUEST {
 			expectedType = REPLY
 		} else if packet.Type() == RELAY_FORW {
@@ -84,26 +238,13 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 			expectedType = LEASEQUERY_REPLY
 		} // and probably more
 	}
-	// if no LocalAddr is specified, get the interface's link-local address
-	var laddr net.UDPAddr
-	if c.LocalAddr == nil {
-		llAddr, err := GetLinkLocalAddr(ifname)
-		if err != nil {
-			return nil, err
-		}
-		laddr = net.UDPAddr{IP: *llAddr, Port: DefaultClientPort, Zone: ifname}
-	} else {
-		if addr, ok := c.LocalAddr.(*net.UDPAddr); ok {
-			laddr = *addr
-		} else {
-			return nil, fmt.Errorf("Invalid local address: not a net.UDPAddr: %v", c.LocalAddr)
-		}
+	if expectedType != MSGTYPE_NONE {
+		expectedTypes = append(expectedTypes, expectedType)
 	}
-
 	// if no RemoteAddr is specified, use AllDHCPRelayAgentsAndServers
 	var raddr net.UDPAddr
 	if c.RemoteAddr == nil {
-		raddr = net.UDPAddr{IP: AllDHCPRelayAgentsAndServers, Port: DefaultServerPort}
+		raddr = net.UDPAddr{IP: AllDHCPRelayAgentsAndServers, Port: ServerPort, Zone: ifname}
 	} else {
 		if addr, ok := c.RemoteAddr.(*net.UDPAddr); ok {
 			raddr = *addr
@@ -112,39 +253,151 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 		}
 	}
 
-	// prepare the socket to listen on for replies
-	conn, err := net.ListenUDP("udp6", &laddr)
-	if err != nil {
-		return nil, err
+	conn := c.conn
+	if conn == nil {
+		udpConn, err := c.listen(ifname)
+		if err != nil {
+			return nil, err
+		}
+		defer udpConn.Close()
+		conn = udpConn
 	}
-	defer conn.Close()
+	return c.transmit(ctx, conn, &raddr, packet, expectedTypes)
+}
 
-	// send the packet out
-	conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
-	_, err = conn.WriteTo(packet.ToBytes(), &raddr)
-	if err != nil {
-		return nil, err
+// listen prepares the socket to listen on for replies. If no LocalAddr is
+// specified, it binds to the interface and its link-local address.
+func (c *Client) listen(ifname string) (*net.UDPConn, error) {
+	if c.LocalAddr == nil {
+		return BindUDPInterface(ifname, nil)
 	}
+	laddr, ok := c.LocalAddr.(*net.UDPAddr)
+	if !ok {
+		return nil, fmt.Errorf("Invalid local address: not a net.UDPAddr: %v", c.LocalAddr)
+	}
+	return net.ListenUDP("udp6", laddr)
+}
 
-	// wait for a reply
-	oobdata := []byte{} // ignoring oob data
-	conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+// transmit sends packet to raddr and waits for a reply of one of the expected
+// types, retransmitting packet according to the retransmission policy of its
+// type until a reply arrives, the policy's MRC or MRD is reached, or the read
+// timeout expires. In the last two cases the timeout error of the last read is
+// returned. If ctx is done first, ctx.Err() is returned. Nothing is sent if
+// the options of packet are invalid, see Options.Valid.
+func (c *Client) transmit(ctx context.Context, conn connection, raddr net.Addr, packet DHCPv6, expectedTypes []MessageType) (DHCPv6, error) {
+	if err := Options(packet.Options()).Valid(); err != nil {
+		return nil, err
+	}
 	var (
-		adv       DHCPv6
-		isMessage bool
+		start    = time.Now()
+		deadline = start.Add(c.ReadTimeout)
+		policy   = c.retransmitPolicy(packet.Type())
+		rt       time.Duration
 	)
-	defer conn.Close()
+	if policy.MRD > 0 && policy.MRD < c.ReadTimeout {
+		deadline = start.Add(policy.MRD)
+	}
+	if ctx.Done() != nil {
+		// unblock the pending read as soon as ctx is done
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				conn.SetReadDeadline(time.Unix(1, 0))
+			case <-stop:
+			}
+		}()
+	}
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// the first message of the exchange carries a zero elapsed time
+		var elapsed time.Duration
+		if attempt > 1 {
+			elapsed = time.Since(start)
+		}
+		setElapsedTime(packet, elapsed)
+		if wc, ok := conn.(writeDeadliner); ok {
+			wc.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+		}
+		if _, err := conn.WriteTo(packet.ToBytes(), raddr); err != nil {
+			return nil, err
+		}
+		rt = nextRetransmissionTimeout(policy, packet.Type(), rt, retransmissionRand())
+		readDeadline := time.Now().Add(rt)
+		if readDeadline.After(deadline) {
+			readDeadline = deadline
+		}
+		conn.SetReadDeadline(readDeadline)
+		// ctx may be done before the deadline above was set, overriding the
+		// one set by the goroutine
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		reply, err := readReply(conn, packet, expectedTypes)
+		if err == nil {
+			return reply, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+			return nil, err
+		}
+		if !time.Now().Before(deadline) || (policy.MRC > 0 && attempt >= policy.MRC) {
+			return nil, err
+		}
+	}
+}
+
+// elapsedTimeTypes are the types of the client messages that must carry an
+// elapsed time option, see RFC 8415, section 21.9 and appendix B
+var elapsedTimeTypes = map[MessageType]bool{
+	SOLICIT:             true,
+	REQUEST:             true,
+	CONFIRM:             true,
+	RENEW:               true,
+	REBIND:              true,
+	RELEASE:             true,
+	DECLINE:             true,
+	INFORMATION_REQUEST: true,
+}
+
+// setElapsedTime sets the elapsed time option of packet to the time since the
+// first transmission of the message, adding the option if packet is a client
+// message that lacks it. The value is clamped to the maximum the option can
+// carry. Relay messages are left unchanged.
+func setElapsedTime(packet DHCPv6, elapsed time.Duration) {
+	msg, ok := packet.(*DHCPv6Message)
+	if !ok {
+		return
+	}
+	if et, ok := msg.GetOneOption(OPTION_ELAPSED_TIME).(*OptElapsedTime); ok {
+		et.SetElapsedTime(elapsed)
+	} else if elapsedTimeTypes[msg.Type()] {
+		msg.AddOption(NewOptElapsedTime(elapsed))
+	}
+}
+
+// readReply waits for a reply of one of the expected types to packet, until
+// the read deadline of conn expires. If no type is expected, any reply is
+// accepted. REPLYs to a SOLICIT without the rapid commit option are always
+// discarded.
+func readReply(conn connection, packet DHCPv6, expectedTypes []MessageType) (DHCPv6, error) {
+	var isMessage bool
 	msg, ok := packet.(*DHCPv6Message)
 	if ok {
 		isMessage = true
 	}
 	for {
 		buf := make([]byte, maxUDPReceivedPacketSize)
-		n, _, _, _, err := conn.ReadMsgUDP(buf, oobdata)
+		n, _, err := conn.ReadFrom(buf)
 		if err != nil {
 			return nil, err
 		}
-		adv, err = FromBytes(buf[:n])
+		adv, err := FromBytes(buf[:n])
 		if err != nil {
 			// skip non-DHCP packets
 			continue
@@ -158,19 +411,32 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 				continue
 			}
 		}
-		if expectedType == MSGTYPE_NONE {
+		if isMessage && msg.Type() == SOLICIT && adv.Type() == REPLY && !Options(adv.Options()).HasRapidCommit() {
+			// a REPLY to a SOLICIT must carry the rapid commit option,
+			// otherwise it is discarded as required by RFC 8415
+			continue
+		}
+		if len(expectedTypes) == 0 {
 			// just take whatever arrived
-			break
-		} else if adv.Type() == expectedType {
-			break
+			return adv, nil
+		}
+		for _, expectedType := range expectedTypes {
+			if adv.Type() == expectedType {
+				return adv, nil
+			}
 		}
 	}
-	return adv, nil
 }
 
 // Solicit sends a SOLICIT, return the solicit, an ADVERTISE (if not nil), and
 // an error if any
 func (c *Client) Solicit(ifname string, solicit DHCPv6) (DHCPv6, DHCPv6, error) {
+	return c.SolicitContext(context.Background(), ifname, solicit)
+}
+
+// SolicitContext is like Solicit, but stops waiting for replies and
+// retransmitting when ctx is done, returning ctx.Err()
+func (c *Client) SolicitContext(ctx context.Context, ifname string, solicit DHCPv6) (DHCPv6, DHCPv6, error) {
 	var err error
 	if solicit == nil {
 		solicit, err = NewSolicitForInterface(ifname)
@@ -178,13 +444,20 @@ func (c *Client) Solicit(ifname string, solicit DHCPv6) (DHCPv6, DHCPv6, error)
 			return nil, nil, err
 		}
 	}
-	advertise, err := c.sendReceive(ifname, solicit, MSGTYPE_NONE)
+	advertise, err := c.sendReceive(ctx, ifname, solicit, MSGTYPE_NONE)
 	return solicit, advertise, err
 }
 
 // Request sends a REQUEST built from an ADVERTISE if no REQUEST is specified.
-// It returns the request, a reply if not nil, and an error if any
-func (c *Client) Request(ifname string, advertise, request DHCPv6) (DHCPv6, DHCPv6, error) {
+// The modifiers are applied to the REQUEST before it is sent. It returns the
+// request, a reply if not nil, and an error if any
+func (c *Client) Request(ifname string, advertise, request DHCPv6, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
+	return c.RequestContext(context.Background(), ifname, advertise, request, modifiers...)
+}
+
+// RequestContext is like Request, but stops waiting for replies and
+// retransmitting when ctx is done, returning ctx.Err()
+func (c *Client) RequestContext(ctx context.Context, ifname string, advertise, request DHCPv6, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
 	if request == nil {
 		var err error
 		request, err = NewRequestFromAdvertise(advertise)
@@ -192,6 +465,9 @@ func (c *Client) Request(ifname string, advertise, request DHCPv6) (DHCPv6, DHCP
 			return nil, nil, err
 		}
 	}
-	reply, err := c.sendReceive(ifname, request, MSGTYPE_NONE)
+	for _, mod := range modifiers {
+		request = mod(request)
+	}
+	reply, err := c.sendReceive(ctx, ifname, request, MSGTYPE_NONE)
 	return request, reply, err
 }
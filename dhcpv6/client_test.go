@@ -0,0 +1,438 @@
+package dhcpv6
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRetransmissionTimeout(t *testing.T) {
+	policy := DefaultRetransmitPolicies[REQUEST]
+	// no randomization
+	rt := nextRetransmissionTimeout(policy, REQUEST, 0, 0)
+	require.Equal(t, 1*time.Second, rt)
+	rt = nextRetransmissionTimeout(policy, REQUEST, rt, 0)
+	require.Equal(t, 2*time.Second, rt)
+	rt = nextRetransmissionTimeout(policy, REQUEST, rt, 0)
+	require.Equal(t, 4*time.Second, rt)
+	// capped to REQ_MAX_RT
+	rt = nextRetransmissionTimeout(policy, REQUEST, 20*time.Second, 0)
+	require.Equal(t, 30*time.Second, rt)
+
+	// with randomization
+	rt = nextRetransmissionTimeout(policy, REQUEST, 0, -0.1)
+	require.Equal(t, 900*time.Millisecond, rt)
+	rt = nextRetransmissionTimeout(policy, REQUEST, 30*time.Second, 0.1)
+	require.Equal(t, 33*time.Second, rt)
+}
+
+func TestNextRetransmissionTimeoutFirstSolicit(t *testing.T) {
+	// the first SOLICIT must not be retransmitted before SOL_TIMEOUT
+	rt := nextRetransmissionTimeout(DefaultRetransmitPolicies[SOLICIT], SOLICIT, 0, -0.1)
+	require.Equal(t, 1100*time.Millisecond, rt)
+}
+
+func TestRetransmissionRand(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		r := retransmissionRand()
+		require.True(t, r >= -0.1 && r <= 0.1, "random factor out of range: %v", r)
+	}
+}
+
+// advertiseHandler answers each SOLICIT with an ADVERTISE
+func advertiseHandler(msg *DHCPv6Message) DHCPv6 {
+	adv, err := NewAdvertiseFromSolicit(msg)
+	if err != nil {
+		return nil
+	}
+	return adv
+}
+
+// testServer listens on the IPv6 loopback and answers each message with the
+// one returned by handler, dropping the first drop packets it receives
+func testServer(t *testing.T, drop int, handler func(*DHCPv6Message) DHCPv6) (*net.UDPConn, chan int) {
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	received := make(chan int, 1)
+	go func() {
+		buf := make([]byte, maxUDPReceivedPacketSize)
+		count := 0
+		for {
+			n, peer, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				received <- count
+				return
+			}
+			count++
+			if count <= drop {
+				continue
+			}
+			msg, err := MessageFromBytes(buf[:n])
+			if err != nil {
+				continue
+			}
+			if reply := handler(msg); reply != nil {
+				conn.WriteToUDP(reply.ToBytes(), peer)
+			}
+		}
+	}()
+	return conn, received
+}
+
+func TestClientSolicitRetransmit(t *testing.T) {
+	srv, received := testServer(t, 1, advertiseHandler)
+	c := NewClient()
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = srv.LocalAddr()
+	solicit, err := NewSolicit(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	_, advertise, err := c.Solicit("lo", solicit)
+	require.NoError(t, err)
+	require.Equal(t, ADVERTISE, advertise.Type())
+	require.Equal(t, solicit.(*DHCPv6Message).TransactionID(), advertise.(*DHCPv6Message).TransactionID())
+	// the retransmission carries the time elapsed since the first attempt
+	et := solicit.GetOneOption(OPTION_ELAPSED_TIME).(*OptElapsedTime)
+	require.True(t, et.ElapsedTime() > 0)
+	srv.Close()
+	require.Equal(t, 2, <-received)
+}
+
+func TestClientSolicitTimeout(t *testing.T) {
+	srv, _ := testServer(t, 1000, advertiseHandler)
+	defer srv.Close()
+	c := NewClient()
+	c.ReadTimeout = 100 * time.Millisecond
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = srv.LocalAddr()
+	solicit, err := NewSolicit(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	start := time.Now()
+	_, _, err = c.Solicit("lo", solicit)
+	require.Error(t, err)
+	netErr, ok := err.(net.Error)
+	require.True(t, ok && netErr.Timeout(), "expected a timeout, got %v", err)
+	require.True(t, time.Since(start) < time.Second)
+}
+
+func TestClientSolicitContextCancel(t *testing.T) {
+	srv, _ := testServer(t, 1000, advertiseHandler)
+	defer srv.Close()
+	c := NewClient()
+	c.ReadTimeout = time.Minute
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = srv.LocalAddr()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	_, _, err := c.SolicitContext(ctx, "lo", newTestSolicit(t))
+	require.Equal(t, context.Canceled, err)
+	require.True(t, time.Since(start) < time.Second, "returned after %v", time.Since(start))
+}
+
+func TestClientExchangeContextDeadline(t *testing.T) {
+	srv, _ := testServer(t, 1000, advertiseHandler)
+	defer srv.Close()
+	c := newTestClient(srv)
+	c.ReadTimeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	conversation, err := c.ExchangeContext(ctx, "lo", newTestSolicit(t))
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, 1, len(conversation))
+	require.True(t, time.Since(start) < time.Second, "returned after %v", time.Since(start))
+}
+
+func TestClientTransmitContextCancelled(t *testing.T) {
+	// nothing is sent once the context is cancelled
+	c := NewClient()
+	conn := newFakePacketConn(0, advertiseHandler)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.transmit(ctx, conn, conn.LocalAddr(), newTestSolicit(t), []MessageType{ADVERTISE})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 0, len(conn.writes))
+}
+
+// rapidCommitHandler implements a server that answers a SOLICIT with a rapid
+// commit REPLY if rapidCommit is set, and performs a 4-message exchange
+// otherwise. If noRapidCommitOpt is set, the rapid commit REPLY does not
+// carry the rapid commit option.
+func rapidCommitHandler(rapidCommit, noRapidCommitOpt bool) func(*DHCPv6Message) DHCPv6 {
+	duid := *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{6, 7, 8, 9, 10, 11})
+	return func(msg *DHCPv6Message) DHCPv6 {
+		switch msg.Type() {
+		case SOLICIT:
+			if rapidCommit && Options(msg.Options()).HasRapidCommit() {
+				reply := DHCPv6Message{messageType: REPLY, transactionID: msg.TransactionID()}
+				reply.AddOption(msg.GetOneOption(OPTION_CLIENTID))
+				reply.AddOption(&OptServerId{Sid: duid})
+				if !noRapidCommitOpt {
+					reply.AddOption(&OptRapidCommit{})
+				}
+				return &reply
+			}
+			iaNa := msg.GetOneOption(OPTION_IA_NA).(*OptIANA)
+			adv, err := NewAdvertiseFromSolicit(msg, WithServerID(duid), WithIANA(iaNa))
+			if err != nil {
+				return nil
+			}
+			return adv
+		case REQUEST:
+			reply, err := NewReplyFromRequest(msg)
+			if err != nil {
+				return nil
+			}
+			return reply
+		}
+		return nil
+	}
+}
+
+func newTestClient(srv *net.UDPConn) *Client {
+	c := NewClient()
+	c.ReadTimeout = 500 * time.Millisecond
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = srv.LocalAddr()
+	return c
+}
+
+func newTestSolicit(t *testing.T) DHCPv6 {
+	solicit, err := NewSolicit(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	return solicit
+}
+
+func TestClientRapidCommit(t *testing.T) {
+	srv, _ := testServer(t, 0, rapidCommitHandler(true, false))
+	defer srv.Close()
+	c := newTestClient(srv)
+	conversation, err := c.RapidCommit("lo", newTestSolicit(t))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(conversation))
+	require.True(t, Options(conversation[0].Options()).HasRapidCommit())
+	require.Equal(t, REPLY, conversation[1].Type())
+}
+
+func TestClientRapidCommitFallback(t *testing.T) {
+	srv, _ := testServer(t, 0, rapidCommitHandler(false, false))
+	defer srv.Close()
+	c := newTestClient(srv)
+	conversation, err := c.RapidCommit("lo", newTestSolicit(t))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(conversation))
+	require.Equal(t, ADVERTISE, conversation[1].Type())
+	require.Equal(t, REQUEST, conversation[2].Type())
+	require.Equal(t, REPLY, conversation[3].Type())
+
+	c.RapidCommitFallback = false
+	conversation, err = c.RapidCommit("lo", newTestSolicit(t))
+	require.Error(t, err)
+	require.Equal(t, 2, len(conversation))
+}
+
+func TestClientRapidCommitMissingOption(t *testing.T) {
+	srv, _ := testServer(t, 0, rapidCommitHandler(true, true))
+	defer srv.Close()
+	c := newTestClient(srv)
+	// the REPLY is discarded, and no ADVERTISE follows
+	_, err := c.RapidCommit("lo", newTestSolicit(t))
+	require.Error(t, err)
+	netErr, ok := err.(net.Error)
+	require.True(t, ok && netErr.Timeout(), "expected a timeout, got %v", err)
+}
+
+func TestClientRapidCommitMissingOptionFallback(t *testing.T) {
+	// the first SOLICIT is answered with a REPLY without the rapid commit
+	// option, the retransmitted one with an ADVERTISE
+	fourWay, noOption := rapidCommitHandler(false, false), rapidCommitHandler(true, true)
+	solicits := 0
+	handler := func(msg *DHCPv6Message) DHCPv6 {
+		if msg.Type() == SOLICIT {
+			solicits++
+			if solicits == 1 {
+				return noOption(msg)
+			}
+		}
+		return fourWay(msg)
+	}
+	c := NewClient()
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		SOLICIT: {IRT: 20 * time.Millisecond, MRT: 200 * time.Millisecond},
+	}
+	c.conn = newFakePacketConn(0, handler)
+	conversation, err := c.RapidCommit("lo", newTestSolicit(t))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(conversation))
+	require.Equal(t, ADVERTISE, conversation[1].Type())
+	require.Equal(t, REPLY, conversation[3].Type())
+	require.Equal(t, 2, solicits)
+}
+
+// timeoutError is the net.Error returned by fakePacketConn when a read
+// deadline expires
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// fakePacketConn is a net.PacketConn that drops the first drop packets
+// written to it and answers the following ones with the packet returned by
+// handler. It records the time of each write.
+type fakePacketConn struct {
+	drop         int
+	handler      func(*DHCPv6Message) DHCPv6
+	writes       []time.Time
+	packets      [][]byte
+	replies      chan []byte
+	readDeadline time.Time
+}
+
+func newFakePacketConn(drop int, handler func(*DHCPv6Message) DHCPv6) *fakePacketConn {
+	return &fakePacketConn{drop: drop, handler: handler, replies: make(chan []byte, 16)}
+}
+
+func (f *fakePacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	timer := time.NewTimer(time.Until(f.readDeadline))
+	defer timer.Stop()
+	select {
+	case reply := <-f.replies:
+		return copy(b, reply), f.LocalAddr(), nil
+	case <-timer.C:
+		return 0, nil, timeoutError{}
+	}
+}
+
+func (f *fakePacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	f.writes = append(f.writes, time.Now())
+	f.packets = append(f.packets, append([]byte(nil), b...))
+	if len(f.writes) <= f.drop {
+		return len(b), nil
+	}
+	msg, err := MessageFromBytes(b)
+	if err != nil {
+		return 0, err
+	}
+	if reply := f.handler(msg); reply != nil {
+		f.replies <- reply.ToBytes()
+	}
+	return len(b), nil
+}
+
+func (f *fakePacketConn) Close() error                       { return nil }
+func (f *fakePacketConn) LocalAddr() net.Addr                { return &net.UDPAddr{IP: net.IPv6loopback} }
+func (f *fakePacketConn) SetDeadline(t time.Time) error      { return nil }
+func (f *fakePacketConn) SetWriteDeadline(t time.Time) error { return nil }
+func (f *fakePacketConn) SetReadDeadline(t time.Time) error {
+	f.readDeadline = t
+	return nil
+}
+
+func TestClientTransmitInvalidOptions(t *testing.T) {
+	// nothing is sent if an option is invalid
+	c := NewClient()
+	conn := newFakePacketConn(0, advertiseHandler)
+	solicit := WithDNS(nil)(newTestSolicit(t))
+	_, err := c.transmit(context.Background(), conn, conn.LocalAddr(), solicit, []MessageType{ADVERTISE})
+	require.Error(t, err)
+	require.Equal(t, 0, len(conn.writes))
+
+	solicit = WithDNS(net.IPv4(192, 0, 2, 1).To4())(newTestSolicit(t))
+	_, err = c.transmit(context.Background(), conn, conn.LocalAddr(), solicit, []MessageType{ADVERTISE})
+	require.Error(t, err)
+	require.Equal(t, 0, len(conn.writes))
+}
+
+func TestClientTransmitRetransmitPolicy(t *testing.T) {
+	c := NewClient()
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		SOLICIT: {IRT: 20 * time.Millisecond, MRT: 200 * time.Millisecond},
+	}
+	conn := newFakePacketConn(3, advertiseHandler)
+	solicit := newTestSolicit(t)
+	advertise, err := c.transmit(context.Background(), conn, conn.LocalAddr(), solicit, []MessageType{ADVERTISE})
+	require.NoError(t, err)
+	require.Equal(t, ADVERTISE, advertise.Type())
+	require.Equal(t, 4, len(conn.writes))
+	// the retransmission timeout roughly doubles at each attempt
+	for i := 2; i < len(conn.writes); i++ {
+		prev := conn.writes[i-1].Sub(conn.writes[i-2])
+		cur := conn.writes[i].Sub(conn.writes[i-1])
+		require.True(t, cur > prev, "retransmission %d after %v, previous after %v", i, cur, prev)
+	}
+}
+
+func TestClientTransmitMRC(t *testing.T) {
+	c := NewClient()
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		REQUEST: {IRT: 5 * time.Millisecond, MRC: 3},
+	}
+	conn := newFakePacketConn(1000, advertiseHandler)
+	request := DHCPv6Message{messageType: REQUEST, transactionID: 0xabcdef}
+	_, err := c.transmit(context.Background(), conn, conn.LocalAddr(), &request, []MessageType{REPLY})
+	require.Error(t, err)
+	require.Equal(t, 3, len(conn.writes))
+}
+
+func TestClientTransmitElapsedTime(t *testing.T) {
+	c := NewClient()
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		SOLICIT: {IRT: 20 * time.Millisecond, MRT: 200 * time.Millisecond},
+	}
+	conn := newFakePacketConn(2, advertiseHandler)
+	solicit := newTestSolicit(t)
+	// a stale value is reset for the first transmission
+	solicit.UpdateOption(NewOptElapsedTime(time.Minute))
+	_, err := c.transmit(context.Background(), conn, conn.LocalAddr(), solicit, []MessageType{ADVERTISE})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(conn.packets))
+	var elapsed []time.Duration
+	for _, p := range conn.packets {
+		msg, err := MessageFromBytes(p)
+		require.NoError(t, err)
+		elapsed = append(elapsed, msg.GetOneOption(OPTION_ELAPSED_TIME).(*OptElapsedTime).ElapsedTime())
+	}
+	require.Equal(t, time.Duration(0), elapsed[0])
+	require.True(t, elapsed[1] > 0, "elapsed times %v", elapsed)
+	require.True(t, elapsed[2] > elapsed[1], "elapsed times %v", elapsed)
+
+	// the option is added to client messages lacking it
+	conn = newFakePacketConn(0, func(*DHCPv6Message) DHCPv6 { return nil })
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		REQUEST: {IRT: 5 * time.Millisecond, MRC: 1},
+	}
+	request := DHCPv6Message{messageType: REQUEST, transactionID: 0xabcdef}
+	c.transmit(context.Background(), conn, conn.LocalAddr(), &request, []MessageType{REPLY})
+	msg, err := MessageFromBytes(conn.packets[0])
+	require.NoError(t, err)
+	require.NotNil(t, msg.GetOneOption(OPTION_ELAPSED_TIME))
+}
+
+func TestSetElapsedTimeClamped(t *testing.T) {
+	msg := DHCPv6Message{messageType: SOLICIT}
+	setElapsedTime(&msg, 24*time.Hour)
+	require.Equal(t, time.Duration(0xffff)*10*time.Millisecond, msg.GetOneOption(OPTION_ELAPSED_TIME).(*OptElapsedTime).ElapsedTime())
+}
+
+func TestClientTransmitMRD(t *testing.T) {
+	c := NewClient()
+	c.ReadTimeout = time.Minute
+	c.RetransmitPolicies = map[MessageType]RetransmitPolicy{
+		SOLICIT: {IRT: 10 * time.Millisecond, MRD: 100 * time.Millisecond},
+	}
+	conn := newFakePacketConn(1000, advertiseHandler)
+	start := time.Now()
+	_, err := c.transmit(context.Background(), conn, conn.LocalAddr(), newTestSolicit(t), []MessageType{ADVERTISE})
+	require.Error(t, err)
+	netErr, ok := err.(net.Error)
+	require.True(t, ok && netErr.Timeout(), "expected a timeout, got %v", err)
+	require.True(t, time.Since(start) < time.Second)
+	require.True(t, len(conn.writes) > 1)
+}
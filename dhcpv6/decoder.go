@@ -0,0 +1,71 @@
+package dhcpv6
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Decoder reads big-endian values from a byte slice, keeping track of the
+// current offset. The slices returned by ReadN alias the underlying buffer,
+// so no copy is made: callers that need to retain or modify the data must
+// copy it.
+type Decoder struct {
+	data []byte
+	off  int
+}
+
+// NewDecoder returns a Decoder reading from the given buffer
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Len returns the number of bytes that have not been read yet
+func (d *Decoder) Len() int {
+	return len(d.data) - d.off
+}
+
+// Offset returns the number of bytes read so far
+func (d *Decoder) Offset() int {
+	return d.off
+}
+
+// Read8 reads a single byte
+func (d *Decoder) Read8() (uint8, error) {
+	if d.Len() < 1 {
+		return 0, fmt.Errorf("Decoder: short data: cannot read 1 byte, %v left", d.Len())
+	}
+	v := d.data[d.off]
+	d.off++
+	return v, nil
+}
+
+// Read16 reads a big-endian uint16
+func (d *Decoder) Read16() (uint16, error) {
+	if d.Len() < 2 {
+		return 0, fmt.Errorf("Decoder: short data: cannot read 2 bytes, %v left", d.Len())
+	}
+	v := binary.BigEndian.Uint16(d.data[d.off : d.off+2])
+	d.off += 2
+	return v, nil
+}
+
+// Read32 reads a big-endian uint32
+func (d *Decoder) Read32() (uint32, error) {
+	if d.Len() < 4 {
+		return 0, fmt.Errorf("Decoder: short data: cannot read 4 bytes, %v left", d.Len())
+	}
+	v := binary.BigEndian.Uint32(d.data[d.off : d.off+4])
+	d.off += 4
+	return v, nil
+}
+
+// ReadN returns the next n bytes. The returned slice aliases the decoder's
+// buffer.
+func (d *Decoder) ReadN(n int) ([]byte, error) {
+	if n < 0 || d.Len() < n {
+		return nil, fmt.Errorf("Decoder: short data: cannot read %v bytes, %v left", n, d.Len())
+	}
+	v := d.data[d.off : d.off+n]
+	d.off += n
+	return v, nil
+}
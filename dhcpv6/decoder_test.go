@@ -0,0 +1,51 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}
+	d := NewDecoder(data)
+	require.Equal(t, 9, d.Len())
+
+	v8, err := d.Read8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x01), v8)
+
+	v16, err := d.Read16()
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x0203), v16)
+
+	v32, err := d.Read32()
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x04050607), v32)
+
+	b, err := d.ReadN(2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x08, 0x09}, b)
+	require.Equal(t, 0, d.Len())
+	require.Equal(t, 9, d.Offset())
+
+	// ReadN aliases the input buffer
+	b[0] = 0xff
+	require.Equal(t, byte(0xff), data[7])
+}
+
+func TestDecoderShortData(t *testing.T) {
+	d := NewDecoder([]byte{0x01})
+	_, err := d.Read16()
+	require.Error(t, err)
+	_, err = d.Read32()
+	require.Error(t, err)
+	_, err = d.ReadN(2)
+	require.Error(t, err)
+	// failed reads do not consume data
+	v, err := d.Read8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x01), v)
+	_, err = d.Read8()
+	require.Error(t, err)
+}
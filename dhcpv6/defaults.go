@@ -1,6 +1,28 @@
 package dhcpv6
 
+import (
+	"net"
+)
+
+// UDP ports, as defined by RFC 8415, section 7.2
 const (
-	DefaultClientPort = 546
-	DefaultServerPort = 547
+	// ClientPort is the port clients listen on
+	ClientPort = 546
+	// ServerPort is the port servers and relay agents listen on
+	ServerPort = 547
+
+	// DefaultClientPort is an alias of ClientPort, kept for compatibility
+	DefaultClientPort = ClientPort
+	// DefaultServerPort is an alias of ServerPort, kept for compatibility
+	DefaultServerPort = ServerPort
+)
+
+// Multicast addresses, as defined by RFC 8415, section 7.1
+var (
+	// AllDHCPRelayAgentsAndServers is the link-scoped address clients use to
+	// reach the relay agents and servers on their link
+	AllDHCPRelayAgentsAndServers = net.ParseIP("ff02::1:2")
+	// AllDHCPServers is the site-scoped address relay agents use to reach
+	// all the servers of their site
+	AllDHCPServers = net.ParseIP("ff05::1:3")
 )
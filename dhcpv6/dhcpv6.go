@@ -24,63 +24,25 @@ type DHCPv6 interface {
 // structures. This is used to simplify packet manipulation
 type Modifier func(d DHCPv6) DHCPv6
 
+// FromBytes parses a DHCPv6 message or relay message from a sequence of bytes
 func FromBytes(data []byte) (DHCPv6, error) {
-	var (
-		isRelay     = false
-		headerSize  int
-		messageType = MessageType(data[0])
-	)
-	if messageType == RELAY_FORW || messageType == RELAY_REPL {
-		isRelay = true
+	if len(data) < 1 {
+		return nil, fmt.Errorf("Invalid DHCPv6 packet: empty data")
 	}
-	if isRelay {
-		headerSize = RelayHeaderSize
-	} else {
-		headerSize = MessageHeaderSize
-	}
-	if len(data) < headerSize {
-		return nil, fmt.Errorf("Invalid header size: shorter than %v bytes", headerSize)
-	}
-	if isRelay {
-		var (
-			linkAddr, peerAddr []byte
-		)
-		d := DHCPv6Relay{
-			messageType: messageType,
-			hopCount:    uint8(data[1]),
-		}
-		linkAddr = append(linkAddr, data[2:18]...)
-		d.linkAddr = linkAddr
-		peerAddr = append(peerAddr, data[18:34]...)
-		d.peerAddr = peerAddr
-		options, err := OptionsFromBytes(data[34:])
-		if err != nil {
-			return nil, err
-		}
-		// TODO fail if no OptRelayMessage is present
-		d.options = options
-		return &d, nil
-	} else {
-		tid, err := BytesToTransactionID(data[1:4])
-		if err != nil {
-			return nil, err
-		}
-		d := DHCPv6Message{
-			messageType:   messageType,
-			transactionID: *tid,
-		}
-		options, err := OptionsFromBytes(data[4:])
-		if err != nil {
-			return nil, err
-		}
-		d.options = options
-		return &d, nil
+	messageType := MessageType(data[0])
+	if messageType == RELAY_FORW || messageType == RELAY_REPL {
+		return RelayMessageFromBytes(data)
 	}
+	return MessageFromBytes(data)
 }
 
 // NewMessage creates a new DHCPv6 message with default options
 func NewMessage(modifiers ...Modifier) (DHCPv6, error) {
-	tid, err := GenerateTransactionID()
+	tidBytes, err := GenerateTransactionID()
+	if err != nil {
+		return nil, err
+	}
+	tid, err := BytesToTransactionID(tidBytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -96,27 +58,6 @@ func NewMessage(modifiers ...Modifier) (DHCPv6, error) {
 	return d, nil
 }
 
-func getOptions(options []Option, code OptionCode, onlyFirst bool) []Option {
-	var ret []Option
-	for _, opt := range options {
-		if opt.Code() == code {
-			ret = append(ret, opt)
-			if onlyFirst {
-				break
-			}
-		}
-	}
-	return ret
-}
-
-func getOption(options []Option, code OptionCode) Option {
-	opts := getOptions(options, code, true)
-	if opts == nil {
-		return nil
-	}
-	return opts[0]
-}
-
 // DecapsulateRelay extracts the content of a relay message. It does not recurse
 // if there are nested relay messages. Returns the original packet if is not not
 // a relay message
@@ -128,7 +69,10 @@ func DecapsulateRelay(l DHCPv6) (DHCPv6, error) {
 	if opt == nil {
 		return nil, fmt.Errorf("No OptRelayMsg found")
 	}
-	relayOpt := opt.(*OptRelayMsg)
+	relayOpt, ok := opt.(*OptRelayMsg)
+	if !ok {
+		return nil, fmt.Errorf("Invalid OptRelayMsg: %v", opt)
+	}
 	if relayOpt.RelayMessage() == nil {
 		return nil, fmt.Errorf("Relay message cannot be nil")
 	}
@@ -157,3 +101,82 @@ func EncapsulateRelay(d DHCPv6, mType MessageType, linkAddr, peerAddr net.IP) (D
 	outer.AddOption(&orm)
 	return &outer, nil
 }
+
+// messageLines renders a message for String, one line per option, each line
+// prefixed with indent. depth is the number of relay messages enclosing d;
+// relay chains deeper than MaxRelayDepth, which can only be built by hand,
+// e.g. by making a relay message contain itself, are truncated.
+func messageLines(d DHCPv6, indent string, depth int) []string {
+	if depth > MaxRelayDepth {
+		return []string{indent + "(relay messages nested too deep)"}
+	}
+	var header string
+	switch m := d.(type) {
+	case *DHCPv6Message:
+		header = fmt.Sprintf("DHCPv6Message(messageType=%v transactionID=0x%06x)",
+			m.MessageTypeToString(), m.TransactionID())
+	case *DHCPv6Relay:
+		header = fmt.Sprintf("DHCPv6Relay(messageType=%v hopcount=%v linkaddr=%v peeraddr=%v)",
+			m.MessageTypeToString(), m.HopCount(), m.LinkAddr(), m.PeerAddr())
+	default:
+		return []string{indent + d.String()}
+	}
+	lines := []string{indent + header}
+	for _, opt := range d.Options() {
+		lines = append(lines, optionLines(opt, indent+"  ", depth)...)
+	}
+	return lines
+}
+
+// optionLines renders an option for String. Options that contain other
+// options or messages are rendered on multiple lines, with the nested ones
+// further indented. depth is as in messageLines.
+func optionLines(opt Option, indent string, depth int) []string {
+	var (
+		header string
+		nested Options
+	)
+	switch o := opt.(type) {
+	case *OptRelayMsg:
+		if o.RelayMessage() == nil {
+			break
+		}
+		return append([]string{indent + "OptRelayMsg"}, messageLines(o.RelayMessage(), indent+"  ", depth+1)...)
+	case *OptIANA:
+		header = fmt.Sprintf("OptIANA{IAID=%v, t1=%v, t2=%v}", o.IaId, o.T1, o.T2)
+		nested = o.Options
+	case *OptIAForPrefixDelegation:
+		header = fmt.Sprintf("OptIAForPrefixDelegation{IAID=%v, t1=%v, t2=%v}", o.IAID(), o.T1(), o.T2())
+		nested = o.Options()
+	case *OptIAAddress:
+		header = fmt.Sprintf("OptIAAddress{ipv6addr=%v, preferredlifetime=%v, validlifetime=%v}",
+			net.IP(o.IPv6Addr[:]), o.PreferredLifetime(), o.ValidLifetime())
+		nested = o.Options
+	case *OptIAPrefix:
+		header = fmt.Sprintf("OptIAPrefix{preferredlifetime=%v, validlifetime=%v, prefix=%v}",
+			o.PreferredLifetime(), o.ValidLifetime(), o.Prefix())
+		nested = o.Options()
+	case *OptS46ContainerMAPE:
+		header, nested = "OptS46ContainerMAPE", o.Options
+	case *OptS46ContainerMAPT:
+		header, nested = "OptS46ContainerMAPT", o.Options
+	case *OptS46ContainerLW:
+		header, nested = "OptS46ContainerLW", o.Options
+	case *OptClientData:
+		header, nested = "OptClientData", o.Options
+	case *OptLQRelayData:
+		if o.RelayMessage == nil {
+			break
+		}
+		return append([]string{indent + fmt.Sprintf("OptLQRelayData{peeraddress=%v}", o.PeerAddress)},
+			messageLines(o.RelayMessage, indent+"  ", depth+1)...)
+	}
+	if len(nested) == 0 {
+		return []string{indent + opt.String()}
+	}
+	lines := []string{indent + header}
+	for _, n := range nested {
+		lines = append(lines, optionLines(n, indent+"  ", depth)...)
+	}
+	return lines
+}
@@ -1,8 +1,13 @@
 package dhcpv6
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
 	"testing"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,10 +26,77 @@ func TestBytesToTransactionIDShortData(t *testing.T) {
 }
 
 func TestGenerateTransactionID(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		tid, err := GenerateTransactionID()
+		require.NoError(t, err)
+		require.NotEqual(t, [3]byte{}, tid)
+	}
+}
+
+func TestRandReader(t *testing.T) {
+	defer func(r io.Reader) { RandReader = r }(RandReader)
+
+	// zero transaction IDs are skipped
+	RandReader = bytes.NewReader([]byte{0, 0, 0, 0xaa, 0xbb, 0xcc})
 	tid, err := GenerateTransactionID()
 	require.NoError(t, err)
-	require.NotNil(t, *tid)
-	require.True(t, *tid <= 0xffffff, "transaction ID should be smaller than 0xffffff")
+	require.Equal(t, [3]byte{0xaa, 0xbb, 0xcc}, tid)
+
+	// messages are reproducible
+	seed := []byte{0x11, 0x22, 0x33, 1, 2, 3, 4}
+	RandReader = bytes.NewReader(seed)
+	d, err := NewSolicit(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	RandReader = bytes.NewReader(seed)
+	d2, err := NewSolicit(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x112233), d.(*DHCPv6Message).TransactionID())
+	require.Equal(t, IAID{1, 2, 3, 4}, d.GetOneOption(OPTION_IA_NA).(*OptIANA).IaId)
+	require.Equal(t, d.ToBytes(), d2.ToBytes())
+
+	// an exhausted reader is an error
+	RandReader = bytes.NewReader([]byte{1})
+	_, err = GenerateTransactionID()
+	require.Error(t, err)
+}
+
+// zeroReader is an io.Reader that only returns zeros
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestGenerateTransactionIDZeroReader(t *testing.T) {
+	defer func(r io.Reader) { RandReader = r }(RandReader)
+	RandReader = zeroReader{}
+	_, err := GenerateTransactionID()
+	require.Error(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	req := DHCPv6Message{messageType: REQUEST, transactionID: 0xaabbcc}
+	resp := DHCPv6Message{messageType: REPLY, transactionID: 0xaabbcc}
+	require.True(t, Matches(&req, &resp))
+
+	// mismatched transaction ID
+	resp.transactionID = 0xaabbcd
+	require.False(t, Matches(&req, &resp))
+
+	// not a valid response type
+	resp = DHCPv6Message{messageType: ADVERTISE, transactionID: 0xaabbcc}
+	require.False(t, Matches(&req, &resp))
+
+	// rapid commit
+	sol := DHCPv6Message{messageType: SOLICIT, transactionID: 0xaabbcc}
+	resp = DHCPv6Message{messageType: REPLY, transactionID: 0xaabbcc}
+	require.True(t, Matches(&sol, &resp))
+
+	require.False(t, Matches(&resp, &sol))
+	require.False(t, Matches(nil, &resp))
 }
 
 func TestNewMessage(t *testing.T) {
@@ -82,6 +154,76 @@ func TestFromAndToBytes(t *testing.T) {
 	require.Equal(t, expected, toBytes)
 }
 
+func TestNewSolicit(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xfa, 0xce, 0xb0, 0x00, 0x00, 0x0c}
+	s, err := NewSolicit(hwaddr, WithRapidCommit, WithRequestedOptions(DNS_RECURSIVE_NAME_SERVER))
+	require.NoError(t, err)
+	require.Equal(t, SOLICIT, s.Type())
+	opt := s.GetOneOption(OPTION_CLIENTID)
+	require.NotNil(t, opt)
+	duid := opt.(*OptClientId).Cid
+	require.Equal(t, DUID_LL, duid.Type)
+	require.Equal(t, hwaddr, duid.LinkLayerAddr)
+	require.NotNil(t, s.GetOneOption(OPTION_ELAPSED_TIME))
+	require.NotNil(t, s.GetOneOption(OPTION_IA_NA))
+	require.NotNil(t, s.GetOneOption(OPTION_RAPID_COMMIT))
+	require.NotNil(t, s.GetOneOption(OPTION_ORO))
+
+	// round-trip
+	m, err := MessageFromBytes(s.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, s.ToBytes(), m.ToBytes())
+}
+
+func TestMessageFromBytes(t *testing.T) {
+	data := []byte{
+		01,               // SOLICIT
+		0xab, 0xcd, 0xef, // transaction ID
+		0x00, 0x08, 0x00, 0x02, 0x00, 0x00, // OptElapsedTime
+	}
+	d, err := MessageFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, SOLICIT, d.Type())
+	require.Equal(t, uint32(0xabcdef), d.TransactionID())
+	require.Equal(t, 1, len(d.Options()))
+	require.Equal(t, data, d.ToBytes())
+}
+
+func TestMessageFromBytesShortData(t *testing.T) {
+	_, err := MessageFromBytes([]byte{01, 0xab, 0xcd})
+	require.Error(t, err)
+}
+
+func TestMessageFromBytesRelay(t *testing.T) {
+	data := make([]byte, RelayHeaderSize)
+	data[0] = byte(RELAY_FORW)
+	_, err := MessageFromBytes(data)
+	require.Error(t, err)
+}
+
+func TestMessageFromBytesUnknownType(t *testing.T) {
+	for _, mt := range []byte{0, 18, 0xff} {
+		data := []byte{mt, 0xab, 0xcd, 0xef}
+		_, err := MessageFromBytes(data)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), fmt.Sprintf("%d", mt))
+		d, err := MessageFromBytesMode(data, false)
+		require.NoError(t, err)
+		require.Equal(t, MessageType(mt), d.Type())
+	}
+}
+
+func TestMessageTypeIsValid(t *testing.T) {
+	require.False(t, MSGTYPE_NONE.IsValid())
+	require.True(t, SOLICIT.IsValid())
+	require.True(t, RELAY_REPL.IsValid())
+	require.True(t, LEASEQUERY_DATA.IsValid())
+	require.False(t, MessageType(18).IsValid())
+	require.True(t, DHCPV4_QUERY.IsValid())
+	require.True(t, DHCPV4_RESPONSE.IsValid())
+	require.False(t, MessageType(22).IsValid())
+}
+
 func withServerID(d DHCPv6) DHCPv6 {
 	sid := OptServerId{}
 	d.AddOption(&sid)
@@ -116,6 +258,33 @@ func TestNewReplyFromRequest(t *testing.T) {
 	require.Equal(t, rep.Type(), REPLY)
 }
 
+func TestNewAdvertiseFromSolicitWithLease(t *testing.T) {
+	s := DHCPv6Message{}
+	s.SetMessage(SOLICIT)
+	s.SetTransactionID(0xabcdef)
+
+	serverDuid := Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HwTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0xfa, 0xce, 0xb0, 0x00, 0x00, 0x0c},
+	}
+	iaNa := OptIANA{IaId: IAID{1, 2, 3, 4}, T1: 3600, T2: 5400}
+
+	// missing Client ID
+	_, err := NewAdvertiseFromSolicit(&s, WithServerID(serverDuid))
+	require.Error(t, err)
+
+	cid := OptClientId{Cid: Duid{Type: DUID_LL, HwType: iana.HwTypeEthernet}}
+	s.AddOption(&cid)
+	a, err := NewAdvertiseFromSolicit(&s, WithServerID(serverDuid), WithIANA(&iaNa))
+	require.NoError(t, err)
+	require.Equal(t, ADVERTISE, a.Type())
+	require.Equal(t, s.TransactionID(), a.(*DHCPv6Message).TransactionID())
+	require.Equal(t, &cid, a.GetOneOption(OPTION_CLIENTID))
+	require.Equal(t, serverDuid, a.GetOneOption(OPTION_SERVERID).(*OptServerId).Sid)
+	require.Equal(t, &iaNa, a.GetOneOption(OPTION_IA_NA))
+}
+
 func TestNewReplyFromRenew(t *testing.T) {
 	ren := DHCPv6Message{}
 	ren.SetMessage(RENEW)
@@ -152,3 +321,115 @@ func TestNewReplyFromRebind(t *testing.T) {
 
 // TODO test NewSolicit
 //      test String and Summary
+
+func TestMessageString(t *testing.T) {
+	ia := &OptIANA{IaId: IAID{0xfa, 0xce, 0xb0, 0x0c}, T1: 10, T2: 20}
+	ia.Options.Add(&OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")})
+	msg := DHCPv6Message{
+		messageType:   SOLICIT,
+		transactionID: 0x1a2b3c,
+		options:       Options{&OptRapidCommit{}, ia},
+	}
+	expected := "DHCPv6Message(messageType=SOLICIT transactionID=0x1a2b3c)\n" +
+		"  " + (&OptRapidCommit{}).String() + "\n" +
+		"  OptIANA{IAID=faceb00c, t1=10, t2=20}\n" +
+		"    " + ia.Options[0].String()
+	require.Equal(t, expected, msg.String())
+}
+
+func TestRelayString(t *testing.T) {
+	msg := DHCPv6Message{messageType: SOLICIT, transactionID: 0x1a2b3c}
+	relay, err := EncapsulateRelay(&msg, RELAY_FORW, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	expected := "DHCPv6Relay(messageType=RELAY-FORW hopcount=0 linkaddr=::1 peeraddr=::1)\n" +
+		"  OptRelayMsg\n" +
+		"    DHCPv6Message(messageType=SOLICIT transactionID=0x1a2b3c)"
+	require.Equal(t, expected, relay.String())
+}
+
+func TestMessageSummary(t *testing.T) {
+	msg := DHCPv6Message{
+		messageType:   SOLICIT,
+		transactionID: 0x1a2b3c,
+		options: Options{
+			&OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff})},
+			&OptIANA{},
+		},
+	}
+	require.Equal(t, "SOLICIT xid=0x1a2b3c cid=DUID-LL(aa:bb:cc:dd:ee:ff) ia_na=1 ia_pd=0", msg.Summary())
+
+	msg.options = Options{&OptIAForPrefixDelegation{}, &OptIAForPrefixDelegation{}}
+	msg.transactionID = 0x0000ff
+	require.Equal(t, "SOLICIT xid=0x0000ff ia_na=0 ia_pd=2", msg.Summary())
+
+	msg.options = Options{&OptClientId{Cid: *NewDuidEN(9, []byte{0x01, 0x02})}}
+	require.Equal(t, "SOLICIT xid=0x0000ff cid=DUID-EN(9:0102) ia_na=0 ia_pd=0", msg.Summary())
+}
+
+func TestMessageSummaryAllocations(t *testing.T) {
+	msg := DHCPv6Message{
+		messageType:   REQUEST,
+		transactionID: 0x1a2b3c,
+		options: Options{
+			&OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff})},
+			&OptIANA{},
+		},
+	}
+	allocs := testing.AllocsPerRun(100, func() { _ = msg.Summary() })
+	require.True(t, allocs <= 2, "too many allocations: %v", allocs)
+}
+
+func TestMessageValidate(t *testing.T) {
+	cid := &OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6})}
+	sid := &OptServerId{Sid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{6, 5, 4, 3, 2, 1})}
+
+	solicit := DHCPv6Message{messageType: SOLICIT}
+	solicit.AddOption(cid)
+	require.NoError(t, solicit.Validate())
+
+	// a solicit must not carry a server ID
+	solicit.AddOption(sid)
+	err := solicit.Validate()
+	require.Error(t, err)
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok)
+	require.Equal(t, ValidationErrors{{MessageType: SOLICIT, Option: OPTION_SERVERID}}, errs)
+	require.Equal(t, "Invalid message: SOLICIT must not include OPTION_SERVERID", err.Error())
+
+	// all the violations are reported
+	request := DHCPv6Message{messageType: REQUEST}
+	err = request.Validate()
+	require.Equal(t, ValidationErrors{
+		{MessageType: REQUEST, Option: OPTION_CLIENTID, Missing: true},
+		{MessageType: REQUEST, Option: OPTION_SERVERID, Missing: true},
+	}, err)
+
+	reply := DHCPv6Message{messageType: REPLY}
+	reply.AddOption(sid)
+	require.NoError(t, reply.Validate())
+
+	infoRequest := DHCPv6Message{messageType: INFORMATION_REQUEST}
+	infoRequest.AddOption(&OptIANA{})
+	require.Error(t, infoRequest.Validate())
+
+	// no rules for unknown types
+	unknown := DHCPv6Message{messageType: MessageType(100)}
+	require.NoError(t, unknown.Validate())
+
+	// invalid options are reported, for all types
+	unknown.AddOption(&OptDNSRecursiveNameServer{NameServers: []net.IP{nil}})
+	err = unknown.Validate()
+	require.Error(t, err)
+	errs, ok = err.(ValidationErrors)
+	require.True(t, ok)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, DNS_RECURSIVE_NAME_SERVER, errs[0].Option)
+	require.Error(t, errs[0].Err)
+
+	solicit = DHCPv6Message{messageType: SOLICIT}
+	solicit.AddOption(cid)
+	solicit.AddOption(NewOptIANA(IAID{1, 2, 3, 4}, &OptIAAddress{IPv6Addr: net.IPv4(192, 0, 2, 1).To4()}))
+	err = solicit.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "SOLICIT has an invalid OPTION_IA_NA")
+}
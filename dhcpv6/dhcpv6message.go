@@ -5,8 +5,12 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/insomniacslk/dhcp/iana"
@@ -17,7 +21,46 @@ const MessageHeaderSize = 4
 type DHCPv6Message struct {
 	messageType   MessageType
 	transactionID uint32 // only 24 bits are used though
-	options       []Option
+	options       Options
+}
+
+// MessageFromBytes parses a non-relay DHCPv6 message from a sequence of bytes.
+// Relay messages have a different header layout, and must be parsed with
+// RelayMessageFromBytes instead. Messages of unknown type are rejected, see
+// MessageFromBytesMode.
+func MessageFromBytes(data []byte) (*DHCPv6Message, error) {
+	return MessageFromBytesMode(data, true)
+}
+
+// MessageFromBytesMode parses a non-relay DHCPv6 message like
+// MessageFromBytes. If strict is false, messages of unknown type are accepted
+// too, which is useful to experiment with new message types.
+func MessageFromBytesMode(data []byte, strict bool) (*DHCPv6Message, error) {
+	if len(data) < MessageHeaderSize {
+		return nil, fmt.Errorf("Invalid header size: shorter than %v bytes", MessageHeaderSize)
+	}
+	messageType := MessageType(data[0])
+	if messageType == RELAY_FORW || messageType == RELAY_REPL {
+		return nil, fmt.Errorf("Invalid message type %v: relay messages must be parsed with RelayMessageFromBytes",
+			MessageTypeToString(messageType))
+	}
+	if strict && !messageType.IsValid() {
+		return nil, fmt.Errorf("Invalid message type %d: unknown message type", data[0])
+	}
+	tid, err := BytesToTransactionID(data[1:4])
+	if err != nil {
+		return nil, err
+	}
+	d := DHCPv6Message{
+		messageType:   messageType,
+		transactionID: *tid,
+	}
+	options, err := OptionsFromBytes(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	d.options = options
+	return &d, nil
 }
 
 func BytesToTransactionID(data []byte) (*uint32, error) {
@@ -34,35 +77,77 @@ func BytesToTransactionID(data []byte) (*uint32, error) {
 	return &tid, nil
 }
 
-func GenerateTransactionID() (*uint32, error) {
-	var tid *uint32
-	for {
-		tidBytes := make([]byte, 4)
-		n, err := rand.Read(tidBytes)
-		if n != 4 {
-			return nil, fmt.Errorf("Invalid random sequence: shorter than 4 bytes")
-		}
-		tid, err = BytesToTransactionID(tidBytes)
-		if err != nil {
-			return nil, err
+// RandReader is the source of the random transaction IDs and IAIDs generated
+// by the message builders. Tests can replace it with a deterministic reader
+// to get reproducible messages. Reads are serialized, so the reader does not
+// need to be safe for concurrent use; RandReader itself must not be replaced
+// while messages are being built.
+var RandReader io.Reader = rand.Reader
+
+// randMu serializes the reads from RandReader
+var randMu sync.Mutex
+
+// readRand fills b with bytes from RandReader
+func readRand(b []byte) error {
+	randMu.Lock()
+	defer randMu.Unlock()
+	_, err := io.ReadFull(RandReader, b)
+	return err
+}
+
+// maxTransactionIDAttempts is the number of random values GenerateTransactionID
+// reads before giving up, in case RandReader only returns zeros
+const maxTransactionIDAttempts = 10
+
+// GenerateTransactionID returns a random, nonzero 3-byte transaction ID. It
+// returns an error if RandReader returns zero maxTransactionIDAttempts times in
+// a row.
+func GenerateTransactionID() ([3]byte, error) {
+	var tid [3]byte
+	// retry until != 0
+	for attempt := 0; attempt < maxTransactionIDAttempts; attempt++ {
+		if err := readRand(tid[:]); err != nil {
+			return tid, err
 		}
-		if tid == nil {
-			return nil, fmt.Errorf("Error: got a nil Transaction ID")
+		if tid != [3]byte{} {
+			return tid, nil
 		}
-		// retry until != 0
-		// TODO add retry limit
-		if *tid != 0 {
-			break
+	}
+	return tid, fmt.Errorf("Could not generate a nonzero transaction ID in %d attempts", maxTransactionIDAttempts)
+}
+
+// responseTypes maps the message types sent by a client to the types of the
+// valid responses from a server
+var responseTypes = map[MessageType][]MessageType{
+	SOLICIT:             {ADVERTISE, REPLY}, // REPLY with rapid commit
+	REQUEST:             {REPLY},
+	CONFIRM:             {REPLY},
+	RENEW:               {REPLY},
+	REBIND:              {REPLY},
+	RELEASE:             {REPLY},
+	DECLINE:             {REPLY},
+	INFORMATION_REQUEST: {REPLY},
+	LEASEQUERY:          {LEASEQUERY_REPLY},
+}
+
+// Matches returns true if resp is a response to req, i.e. they have the same
+// transaction ID and resp has a valid response type for req
+func Matches(req, resp *DHCPv6Message) bool {
+	if req == nil || resp == nil || req.TransactionID() != resp.TransactionID() {
+		return false
+	}
+	for _, t := range responseTypes[req.Type()] {
+		if resp.Type() == t {
+			return true
 		}
 	}
-	return tid, nil
+	return false
 }
 
 // GetTime returns a time integer suitable for DUID-LLT, i.e. the current time counted
 // in seconds since January 1st, 2000, midnight UTC, modulo 2^32
 func GetTime() uint32 {
-	now := time.Since(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
-	return uint32((now.Nanoseconds() / 1000000000) % 0xffffffff)
+	return duidTime(time.Now())
 }
 
 // NewSolicitForInterface creates a new SOLICIT message with DUID-LLT, using the
@@ -108,6 +193,61 @@ func NewSolicitForInterface(ifname string, modifiers ...Modifier) (DHCPv6, error
 	return d, nil
 }
 
+// NewSolicit creates a new SOLICIT message with a DUID-LL client ID built from
+// the given hardware address, a zero elapsed time option and an IA_NA with a
+// random IAID.
+func NewSolicit(hwaddr net.HardwareAddr, modifiers ...Modifier) (DHCPv6, error) {
+	d, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	d.(*DHCPv6Message).SetMessage(SOLICIT)
+	duid := Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HwTypeEthernet,
+		LinkLayerAddr: hwaddr,
+	}
+	d.AddOption(&OptClientId{Cid: duid})
+	d.AddOption(&OptElapsedTime{})
+	iaNa := OptIANA{}
+	if err := readRand(iaNa.IaId[:]); err != nil {
+		return nil, err
+	}
+	d.AddOption(&iaNa)
+
+	// apply modifiers
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// NewLeasequeryByAddress creates a new LEASEQUERY message asking for the
+// lease of the given address. The requestor must identify itself with a
+// client ID option, which can be added with the modifiers.
+func NewLeasequeryByAddress(addr net.IP, modifiers ...Modifier) (DHCPv6, error) {
+	if addr.To16() == nil || addr.To4() != nil {
+		return nil, fmt.Errorf("Invalid leasequery address %v: not an IPv6 address", addr)
+	}
+	d, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	d.(*DHCPv6Message).SetMessage(LEASEQUERY)
+	query := OptLQQuery{
+		QueryType:   QUERY_BY_ADDRESS,
+		LinkAddress: net.IPv6unspecified,
+		Options:     Options{&OptIAAddress{IPv6Addr: addr}},
+	}
+	d.AddOption(&query)
+
+	// apply modifiers
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
 // NewAdvertiseFromSolicit creates a new ADVERTISE packet based on an SOLICIT packet.
 func NewAdvertiseFromSolicit(solicit DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
 	if solicit == nil {
@@ -184,7 +324,6 @@ func NewRequestFromAdvertise(advertise DHCPv6, modifiers ...Modifier) (DHCPv6, e
 	})
 	req.AddOption(&oro)
 	// add OPTION_VENDOR_CLASS, only if present in the original request
-	// TODO implement OptionVendorClass
 	vClass := adv.GetOneOption(OPTION_VENDOR_CLASS)
 	if vClass != nil {
 		req.AddOption(vClass)
@@ -340,58 +479,191 @@ func (d *DHCPv6Message) SetOptions(options []Option) {
 }
 
 func (d *DHCPv6Message) AddOption(option Option) {
-	d.options = append(d.options, option)
+	d.options.Add(option)
 }
 
 func (d *DHCPv6Message) UpdateOption(option Option) {
-	for idx, opt := range d.options {
-		if opt.Code() == option.Code() {
-			d.options[idx] = option
-			// don't look further
-			return
+	d.options.Update(option)
+}
+
+// String returns a multi-line representation of the message, with one option
+// per line. Nested options and relay messages are indented.
+func (d *DHCPv6Message) String() string {
+	return strings.Join(messageLines(d, "", 0), "\n")
+}
+
+// SucceededOrError checks the status codes in the message, both at the top
+// level and within the IA_NA and IA_PD options. It returns nil if they are
+// all successful, or absent, and a StatusErrors listing the failed ones
+// otherwise.
+func (d *DHCPv6Message) SucceededOrError() error {
+	var errs StatusErrors
+	if sc := d.options.Status(); sc != nil && sc.StatusCode != iana.StatusSuccess {
+		errs = append(errs, &StatusError{StatusCode: sc.StatusCode, StatusMessage: string(sc.StatusMessage)})
+	}
+	for _, opt := range d.options {
+		var (
+			iaid    IAID
+			options Options
+		)
+		switch o := opt.(type) {
+		case *OptIANA:
+			iaid, options = o.IaId, o.Options
+		case *OptIAForPrefixDelegation:
+			iaid, options = o.IAID(), o.Options()
+		default:
+			continue
+		}
+		if sc := options.Status(); sc != nil && sc.StatusCode != iana.StatusSuccess {
+			errs = append(errs, &StatusError{
+				Option:        opt.Code(),
+				IAID:          iaid,
+				StatusCode:    sc.StatusCode,
+				StatusMessage: string(sc.StatusMessage),
+			})
 		}
 	}
-	// if not found, add it
-	d.AddOption(option)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
-func (d *DHCPv6Message) String() string {
-	return fmt.Sprintf("DHCPv6Message(messageType=%v transactionID=0x%06x, %d options)",
-		d.MessageTypeToString(), d.TransactionID(), len(d.options),
-	)
+// optionRules lists the options that must, and must not, appear in a message
+type optionRules struct {
+	required  []OptionCode
+	forbidden []OptionCode
 }
 
-func (d *DHCPv6Message) Summary() string {
-	ret := fmt.Sprintf(
-		"DHCPv6Message\n"+
-			"  messageType=%v\n"+
-			"  transactionid=0x%06x\n",
-		d.MessageTypeToString(),
-		d.TransactionID(),
-	)
-	ret += "  options=["
-	if len(d.options) > 0 {
-		ret += "\n"
+// messageOptionRules maps message types to the options whose presence or
+// absence makes the receiver discard them, see RFC 8415, section 16,
+// RFC 5007, section 4.2 for leasequery and RFC 7341, section 5 for
+// DHCPv4-over-DHCPv6
+var messageOptionRules = map[MessageType]optionRules{
+	SOLICIT:             {required: []OptionCode{OPTION_CLIENTID}, forbidden: []OptionCode{OPTION_SERVERID}},
+	ADVERTISE:           {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	REQUEST:             {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	CONFIRM:             {required: []OptionCode{OPTION_CLIENTID}, forbidden: []OptionCode{OPTION_SERVERID}},
+	RENEW:               {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	REBIND:              {required: []OptionCode{OPTION_CLIENTID}, forbidden: []OptionCode{OPTION_SERVERID}},
+	REPLY:               {required: []OptionCode{OPTION_SERVERID}},
+	RELEASE:             {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	DECLINE:             {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	RECONFIGURE:         {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID, OPTION_RECONF_MSG, OPTION_AUTH}},
+	INFORMATION_REQUEST: {forbidden: []OptionCode{OPTION_IA_NA, OPTION_IA_TA, OPTION_IA_PD}},
+	LEASEQUERY:          {required: []OptionCode{OPTION_CLIENTID, OPTION_LQ_QUERY}},
+	LEASEQUERY_REPLY:    {required: []OptionCode{OPTION_CLIENTID, OPTION_SERVERID}},
+	DHCPV4_QUERY:        {required: []OptionCode{OPTION_DHCPV4_MSG}},
+	DHCPV4_RESPONSE:     {required: []OptionCode{OPTION_DHCPV4_MSG}},
+}
+
+// Validate checks that the message carries the options required for its
+// type, none of the forbidden ones, and that its options are valid, see
+// ValidOption. It returns a ValidationErrors listing every violation, or nil
+// if there is none. Messages of types without rules are only checked for
+// invalid options.
+func (d *DHCPv6Message) Validate() error {
+	var errs ValidationErrors
+	for _, opt := range d.options {
+		if err := ValidOption(opt); err != nil {
+			errs = append(errs, &ValidationError{MessageType: d.messageType, Option: opt.Code(), Err: err})
+		}
+	}
+	rules := messageOptionRules[d.messageType]
+	for _, code := range rules.required {
+		if d.GetOneOption(code) == nil {
+			errs = append(errs, &ValidationError{MessageType: d.messageType, Option: code, Missing: true})
+		}
 	}
+	for _, code := range rules.forbidden {
+		if d.GetOneOption(code) != nil {
+			errs = append(errs, &ValidationError{MessageType: d.messageType, Option: code})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Summary returns a compact, single-line description of the message for
+// logging, e.g. "SOLICIT xid=0x1a2b3c cid=DUID-LL(aa:bb:cc:dd:ee:ff) ia_na=1
+// ia_pd=0". It is meant to be called for every packet, so it avoids
+// fmt and intermediate allocations.
+func (d *DHCPv6Message) Summary() string {
+	buf := make([]byte, 0, 96)
+	buf = append(buf, d.MessageTypeToString()...)
+	buf = append(buf, " xid=0x"...)
+	buf = appendHex(buf, []byte{byte(d.transactionID >> 16), byte(d.transactionID >> 8), byte(d.transactionID)}, 0)
+	var iaNA, iaPD int
 	for _, opt := range d.options {
-		ret += fmt.Sprintf("    %v\n", opt.String())
+		switch o := opt.(type) {
+		case *OptClientId:
+			buf = append(buf, " cid="...)
+			buf = appendDuidSummary(buf, &o.Cid)
+		case *OptIANA:
+			iaNA++
+		case *OptIAForPrefixDelegation:
+			iaPD++
+		}
 	}
-	ret += "  ]\n"
-	return ret
+	buf = append(buf, " ia_na="...)
+	buf = strconv.AppendInt(buf, int64(iaNA), 10)
+	buf = append(buf, " ia_pd="...)
+	buf = strconv.AppendInt(buf, int64(iaPD), 10)
+	return string(buf)
+}
+
+// appendDuidSummary appends a compact representation of a DUID to buf, e.g.
+// "DUID-LL(aa:bb:cc:dd:ee:ff)"
+func appendDuidSummary(buf []byte, d *Duid) []byte {
+	if name, ok := DuidTypeToString[d.Type]; ok {
+		buf = append(buf, name...)
+	} else {
+		buf = append(buf, "DUID-"...)
+		buf = strconv.AppendUint(buf, uint64(d.Type), 10)
+	}
+	buf = append(buf, '(')
+	switch d.Type {
+	case DUID_LLT, DUID_LL:
+		buf = appendHex(buf, d.LinkLayerAddr, ':')
+	case DUID_EN:
+		buf = strconv.AppendUint(buf, uint64(d.EnterpriseNumber), 10)
+		buf = append(buf, ':')
+		buf = appendHex(buf, d.EnterpriseIdentifier, 0)
+	case DUID_UUID:
+		buf = appendHex(buf, d.Uuid, 0)
+	default:
+		buf = appendHex(buf, d.Opaque, 0)
+	}
+	return append(buf, ')')
+}
+
+// appendHex appends the hex representation of data to buf, with the bytes
+// separated by sep unless it is zero
+func appendHex(buf []byte, data []byte, sep byte) []byte {
+	const digits = "0123456789abcdef"
+	for i, b := range data {
+		if i > 0 && sep != 0 {
+			buf = append(buf, sep)
+		}
+		buf = append(buf, digits[b>>4], digits[b&0x0f])
+	}
+	return buf
 }
 
 // Convert a DHCPv6Message structure into its binary representation, suitable for being
 // sent over the network
 func (d *DHCPv6Message) ToBytes() []byte {
-	var ret []byte
-	ret = append(ret, byte(d.messageType))
-	tidBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(tidBytes, d.transactionID)
-	ret = append(ret, tidBytes[1:4]...) // discard the first byte
-	for _, opt := range d.options {
-		ret = append(ret, opt.ToBytes()...)
-	}
-	return ret
+	return MessageToBytes(d)
+}
+
+// SerializeTo writes the message to a Serializer
+func (d *DHCPv6Message) SerializeTo(s *Serializer) {
+	s.Write8(byte(d.messageType))
+	s.Write8(byte(d.transactionID >> 16))
+	s.Write16(uint16(d.transactionID))
+	s.WriteOptions(d.options)
 }
 
 func (d *DHCPv6Message) Length() int {
@@ -407,11 +679,11 @@ func (d *DHCPv6Message) Options() []Option {
 }
 
 func (d *DHCPv6Message) GetOption(code OptionCode) []Option {
-	return getOptions(d.options, code, false)
+	return d.options.Get(code)
 }
 
 func (d *DHCPv6Message) GetOneOption(code OptionCode) Option {
-	return getOption(d.options, code)
+	return d.options.GetOne(code)
 }
 
 func (d *DHCPv6Message) IsRelay() bool {
@@ -4,16 +4,92 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 )
 
 const RelayHeaderSize = 34
 
+// HopCountLimit is the maximum hop count of a relay message, as defined by
+// RFC 8415, section 7.6. Relay agents discard messages whose hop count
+// reached the limit instead of relaying them.
+const HopCountLimit = 8
+
+// MaxRelayDepth is the maximum number of nested relay messages accepted when
+// parsing. Deeper messages are rejected with ErrRelayDepthExceeded.
+var MaxRelayDepth = 32
+
 type DHCPv6Relay struct {
 	messageType MessageType
 	hopCount    uint8
 	linkAddr    net.IP
 	peerAddr    net.IP
-	options     []Option
+	options     Options
+}
+
+// RelayMessageFromBytes parses a RELAY_FORW or RELAY_REPL message from a
+// sequence of bytes. All the options are kept in order, including the ones
+// following the OPTION_RELAY_MSG, so that the message is serialized back
+// unchanged.
+func RelayMessageFromBytes(data []byte) (*DHCPv6Relay, error) {
+	return relayMessageFromBytes(data, 1, true)
+}
+
+// RelayMessageFromBytesMode parses a relay message like
+// RelayMessageFromBytes. If strict is false, the 1 to 3 bytes of padding that
+// some relay agents append after the last option are ignored, and are dropped
+// when the message is serialized back. Nested relay messages are always
+// parsed strictly.
+func RelayMessageFromBytesMode(data []byte, strict bool) (*DHCPv6Relay, error) {
+	return relayMessageFromBytes(data, 1, strict)
+}
+
+// RelayMessageFromBytesLenient parses a relay message like
+// RelayMessageFromBytesMode with strict set to false, but keeps the options
+// that cannot be parsed as OptionGeneric and returns their errors, like
+// OptionsFromBytesMode. It returns a nil message if the header cannot be
+// parsed. Nested relay messages are still parsed strictly: an invalid one
+// makes its whole OPTION_RELAY_MSG an OptionGeneric.
+func RelayMessageFromBytesLenient(data []byte) (*DHCPv6Relay, []error) {
+	return parseRelayMessage(data, 1)
+}
+
+// relayMessageFromBytes parses a relay message nested at the given depth,
+// the outermost message being at depth 1
+func relayMessageFromBytes(data []byte, depth int, strict bool) (*DHCPv6Relay, error) {
+	d, errs := parseRelayMessage(data, depth)
+	for _, err := range errs {
+		if perr, ok := err.(*OptionParseError); ok && perr.Err == ErrTrailingData && !strict {
+			continue
+		}
+		return nil, err
+	}
+	// TODO fail if no OptRelayMessage is present
+	return d, nil
+}
+
+// parseRelayMessage implements RelayMessageFromBytesLenient for a relay
+// message nested at the given depth
+func parseRelayMessage(data []byte, depth int) (*DHCPv6Relay, []error) {
+	if depth > MaxRelayDepth {
+		return nil, []error{ErrRelayDepthExceeded}
+	}
+	if len(data) < RelayHeaderSize {
+		return nil, []error{fmt.Errorf("Invalid header size: shorter than %v bytes", RelayHeaderSize)}
+	}
+	messageType := MessageType(data[0])
+	if messageType != RELAY_FORW && messageType != RELAY_REPL {
+		return nil, []error{fmt.Errorf("Invalid message type %v: not a relay message",
+			MessageTypeToString(messageType))}
+	}
+	d := DHCPv6Relay{
+		messageType: messageType,
+		hopCount:    uint8(data[1]),
+	}
+	d.linkAddr = append(net.IP(nil), data[2:18]...)
+	d.peerAddr = append(net.IP(nil), data[18:34]...)
+	options, errs := optionsFromBytes(data[34:], false, depth, MaxOptions)
+	d.options = options
+	return &d, errs
 }
 
 func (r *DHCPv6Relay) Type() MessageType {
@@ -24,12 +100,10 @@ func (r *DHCPv6Relay) MessageTypeToString() string {
 	return MessageTypeToString(r.messageType)
 }
 
+// String returns a multi-line representation of the relay message, with one
+// option per line. Nested options and relay messages are indented.
 func (r *DHCPv6Relay) String() string {
-	ret := fmt.Sprintf(
-		"DHCPv6Relay(messageType=%v hopcount=%v, linkaddr=%v, peeraddr=%v, %d options)",
-		r.MessageTypeToString(), r.hopCount, r.linkAddr, r.peerAddr, len(r.options),
-	)
-	return ret
+	return strings.Join(messageLines(r, "", 0), "\n")
 }
 
 func (r *DHCPv6Relay) Summary() string {
@@ -50,16 +124,18 @@ func (r *DHCPv6Relay) Summary() string {
 }
 
 func (r *DHCPv6Relay) ToBytes() []byte {
-	ret := make([]byte, RelayHeaderSize)
-	ret[0] = byte(r.messageType)
-	ret[1] = byte(r.hopCount)
-	copy(ret[2:18], r.linkAddr)
-	copy(ret[18:34], r.peerAddr)
-	for _, opt := range r.options {
-		ret = append(ret, opt.ToBytes()...)
-	}
+	return MessageToBytes(r)
+}
 
-	return ret
+// SerializeTo writes the relay message to a Serializer
+func (r *DHCPv6Relay) SerializeTo(s *Serializer) {
+	var addrs [32]byte
+	s.Write8(byte(r.messageType))
+	s.Write8(r.hopCount)
+	copy(addrs[0:16], r.linkAddr)
+	copy(addrs[16:32], r.peerAddr)
+	s.WriteBytes(addrs[:])
+	s.WriteOptions(r.options)
 }
 
 func (r *DHCPv6Relay) MessageType() MessageType {
@@ -107,11 +183,11 @@ func (r *DHCPv6Relay) Options() []Option {
 	return r.options
 }
 func (r *DHCPv6Relay) GetOption(code OptionCode) []Option {
-	return getOptions(r.options, code, false)
+	return r.options.Get(code)
 }
 
 func (r *DHCPv6Relay) GetOneOption(code OptionCode) Option {
-	return getOption(r.options, code)
+	return r.options.GetOne(code)
 }
 
 func (r *DHCPv6Relay) SetOptions(options []Option) {
@@ -119,26 +195,29 @@ func (r *DHCPv6Relay) SetOptions(options []Option) {
 }
 
 func (r *DHCPv6Relay) AddOption(option Option) {
-	r.options = append(r.options, option)
+	r.options.Add(option)
 }
 
 // UpdateOption replaces the first option of the same type as the specified one.
 func (r *DHCPv6Relay) UpdateOption(option Option) {
-	for idx, opt := range r.options {
-		if opt.Code() == option.Code() {
-			r.options[idx] = option
-			// don't look further
-			return
-		}
-	}
-	// if not found, add it
-	r.AddOption(option)
+	r.options.Update(option)
 }
 
 func (r *DHCPv6Relay) IsRelay() bool {
 	return true
 }
 
+// RelayMessageOption returns the first OPTION_RELAY_MSG option, or nil if
+// there is none
+func (r *DHCPv6Relay) RelayMessageOption() *OptRelayMsg {
+	var relayMsg *OptRelayMsg
+	r.options.ForEach(OPTION_RELAY_MSG, func(opt Option) bool {
+		relayMsg, _ = opt.(*OptRelayMsg)
+		return relayMsg == nil
+	})
+	return relayMsg
+}
+
 // Recurse into a relay message and extract and return the inner DHCPv6Message.
 // Return nil if none found (e.g. not a relay message).
 func (d *DHCPv6Relay) GetInnerMessage() (DHCPv6, error) {
@@ -182,6 +261,44 @@ func (r *DHCPv6Relay) GetInnerPeerAddr() (net.IP, error) {
 	return addr, nil
 }
 
+// RequestedOptions returns the option codes requested by the relay agents
+// along the relay chain, from the outermost relay message to the innermost
+// one, without duplicates. These are the codes listed in the ECHO_REQUEST
+// options, which the server must echo back in the RELAY_REPL messages, and in
+// the OPTION_ORO options inserted by the relay agents. The options of the
+// client message are not included.
+func (r *DHCPv6Relay) RequestedOptions() []OptionCode {
+	var (
+		codes []OptionCode
+		seen  = make(map[OptionCode]bool)
+	)
+	add := func(requested []OptionCode) {
+		for _, code := range requested {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	var p DHCPv6 = r
+	for depth := 0; depth <= MaxRelayDepth && p != nil && p.IsRelay(); depth++ {
+		for _, opt := range p.Options() {
+			switch o := opt.(type) {
+			case *OptEchoRequest:
+				add(o.Options)
+			case *OptRequestedOption:
+				add(o.RequestedOptions())
+			}
+		}
+		inner, err := DecapsulateRelay(p)
+		if err != nil {
+			break
+		}
+		p = inner
+	}
+	return codes
+}
+
 // NewRelayReplFromRelayForw creates a RELAY_REPL packet based on a RELAY_FORW
 // packet and replaces the inner message with the passed DHCPv6 message.
 func NewRelayReplFromRelayForw(relayForw, msg DHCPv6) (DHCPv6, error) {
@@ -231,3 +348,39 @@ func NewRelayReplFromRelayForw(relayForw, msg DHCPv6) (DHCPv6, error) {
 	}
 	return msg, nil
 }
+
+// NewRelayForw creates a RELAY_FORW message carrying the passed message, which
+// is parsed and stored in an OPTION_RELAY_MSG, followed by the options the
+// relay agent inserts, e.g. OptInterfaceId or OptRemoteId. hopCount is the hop
+// count of the new relay message when inner is a client message. When inner
+// is itself a RELAY_FORW, the hop count is the one of inner incremented by
+// one, and an error is returned if inner already reached HopCountLimit.
+func NewRelayForw(inner []byte, linkAddr, peerAddr net.IP, hopCount uint8, extra ...Option) (*DHCPv6Relay, error) {
+	msg, err := FromBytes(inner)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Type() == RELAY_REPL {
+		return nil, errors.New("The passed message cannot be a RELAY_REPL")
+	}
+	if msg.IsRelay() {
+		innerHops := msg.(*DHCPv6Relay).HopCount()
+		if innerHops >= HopCountLimit {
+			return nil, fmt.Errorf("Hop count %v of the passed message reached the limit of %v", innerHops, HopCountLimit)
+		}
+		hopCount = innerHops + 1
+	} else if hopCount > HopCountLimit {
+		return nil, fmt.Errorf("Hop count %v exceeds the limit of %v", hopCount, HopCountLimit)
+	}
+	relay := DHCPv6Relay{
+		messageType: RELAY_FORW,
+		hopCount:    hopCount,
+		linkAddr:    linkAddr,
+		peerAddr:    peerAddr,
+	}
+	relay.AddOption(&OptRelayMsg{relayMessage: msg})
+	for _, opt := range extra {
+		relay.AddOption(opt)
+	}
+	return &relay, nil
+}
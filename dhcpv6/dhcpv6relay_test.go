@@ -2,7 +2,9 @@ package dhcpv6
 
 import (
 	"bytes"
+	"errors"
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -94,9 +96,7 @@ func TestDHCPv6RelayToBytes(t *testing.T) {
 			messageType:   SOLICIT,
 			transactionID: 0xaabbcc,
 			options: []Option{
-				&OptElapsedTime{
-					ElapsedTime: 0,
-				},
+				&OptElapsedTime{elapsedTime: 0},
 			},
 		},
 	}
@@ -138,3 +138,269 @@ func TestNewRelayRepFromRelayForw(t *testing.T) {
 	rr, err = NewRelayReplFromRelayForw(&rf, nil)
 	require.Error(t, err)
 }
+
+func TestRelayMessageFromBytes(t *testing.T) {
+	inner := DHCPv6Message{}
+	inner.SetMessage(SOLICIT)
+	inner.SetTransactionID(0xabcdef)
+	relay, err := EncapsulateRelay(&inner, RELAY_FORW, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	data := relay.ToBytes()
+
+	r, err := RelayMessageFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, RELAY_FORW, r.Type())
+	require.Equal(t, uint8(0), r.HopCount())
+	require.True(t, net.IPv6loopback.Equal(r.LinkAddr()))
+	require.True(t, net.IPv6linklocalallnodes.Equal(r.PeerAddr()))
+	require.Equal(t, data, r.ToBytes())
+
+	msg, err := r.GetInnerMessage()
+	require.NoError(t, err)
+	require.Equal(t, SOLICIT, msg.Type())
+}
+
+func TestRelayMessageFromBytesInvalid(t *testing.T) {
+	// short header
+	_, err := RelayMessageFromBytes([]byte{byte(RELAY_FORW), 0})
+	require.Error(t, err)
+
+	// not a relay message
+	data := make([]byte, RelayHeaderSize)
+	data[0] = byte(SOLICIT)
+	_, err = RelayMessageFromBytes(data)
+	require.Error(t, err)
+}
+
+func TestRelayMessageGetInnerMessageNoRelayMsg(t *testing.T) {
+	data := make([]byte, RelayHeaderSize)
+	data[0] = byte(RELAY_FORW)
+	r, err := RelayMessageFromBytes(data)
+	require.NoError(t, err)
+	_, err = r.GetInnerMessage()
+	require.Error(t, err)
+}
+
+// nestedRelay returns a message wrapped in depth RELAY_FORW messages
+func nestedRelay(depth int) []byte {
+	msg := []byte{byte(SOLICIT), 0xaa, 0xbb, 0xcc}
+	for i := 0; i < depth; i++ {
+		relay := make([]byte, RelayHeaderSize, RelayHeaderSize+4+len(msg))
+		relay[0] = byte(RELAY_FORW)
+		relay = append(relay, 0, byte(OPTION_RELAY_MSG), byte(len(msg)>>8), byte(len(msg)))
+		msg = append(relay, msg...)
+	}
+	return msg
+}
+
+func TestRelayMessageFromBytesMaxDepth(t *testing.T) {
+	d, err := FromBytes(nestedRelay(MaxRelayDepth))
+	require.NoError(t, err)
+	inner, err := d.(*DHCPv6Relay).GetInnerMessage()
+	require.NoError(t, err)
+	require.Equal(t, SOLICIT, inner.Type())
+
+	_, err = FromBytes(nestedRelay(MaxRelayDepth + 1))
+	require.True(t, errors.Is(err, ErrRelayDepthExceeded), "unexpected error %v", err)
+
+	// way beyond the limit, must fail cleanly without exhausting the stack
+	_, err = FromBytes(nestedRelay(1500))
+	require.True(t, errors.Is(err, ErrRelayDepthExceeded), "unexpected error %v", err)
+}
+
+func TestNewRelayForwChain(t *testing.T) {
+	solicit, err := NewMessage()
+	require.NoError(t, err)
+	ifaceID := &OptInterfaceId{}
+	ifaceID.SetInterfaceID([]byte("eth0"))
+
+	first, err := NewRelayForw(solicit.ToBytes(), net.ParseIP("2001:db8:1::1"), net.ParseIP("fe80::1"), 0, ifaceID)
+	require.NoError(t, err)
+	require.Equal(t, RELAY_FORW, first.Type())
+	require.Equal(t, uint8(0), first.HopCount())
+	require.Equal(t, ifaceID, first.GetOneOption(OPTION_INTERFACE_ID))
+
+	remoteID := &OptRemoteId{}
+	remoteID.SetEnterpriseNumber(0x137)
+	remoteID.SetRemoteID([]byte("port1"))
+	second, err := NewRelayForw(first.ToBytes(), net.ParseIP("2001:db8:2::1"), net.ParseIP("2001:db8:1::1"), 0, remoteID)
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), second.HopCount())
+	require.NotNil(t, second.GetOneOption(OPTION_REMOTE_ID))
+
+	// the chain survives a round trip and unwraps one layer at a time
+	parsed, err := FromBytes(second.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, second.ToBytes(), parsed.ToBytes())
+	decap, err := DecapsulateRelay(parsed)
+	require.NoError(t, err)
+	require.True(t, decap.IsRelay())
+	require.Equal(t, first.ToBytes(), decap.ToBytes())
+	decap, err = DecapsulateRelay(decap)
+	require.NoError(t, err)
+	require.False(t, decap.IsRelay())
+	require.Equal(t, solicit.ToBytes(), decap.ToBytes())
+	peer, err := second.GetInnerPeerAddr()
+	require.NoError(t, err)
+	require.True(t, peer.Equal(net.ParseIP("fe80::1")))
+}
+
+func TestNewRelayForwHopCountLimit(t *testing.T) {
+	solicit, err := NewMessage()
+	require.NoError(t, err)
+	_, err = NewRelayForw(solicit.ToBytes(), net.IPv6unspecified, net.IPv6unspecified, HopCountLimit+1)
+	require.Error(t, err)
+
+	relay, err := NewRelayForw(solicit.ToBytes(), net.IPv6unspecified, net.IPv6unspecified, 0)
+	require.NoError(t, err)
+	for i := 1; i <= HopCountLimit; i++ {
+		relay, err = NewRelayForw(relay.ToBytes(), net.IPv6unspecified, net.IPv6unspecified, 0)
+		require.NoError(t, err)
+		require.Equal(t, uint8(i), relay.HopCount())
+	}
+	_, err = NewRelayForw(relay.ToBytes(), net.IPv6unspecified, net.IPv6unspecified, 0)
+	require.Error(t, err)
+
+	// invalid or RELAY_REPL inner messages
+	_, err = NewRelayForw(nil, net.IPv6unspecified, net.IPv6unspecified, 0)
+	require.Error(t, err)
+	repl, err := EncapsulateRelay(solicit, RELAY_REPL, net.IPv6unspecified, net.IPv6unspecified)
+	require.NoError(t, err)
+	_, err = NewRelayForw(repl.ToBytes(), net.IPv6unspecified, net.IPv6unspecified, 0)
+	require.Error(t, err)
+}
+
+func TestRelayStringChain(t *testing.T) {
+	msg := DHCPv6Message{messageType: SOLICIT, transactionID: 0x1a2b3c}
+	inner, err := EncapsulateRelay(&msg, RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	inner.AddOption(&OptInterfaceId{interfaceId: []byte("eth0")})
+	outer, err := EncapsulateRelay(inner, RELAY_FORW, net.ParseIP("2001:db8:1::1"), net.ParseIP("fe80::2"))
+	require.NoError(t, err)
+	expected := "DHCPv6Relay(messageType=RELAY-FORW hopcount=1 linkaddr=2001:db8:1::1 peeraddr=fe80::2)\n" +
+		"  OptRelayMsg\n" +
+		"    DHCPv6Relay(messageType=RELAY-FORW hopcount=0 linkaddr=2001:db8::1 peeraddr=fe80::1)\n" +
+		"      OptRelayMsg\n" +
+		"        DHCPv6Message(messageType=SOLICIT transactionID=0x1a2b3c)\n" +
+		"      OptInterfaceId{interfaceid=eth0}"
+	require.Equal(t, expected, outer.String())
+}
+
+func TestRelayStringCycle(t *testing.T) {
+	relay := DHCPv6Relay{messageType: RELAY_FORW}
+	relay.AddOption(&OptRelayMsg{relayMessage: &relay})
+	lines := strings.Split(relay.String(), "\n")
+	require.Equal(t, 2*(MaxRelayDepth+1)+1, len(lines))
+	require.Equal(t, "(relay messages nested too deep)", strings.TrimSpace(lines[len(lines)-1]))
+}
+
+func TestRelayRequestedOptions(t *testing.T) {
+	solicit, err := NewMessage()
+	require.NoError(t, err)
+	// the client's own ORO is not included
+	clientORO := &OptRequestedOption{}
+	clientORO.SetRequestedOptions([]OptionCode{DOMAIN_SEARCH_LIST})
+	solicit.AddOption(clientORO)
+
+	first, err := NewRelayForw(solicit.ToBytes(), net.ParseIP("2001:db8:1::1"), net.ParseIP("fe80::1"), 0,
+		&OptEchoRequest{Options: []OptionCode{OPTION_INTERFACE_ID, OPTION_REMOTE_ID}})
+	require.NoError(t, err)
+	oro := &OptRequestedOption{}
+	oro.SetRequestedOptions([]OptionCode{OPTION_CLIENT_LINKLAYER_ADDR})
+	second, err := NewRelayForw(first.ToBytes(), net.ParseIP("2001:db8:2::1"), net.ParseIP("2001:db8:1::1"), 0,
+		&OptEchoRequest{Options: []OptionCode{RELAY_AGENT_SUBSCRIBER_ID, OPTION_INTERFACE_ID}}, oro)
+	require.NoError(t, err)
+
+	// the options are surfaced by the parser
+	parsed, err := RelayMessageFromBytes(second.ToBytes())
+	require.NoError(t, err)
+	require.IsType(t, &OptEchoRequest{}, parsed.GetOneOption(ECHO_REQUEST))
+	require.IsType(t, &OptRequestedOption{}, parsed.GetOneOption(OPTION_ORO))
+
+	require.Equal(t,
+		[]OptionCode{RELAY_AGENT_SUBSCRIBER_ID, OPTION_INTERFACE_ID, OPTION_CLIENT_LINKLAYER_ADDR, OPTION_REMOTE_ID},
+		parsed.RequestedOptions(),
+	)
+	require.Equal(t, []OptionCode{OPTION_INTERFACE_ID, OPTION_REMOTE_ID}, first.RequestedOptions())
+
+	var empty DHCPv6Relay
+	require.Nil(t, empty.RequestedOptions())
+}
+
+func TestRelayMessageTrailingOptions(t *testing.T) {
+	inner := DHCPv6Message{messageType: SOLICIT, transactionID: 0xabcdef}
+	relay, err := EncapsulateRelay(&inner, RELAY_FORW, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	ifaceID := &OptInterfaceId{}
+	ifaceID.SetInterfaceID([]byte("eth0"))
+	relay.AddOption(ifaceID)
+	relay.AddOption(&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 2, 3}})
+	data := relay.ToBytes()
+
+	r, err := RelayMessageFromBytes(data)
+	require.NoError(t, err)
+	opts := r.Options()
+	require.Equal(t, 3, len(opts))
+	require.Equal(t, OPTION_RELAY_MSG, opts[0].Code())
+	require.Equal(t, OPTION_INTERFACE_ID, opts[1].Code())
+	require.Equal(t, OptionCode(0xfde9), opts[2].Code())
+	require.Equal(t, data, r.ToBytes())
+	require.NotNil(t, r.RelayMessageOption())
+	require.Equal(t, SOLICIT, r.RelayMessageOption().RelayMessage().Type())
+
+	// padding after the last option is only accepted in relaxed mode
+	padded := append(append([]byte(nil), data...), 0, 0)
+	_, err = RelayMessageFromBytes(padded)
+	require.True(t, errors.Is(err, ErrTrailingData), "unexpected error %v", err)
+	_, err = RelayMessageFromBytesMode(padded, true)
+	require.Error(t, err)
+	r, err = RelayMessageFromBytesMode(padded, false)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(r.Options()))
+	require.Equal(t, data, r.ToBytes())
+
+	// truncated options are rejected in both modes
+	_, err = RelayMessageFromBytesMode(data[:len(data)-1], false)
+	require.Error(t, err)
+}
+
+func TestRelayMessageFromBytesLenient(t *testing.T) {
+	inner := DHCPv6Message{messageType: SOLICIT, transactionID: 0xabcdef}
+	relay, err := EncapsulateRelay(&inner, RELAY_FORW, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	// an elapsed time option with 3 bytes of data
+	data := append(relay.ToBytes(), 0, 8, 0, 3, 0, 0, 0)
+
+	_, err = RelayMessageFromBytes(data)
+	require.Error(t, err)
+	r, errs := RelayMessageFromBytesLenient(data)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, 2, len(r.Options()))
+	_, ok := r.Options()[1].(*OptionGeneric)
+	require.True(t, ok)
+	require.Equal(t, data, r.ToBytes())
+
+	r, errs = RelayMessageFromBytesLenient(relay.ToBytes())
+	require.Empty(t, errs)
+	require.Equal(t, relay.ToBytes(), r.ToBytes())
+
+	r, errs = RelayMessageFromBytesLenient([]byte{byte(RELAY_FORW), 0})
+	require.Nil(t, r)
+	require.Equal(t, 1, len(errs))
+	r, errs = RelayMessageFromBytesLenient(nestedRelay(MaxRelayDepth + 1))
+	require.Equal(t, 1, len(errs))
+	require.True(t, errors.Is(errs[0], ErrRelayDepthExceeded), "unexpected error %v", errs[0])
+}
+
+func TestRelayMessageOption(t *testing.T) {
+	var r DHCPv6Relay
+	require.Nil(t, r.RelayMessageOption())
+	// an unparsed relay message option is skipped
+	r.AddOption(&OptionGeneric{OptionCode: OPTION_RELAY_MSG, OptionData: []byte{0xff}})
+	require.Nil(t, r.RelayMessageOption())
+	_, err := DecapsulateRelay(&r)
+	require.Error(t, err)
+	relayMsg := &OptRelayMsg{relayMessage: &DHCPv6Message{messageType: SOLICIT}}
+	r.AddOption(relayMsg)
+	require.Equal(t, relayMsg, r.RelayMessageOption())
+}
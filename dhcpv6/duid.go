@@ -1,9 +1,12 @@
 package dhcpv6
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/insomniacslk/dhcp/iana"
 )
@@ -36,6 +39,65 @@ type Duid struct {
 	Opaque               []byte // for unknown DUIDs
 }
 
+// duidEpoch is the origin of DUID-LLT times, see RFC 8415, section 11.2
+var duidEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// duidTime returns the DUID-LLT time of t, i.e. the number of seconds since
+// midnight UTC, January 1st 2000, modulo 2^32. The value wraps around in
+// 2136.
+func duidTime(t time.Time) uint32 {
+	return uint32(t.Unix() - duidEpoch.Unix())
+}
+
+// NewDuidLLT returns a DUID-LLT built from the given hardware type,
+// link-layer address and time, usually the current time
+func NewDuidLLT(hwType iana.HwTypeType, linkLayerAddr net.HardwareAddr, t time.Time) *Duid {
+	return &Duid{
+		Type:          DUID_LLT,
+		HwType:        hwType,
+		Time:          duidTime(t),
+		LinkLayerAddr: linkLayerAddr,
+	}
+}
+
+// NewDuidLL returns a DUID-LL built from the given hardware type and
+// link-layer address
+func NewDuidLL(hwType iana.HwTypeType, linkLayerAddr net.HardwareAddr) *Duid {
+	return &Duid{
+		Type:          DUID_LL,
+		HwType:        hwType,
+		LinkLayerAddr: linkLayerAddr,
+	}
+}
+
+// NewDuidEN returns a DUID-EN built from the given enterprise number and
+// identifier
+func NewDuidEN(enterpriseNumber uint32, enterpriseIdentifier []byte) *Duid {
+	return &Duid{
+		Type:                 DUID_EN,
+		EnterpriseNumber:     enterpriseNumber,
+		EnterpriseIdentifier: enterpriseIdentifier,
+	}
+}
+
+// NewDuidUUID returns a DUID-UUID built from the given 16-byte UUID
+func NewDuidUUID(uuid [16]byte) *Duid {
+	return &Duid{
+		Type: DUID_UUID,
+		Uuid: uuid[:],
+	}
+}
+
+// clone returns a deep copy of the DUID
+func (d *Duid) clone() Duid {
+	c := *d
+	c.LinkLayerAddr = append(net.HardwareAddr(nil), d.LinkLayerAddr...)
+	c.EnterpriseIdentifier = append([]byte(nil), d.EnterpriseIdentifier...)
+	c.Uuid = append([]byte(nil), d.Uuid...)
+	c.Opaque = append([]byte(nil), d.Opaque...)
+	return c
+}
+
 func (d *Duid) Length() int {
 	if d.Type == DUID_LLT {
 		return 8 + len(d.LinkLayerAddr)
@@ -51,42 +113,50 @@ func (d *Duid) Length() int {
 }
 
 func (d *Duid) ToBytes() []byte {
+	buf := make([]byte, 0, d.Length())
+	buf = appendUint16(buf, uint16(d.Type))
 	if d.Type == DUID_LLT {
-		buf := make([]byte, 8)
-		binary.BigEndian.PutUint16(buf[0:2], uint16(d.Type))
-		binary.BigEndian.PutUint16(buf[2:4], uint16(d.HwType))
-		binary.BigEndian.PutUint32(buf[4:8], d.Time)
+		buf = appendUint16(buf, uint16(d.HwType))
+		buf = appendUint32(buf, d.Time)
 		return append(buf, d.LinkLayerAddr...)
 	} else if d.Type == DUID_LL {
-		buf := make([]byte, 4)
-		binary.BigEndian.PutUint16(buf[0:2], uint16(d.Type))
-		binary.BigEndian.PutUint16(buf[2:4], uint16(d.HwType))
+		buf = appendUint16(buf, uint16(d.HwType))
 		return append(buf, d.LinkLayerAddr...)
 	} else if d.Type == DUID_EN {
-		buf := make([]byte, 6)
-		binary.BigEndian.PutUint16(buf[0:2], uint16(d.Type))
-		binary.BigEndian.PutUint32(buf[2:6], d.EnterpriseNumber)
+		buf = appendUint32(buf, d.EnterpriseNumber)
 		return append(buf, d.EnterpriseIdentifier...)
 	} else if d.Type == DUID_UUID {
-		buf := make([]byte, 2)
-		binary.BigEndian.PutUint16(buf[0:2], uint16(d.Type))
 		return append(buf, d.Uuid...)
 	} else {
-		buf := make([]byte, 2)
-		binary.BigEndian.PutUint16(buf[0:2], uint16(d.Type))
 		return append(buf, d.Opaque...)
 	}
 }
 
+// SerializeTo writes the DUID to a Serializer
+func (d *Duid) SerializeTo(s *Serializer) {
+	s.Write16(uint16(d.Type))
+	if d.Type == DUID_LLT {
+		s.Write16(uint16(d.HwType))
+		s.Write32(d.Time)
+		s.WriteBytes(d.LinkLayerAddr)
+	} else if d.Type == DUID_LL {
+		s.Write16(uint16(d.HwType))
+		s.WriteBytes(d.LinkLayerAddr)
+	} else if d.Type == DUID_EN {
+		s.Write32(d.EnterpriseNumber)
+		s.WriteBytes(d.EnterpriseIdentifier)
+	} else if d.Type == DUID_UUID {
+		s.WriteBytes(d.Uuid)
+	} else {
+		s.WriteBytes(d.Opaque)
+	}
+}
+
 func (d *Duid) String() string {
 	dtype := DuidTypeToString[d.Type]
 	if dtype == "" {
 		dtype = "Unknown"
 	}
-	hwtype := iana.HwTypeToString[d.HwType]
-	if hwtype == "" {
-		hwtype = "Unknown"
-	}
 	var hwaddr string
 	if d.HwType == iana.HwTypeEthernet {
 		for _, b := range d.LinkLayerAddr {
@@ -96,31 +166,79 @@ func (d *Duid) String() string {
 			hwaddr = hwaddr[:len(hwaddr)-1]
 		}
 	}
-	return fmt.Sprintf("DUID{type=%v hwtype=%v hwaddr=%v}", dtype, hwtype, hwaddr)
+	return fmt.Sprintf("DUID{type=%v hwtype=%v hwaddr=%v}", dtype, d.HwType, hwaddr)
+}
+
+// MarshalJSON returns the JSON representation of the DUID
+func (d *Duid) MarshalJSON() ([]byte, error) {
+	dtype := DuidTypeToString[d.Type]
+	if dtype == "" {
+		dtype = "Unknown"
+	}
+	data := map[string]interface{}{"type": dtype}
+	switch d.Type {
+	case DUID_LLT:
+		data["hwtype"] = d.HwType
+		data["time"] = d.Time
+		data["link_layer_addr"] = d.LinkLayerAddr.String()
+	case DUID_LL:
+		data["hwtype"] = d.HwType
+		data["link_layer_addr"] = d.LinkLayerAddr.String()
+	case DUID_EN:
+		data["enterprise_number"] = d.EnterpriseNumber
+		data["enterprise_identifier"] = d.EnterpriseIdentifier
+	case DUID_UUID:
+		data["uuid"] = d.Uuid
+	default:
+		data["opaque"] = d.Opaque
+	}
+	return json.Marshal(data)
+}
+
+// Equal returns true if the two DUIDs are identical, i.e. if their types and
+// all the fields relevant to their type are equal
+func (d *Duid) Equal(other *Duid) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	return bytes.Equal(d.ToBytes(), other.ToBytes())
+}
+
+// EqualIgnoreTime is like Equal, but ignores the time field of DUID-LLTs.
+// Servers commonly match bindings this way, so that a client that
+// regenerated its DUID-LLT on the same interface keeps its bindings.
+func (d *Duid) EqualIgnoreTime(other *Duid) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	if d.Type == DUID_LLT && other.Type == DUID_LLT {
+		return d.HwType == other.HwType && bytes.Equal(d.LinkLayerAddr, other.LinkLayerAddr)
+	}
+	return d.Equal(other)
 }
 
 func DuidFromBytes(data []byte) (*Duid, error) {
 	if len(data) < 2 {
-		return nil, fmt.Errorf("Invalid DUID: shorter than 2 bytes")
+		return nil, errOptionTooShort("Invalid DUID: shorter than 2 bytes")
 	}
 	d := Duid{}
 	d.Type = DuidType(binary.BigEndian.Uint16(data[0:2]))
 	if d.Type == DUID_LLT {
 		if len(data) < 8 {
-			return nil, fmt.Errorf("Invalid DUID-LLT: shorter than 8 bytes")
+			return nil, errOptionTooShort("Invalid DUID-LLT: shorter than 8 bytes")
 		}
 		d.HwType = iana.HwTypeType(binary.BigEndian.Uint16(data[2:4]))
 		d.Time = binary.BigEndian.Uint32(data[4:8])
 		d.LinkLayerAddr = data[8:]
 	} else if d.Type == DUID_LL {
 		if len(data) < 4 {
-			return nil, fmt.Errorf("Invalid DUID-LL: shorter than 4 bytes")
+			return nil, errOptionTooShort("Invalid DUID-LL: shorter than 4 bytes")
 		}
 		d.HwType = iana.HwTypeType(binary.BigEndian.Uint16(data[2:4]))
 		d.LinkLayerAddr = data[4:]
 	} else if d.Type == DUID_EN {
 		if len(data) < 6 {
-			return nil, fmt.Errorf("Invalid DUID-EN: shorter than 6 bytes")
+			return nil, errOptionTooShort("Invalid DUID-EN: shorter than 6 bytes")
 		}
 		d.EnterpriseNumber = binary.BigEndian.Uint32(data[2:6])
 		d.EnterpriseIdentifier = data[6:]
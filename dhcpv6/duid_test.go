@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
@@ -118,3 +119,91 @@ func TestOpaqueDuid(t *testing.T) {
 		t.Fatalf("ToBytes: unexpected result: got %x, want %x", got, want)
 	}
 }
+
+func TestNewDuidConstructorsRoundTrip(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	duids := []*Duid{
+		NewDuidLLT(iana.HwTypeEthernet, hwaddr, time.Now()),
+		NewDuidLL(iana.HwTypeEthernet, hwaddr),
+		NewDuidEN(0x00000137, []byte{0x01, 0x02, 0x03}),
+		NewDuidUUID([16]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}),
+	}
+	for _, duid := range duids {
+		buf := duid.ToBytes()
+		require.Equal(t, duid.Length(), len(buf))
+		parsed, err := DuidFromBytes(buf)
+		require.NoError(t, err)
+		require.Equal(t, duid.Type, parsed.Type)
+		require.Equal(t, buf, parsed.ToBytes())
+	}
+}
+
+func TestDuidEqual(t *testing.T) {
+	mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	epoch := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	llt1 := NewDuidLLT(iana.HwTypeEthernet, mac, epoch)
+	llt2 := NewDuidLLT(iana.HwTypeEthernet, mac, epoch.Add(time.Hour))
+	require.True(t, llt1.Equal(NewDuidLLT(iana.HwTypeEthernet, mac, epoch)))
+	require.False(t, llt1.Equal(llt2))
+	require.True(t, llt1.EqualIgnoreTime(llt2))
+	require.False(t, llt1.EqualIgnoreTime(NewDuidLLT(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6}, epoch)))
+	require.False(t, llt1.EqualIgnoreTime(NewDuidLLT(iana.HwTypeIEEE802, mac, epoch)))
+
+	// same link-layer address, different types
+	ll := NewDuidLL(iana.HwTypeEthernet, mac)
+	require.False(t, llt1.Equal(ll))
+	require.False(t, llt1.EqualIgnoreTime(ll))
+	require.True(t, ll.EqualIgnoreTime(NewDuidLL(iana.HwTypeEthernet, mac)))
+
+	en := NewDuidEN(0x137, []byte{1, 2, 3})
+	require.True(t, en.Equal(NewDuidEN(0x137, []byte{1, 2, 3})))
+	require.False(t, en.Equal(NewDuidEN(0x138, []byte{1, 2, 3})))
+
+	var nilDuid *Duid
+	require.True(t, nilDuid.Equal(nil))
+	require.False(t, nilDuid.Equal(ll))
+	require.False(t, ll.EqualIgnoreTime(nil))
+}
+
+func TestOptionsClientServerID(t *testing.T) {
+	cid := NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	sid := NewDuidEN(0x137, []byte{1, 2})
+	var opts Options
+	require.Nil(t, opts.ClientID())
+	require.Nil(t, opts.ServerID())
+	opts.Add(&OptClientId{Cid: *cid})
+	opts.Add(&OptServerId{Sid: *sid})
+	require.True(t, opts.ClientID().Equal(cid))
+	require.True(t, opts.ServerID().Equal(sid))
+}
+
+func TestNewDuidLLTTime(t *testing.T) {
+	mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	for _, tc := range []struct {
+		t    time.Time
+		want uint32
+	}{
+		{time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), 0},
+		// the time zone does not matter
+		{time.Date(2000, time.January, 1, 1, 0, 1, 0, time.FixedZone("CET", 3600)), 1},
+		{time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC), 568080000},
+		// the NTP era rollover has no effect on DUID times
+		{time.Date(2036, time.February, 7, 6, 28, 16, 0, time.UTC), 1139293696},
+		// the last second before, and the first second after, the rollover
+		{time.Date(2136, time.February, 7, 6, 28, 15, 0, time.UTC), 0xffffffff},
+		{time.Date(2136, time.February, 7, 6, 28, 16, 0, time.UTC), 0},
+		{time.Date(2136, time.February, 7, 6, 28, 17, 0, time.UTC), 1},
+	} {
+		d := NewDuidLLT(iana.HwTypeEthernet, mac, tc.t)
+		require.Equal(t, DUID_LLT, d.Type)
+		require.Equal(t, tc.want, d.Time, tc.t.String())
+		require.Equal(t, mac, d.LinkLayerAddr)
+	}
+}
+
+func TestDuidString(t *testing.T) {
+	mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	require.Equal(t, "DUID{type=DUID-LL hwtype=Ethernet hwaddr=aa:bb:cc:dd:ee:ff}", NewDuidLL(iana.HwTypeEthernet, mac).String())
+	require.Equal(t, "DUID{type=DUID-LL hwtype=Infiniband hwaddr=}", NewDuidLL(iana.HwTypeInfiniband, mac).String())
+	require.Equal(t, "DUID{type=DUID-LL hwtype=Unknown hwaddr=}", NewDuidLL(iana.HwTypeType(1000), mac).String())
+}
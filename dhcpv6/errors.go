@@ -0,0 +1,160 @@
+package dhcpv6
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+var (
+	// ErrOptionTooShort is returned when an option, or its header, is shorter
+	// than expected. Option parsers wrap it when the option value is shorter
+	// than its minimum length
+	ErrOptionTooShort = errors.New("option too short")
+	// ErrLengthMismatch is returned when the length of a parsed option is
+	// different from its declared length
+	ErrLengthMismatch = errors.New("option length mismatch")
+	// ErrRelayDepthExceeded is returned when relay messages are nested
+	// deeper than MaxRelayDepth
+	ErrRelayDepthExceeded = errors.New("relay messages nested too deep")
+	// ErrTooManyOptions is returned when a list of options holds more than
+	// MaxOptions options
+	ErrTooManyOptions = errors.New("too many options")
+	// ErrTrailingData is returned when a list of options is followed by
+	// fewer bytes than an option header
+	ErrTrailingData = errors.New("trailing data after options")
+	// ErrNoDHCPv4Parser is returned when decoding a DHCPv4 message before a
+	// parser is registered with RegisterDHCPv4Parser
+	ErrNoDHCPv4Parser = errors.New("no DHCPv4 parser registered")
+)
+
+// shortOptionError is returned by the option parsers when an option value is
+// shorter than expected. Its message is the one of the parser, and it wraps
+// ErrOptionTooShort for errors.Is.
+type shortOptionError struct {
+	msg string
+}
+
+// errOptionTooShort returns a shortOptionError with a formatted message
+func errOptionTooShort(format string, args ...interface{}) error {
+	return &shortOptionError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *shortOptionError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns ErrOptionTooShort
+func (e *shortOptionError) Unwrap() error {
+	return ErrOptionTooShort
+}
+
+// OptionParseError is returned when an option cannot be parsed. Code is the
+// code of the option, or zero if the data is too short to contain it. Err is
+// the cause, i.e. ErrOptionTooShort, ErrLengthMismatch, ErrTooManyOptions,
+// ErrTrailingData or the error returned by the option parser, and can be checked with errors.Is
+// and errors.As.
+type OptionParseError struct {
+	Code OptionCode
+	Err  error
+	msg  string
+}
+
+// newOptionParseError returns an OptionParseError with a formatted message
+func newOptionParseError(code OptionCode, err error, format string, args ...interface{}) *OptionParseError {
+	return &OptionParseError{
+		Code: code,
+		Err:  err,
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *OptionParseError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the error
+func (e *OptionParseError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError describes an option that is missing from, forbidden in, or
+// invalid in a message of the given type
+type ValidationError struct {
+	MessageType MessageType
+	Option      OptionCode
+	// Missing is true if the option is required and missing, and false if it
+	// is forbidden or invalid and present
+	Missing bool
+	// Err is the error returned by ValidOption if the option is invalid, and
+	// nil otherwise
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	name, _ := optionCodeName(e.Option)
+	if e.Err != nil {
+		return fmt.Sprintf("%v has an invalid %v: %v", MessageTypeToString(e.MessageType), name, e.Err)
+	}
+	if e.Missing {
+		return fmt.Sprintf("%v must include %v", MessageTypeToString(e.MessageType), name)
+	}
+	return fmt.Sprintf("%v must not include %v", MessageTypeToString(e.MessageType), name)
+}
+
+// Unwrap returns the error of an invalid option
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is a list of ValidationError, returned by
+// DHCPv6Message.Validate
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ve := range e {
+		msgs = append(msgs, ve.Error())
+	}
+	return "Invalid message: " + strings.Join(msgs, "; ")
+}
+
+// StatusError describes a non-success status code reported by a server.
+// Option is the code of the option carrying the status code, i.e. zero for
+// the top-level status code, OPTION_IA_NA or OPTION_IA_PD, in which case IAID
+// identifies the IA.
+type StatusError struct {
+	Option        OptionCode
+	IAID          IAID
+	StatusCode    iana.StatusCode
+	StatusMessage string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%v (%d)", iana.StatusCodeToString(e.StatusCode), e.StatusCode)
+	if e.StatusMessage != "" {
+		msg += ": " + e.StatusMessage
+	}
+	if e.Option != 0 {
+		name, _ := optionCodeName(e.Option)
+		msg = fmt.Sprintf("%v %v: %v", name, e.IAID, msg)
+	}
+	return msg
+}
+
+// StatusErrors is a list of StatusError, returned by
+// DHCPv6Message.SucceededOrError
+type StatusErrors []*StatusError
+
+func (e StatusErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, se := range e {
+		msgs = append(msgs, se.Error())
+	}
+	return "Server reported failure: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,129 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionTooShort(t *testing.T) {
+	_, err := ParseOption([]byte{0, 1, 0})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+
+	_, err = ParseOption([]byte{0, 8, 0, 2, 0})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+	var perr *OptionParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, OPTION_ELAPSED_TIME, perr.Code)
+	// the message is unchanged
+	require.Equal(t, "Invalid option length for option 8. Declared 2, actual 1", err.Error())
+}
+
+func TestParseOptionParserError(t *testing.T) {
+	// an elapsed time option with 3 bytes of data
+	_, err := ParseOption([]byte{0, 8, 0, 3, 0, 0, 0})
+	var perr *OptionParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, OPTION_ELAPSED_TIME, perr.Code)
+	require.False(t, errors.Is(err, ErrOptionTooShort))
+	require.Equal(t, "Invalid elapsed time data length. Expected 2 bytes, got 3", err.Error())
+}
+
+func TestParseOptionShortValue(t *testing.T) {
+	// an IA address option with 10 bytes of data
+	_, err := ParseOption([]byte{0, 5, 0, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	var perr *OptionParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, OPTION_IAADDR, perr.Code)
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+
+	// an IA_NA option with 2 bytes of data
+	_, err = ParseOption([]byte{0, 3, 0, 2, 0, 0})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+
+	// the message is the one of the option parser
+	_, err = ParseOptIAAddress(make([]byte, 10))
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+	require.Equal(t, "Invalid IA Address data length. Expected at least 24 bytes, got 10", err.Error())
+	_, err = DuidFromBytes([]byte{0})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+	require.Equal(t, "Invalid DUID: shorter than 2 bytes", err.Error())
+}
+
+func TestOptionsFromBytesErrors(t *testing.T) {
+	_, err := OptionsFromBytes([]byte{0, 1, 0})
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+
+	_, err = OptionsFromBytes([]byte{0, 8, 0, 2, 0, 0, 0, 1})
+	require.True(t, errors.Is(err, ErrTrailingData))
+
+	_, err = OptionsFromBytes([]byte{0, 8, 0, 2, 0, 0, 0, 1, 0, 4, 0})
+	var perr *OptionParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, OPTION_CLIENTID, perr.Code)
+}
+
+func TestParseOptionNestedError(t *testing.T) {
+	// an IA_NA carrying a truncated IA address
+	data := []byte{
+		0, 3, 0, 18, // OPTION_IA_NA
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 1, // T1
+		0, 0, 0, 2, // T2
+		0, 5, 0, 24, 0, 0, // truncated OPTION_IAADDR
+	}
+	_, err := ParseOption(data)
+	var perr *OptionParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, OPTION_IA_NA, perr.Code)
+	require.True(t, errors.Is(err, ErrOptionTooShort))
+}
+
+func TestParseOptionLengthMismatch(t *testing.T) {
+	RegisterParser(OptionCode(65004), func(data []byte) (Option, error) {
+		return &OptionGeneric{OptionCode: 65004}, nil
+	})
+	defer RegisterParser(OptionCode(65004), nil)
+	_, err := ParseOption([]byte{0xfd, 0xec, 0, 1, 0})
+	require.True(t, errors.Is(err, ErrLengthMismatch))
+}
+
+func TestSucceededOrError(t *testing.T) {
+	msg := DHCPv6Message{messageType: REPLY}
+	require.NoError(t, msg.SucceededOrError())
+
+	msg.AddOption(&OptStatusCode{StatusCode: iana.StatusSuccess})
+	ia := &OptIANA{IaId: IAID{0, 0, 0, 1}}
+	ia.Options.Add(&OptStatusCode{StatusCode: iana.StatusSuccess})
+	msg.AddOption(ia)
+	require.NoError(t, msg.SucceededOrError())
+
+	msg.SetOptions([]Option{&OptStatusCode{StatusCode: iana.StatusUnspecFail, StatusMessage: []byte("try later")}})
+	pd := &OptIAForPrefixDelegation{}
+	pd.SetIAID(IAID{0, 0, 0, 2})
+	pd.SetOptions(Options{&OptStatusCode{StatusCode: iana.StatusNoPrefixAvail}})
+	msg.AddOption(pd)
+	err := msg.SucceededOrError()
+	require.Error(t, err)
+	var errs StatusErrors
+	require.True(t, errors.As(err, &errs))
+	require.Equal(t, 2, len(errs))
+	require.Equal(t, OptionCode(0), errs[0].Option)
+	require.Equal(t, iana.StatusUnspecFail, errs[0].StatusCode)
+	require.Equal(t, "try later", errs[0].StatusMessage)
+	require.Equal(t, OPTION_IA_PD, errs[1].Option)
+	require.Equal(t, IAID{0, 0, 0, 2}, errs[1].IAID)
+	require.Equal(t, iana.StatusNoPrefixAvail, errs[1].StatusCode)
+	require.Equal(t, "Server reported failure: UnspecFail (1): try later; OPTION_IA_PD 00000002: NoPrefixAvail (6)", err.Error())
+}
+
+func TestOptionsStatus(t *testing.T) {
+	opts := Options{}
+	require.Nil(t, opts.Status())
+	sc := &OptStatusCode{StatusCode: iana.StatusNoBinding}
+	opts.Add(&OptRapidCommit{})
+	opts.Add(sc)
+	require.Equal(t, sc, opts.Status())
+}
@@ -0,0 +1,65 @@
+package dhcpv6
+
+import (
+	"bytes"
+	"testing"
+)
+
+// fuzzSeeds are valid options used as a starting point for the fuzzers
+var fuzzSeeds = [][]byte{
+	{0, 1, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5},            // OPTION_CLIENTID
+	{0, 3, 0, 12, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2},      // OPTION_IA_NA
+	{0, 6, 0, 4, 0, 23, 0, 24},                             // OPTION_ORO
+	{0, 8, 0, 2, 0xaa, 0xbb},                               // OPTION_ELAPSED_TIME
+	{0, 11, 0, 11, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1},        // OPTION_AUTH
+	{0, 13, 0, 4, 0, 0, 'o', 'k'},                          // OPTION_STATUS_CODE
+	{0, 14, 0, 0},                                          // OPTION_RAPID_COMMIT
+	{0, 15, 0, 6, 0, 4, 't', 'e', 's', 't'},                // OPTION_USER_CLASS
+	{0, 24, 0, 9, 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0}, // DOMAIN_SEARCH_LIST
+	{0, 25, 0, 12, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2},     // OPTION_IA_PD
+	{0, 39, 0, 5, 1, 3, 'f', 'o', 'o'},                     // FQDN
+	{0, 56, 0, 8, 0, 3, 0, 4, 3, 'n', 't', 'p'},            // OPTION_NTP_SERVER
+	{0, 61, 0, 4, 0, 7, 0, 9},                              // OPTION_CLIENT_ARCH_TYPE
+	{0, 59, 0, 5, 'h', 't', 't', 'p', ':'},                 // OPT_BOOTFILE_URL
+	{0, 9, 0, 8, 1, 0xaa, 0xbb, 0xcc, 0, 8, 0, 0},          // OPTION_RELAY_MSG
+	{0, 89, 0, 10, 1, 16, 24, 192, 0, 2, 0, 16, 0x20, 1},   // OPTION_S46_RULE
+	{0, 36, 0, 7, 2, 'U', 'S', 1, 2, 'C', 'A'},             // OPTION_GEOCONF_CIVIC
+}
+
+func FuzzParseOption(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		opt, err := ParseOption(data)
+		if err != nil {
+			return
+		}
+		consumed := data[:4+opt.Length()]
+		if b := opt.ToBytes(); !bytes.Equal(b, consumed) {
+			t.Fatalf("Round trip mismatch for %v. Expected %v, got %v", opt, consumed, b)
+		}
+	})
+}
+
+func FuzzOptionsFromBytes(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Add(bytes.Join(fuzzSeeds, nil))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		opts, err := OptionsFromBytes(data)
+		if err != nil {
+			return
+		}
+		s := NewSerializer(len(data))
+		s.WriteOptions(opts)
+		if b := s.Bytes(); !bytes.Equal(b, data) {
+			t.Fatalf("Round trip mismatch for %v. Expected %v, got %v", opts, data, b)
+		}
+		// the lenient mode must accept whatever the strict mode does
+		if _, errs := OptionsFromBytesMode(data, false); len(errs) > 0 {
+			t.Fatalf("Lenient parsing failed where strict parsing succeeded: %v", errs)
+		}
+	})
+}
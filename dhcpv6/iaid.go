@@ -0,0 +1,26 @@
+package dhcpv6
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// IAID represents an Identity Association identifier, as used by IA_NA, IA_TA
+// and IA_PD options
+type IAID [4]byte
+
+// String returns the IAID as a hex string
+func (i IAID) String() string {
+	return hex.EncodeToString(i[:])
+}
+
+// IAIDFromBytes builds an IAID from a sequence of bytes, which must be exactly
+// 4 bytes long
+func IAIDFromBytes(data []byte) (IAID, error) {
+	var iaid IAID
+	if len(data) != len(iaid) {
+		return iaid, fmt.Errorf("Invalid IAID length. Expected %v bytes, got %v", len(iaid), len(data))
+	}
+	copy(iaid[:], data)
+	return iaid, nil
+}
@@ -0,0 +1,21 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIAIDFromBytes(t *testing.T) {
+	iaid, err := IAIDFromBytes([]byte{0xfa, 0xce, 0xb0, 0x0c})
+	require.NoError(t, err)
+	require.Equal(t, IAID{0xfa, 0xce, 0xb0, 0x0c}, iaid)
+	require.Equal(t, "faceb00c", iaid.String())
+}
+
+func TestIAIDFromBytesInvalidLength(t *testing.T) {
+	_, err := IAIDFromBytes([]byte{0xfa, 0xce, 0xb0})
+	require.Error(t, err)
+	_, err = IAIDFromBytes([]byte{0xfa, 0xce, 0xb0, 0x0c, 0x00})
+	require.Error(t, err)
+}
@@ -1,30 +1,150 @@
 package dhcpv6
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"syscall"
 )
 
+// GetLinkLocalAddr returns a pointer to the first link-local address of the
+// given interface, see GetLinkLocalIP
 func GetLinkLocalAddr(ifname string) (*net.IP, error) {
-	ifaces, err := net.Interfaces()
+	ip, err := GetLinkLocalIP(ifname)
 	if err != nil {
 		return nil, err
 	}
-	for _, iface := range ifaces {
-		if iface.Name != ifname {
-			continue
+	return &ip, nil
+}
+
+// GetLinkLocalIP returns the first fe80::/10 address of the given interface,
+// in the order they are reported by the system
+func GetLinkLocalIP(ifname string) (net.IP, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+	ifaddrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, ifaddr := range ifaddrs {
+		if ifaddr, ok := ifaddr.(*net.IPNet); ok {
+			if ifaddr.IP.To4() == nil && ifaddr.IP.IsLinkLocalUnicast() {
+				return ifaddr.IP, nil
+			}
 		}
-		ifaddrs, err := iface.Addrs()
+	}
+	return nil, fmt.Errorf("No link-local address found for interface %v", ifname)
+}
+
+// BindUDPInterface creates a UDP socket bound to laddr and to the given
+// interface, see BindToInterface. If laddr is nil, the first link-local
+// address of the interface and the DHCPv6 client port are used.
+func BindUDPInterface(ifname string, laddr *net.UDPAddr) (*net.UDPConn, error) {
+	if laddr == nil {
+		ip, err := GetLinkLocalIP(ifname)
 		if err != nil {
 			return nil, err
 		}
-		for _, ifaddr := range ifaddrs {
-			if ifaddr, ok := ifaddr.(*net.IPNet); ok {
-				if ifaddr.IP.To4() == nil && ifaddr.IP.IsLinkLocalUnicast() {
-					return &ifaddr.IP, nil
-				}
+		laddr = &net.UDPAddr{IP: ip, Port: ClientPort, Zone: ifname}
+	}
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var bindErr error
+			err := c.Control(func(fd uintptr) {
+				bindErr = BindToInterface(int(fd), ifname)
+			})
+			if err != nil {
+				return err
 			}
+			return bindErr
+		},
+	}
+	conn, err := lc.ListenPacket(context.Background(), "udp6", laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*net.UDPConn), nil
+}
+
+// parseIP6List parses a sequence of 16-byte IPv6 addresses, as used by the
+// various server list options. The returned addresses alias data.
+func parseIP6List(data []byte) ([]net.IP, error) {
+	if len(data)%net.IPv6len != 0 {
+		return nil, fmt.Errorf("Invalid IPv6 address list data length. Expected a multiple of %d bytes, got %v", net.IPv6len, len(data))
+	}
+	var ips []net.IP
+	for i := 0; i < len(data); i += net.IPv6len {
+		ips = append(ips, net.IP(data[i:i+net.IPv6len]))
+	}
+	return ips, nil
+}
+
+// ensureIP6 returns the 16-byte form of ip, or an error if ip is nil, is not
+// a valid address or is an IPv4 address, including an IPv4-mapped one. The
+// option constructors use it to reject addresses that are not IPv6.
+func ensureIP6(ip net.IP) ([]byte, error) {
+	if ip == nil {
+		return nil, fmt.Errorf("Invalid IPv6 address: nil")
+	}
+	ip16 := ip.To16()
+	if ip16 == nil {
+		return nil, fmt.Errorf("Invalid IPv6 address %v: expected 16 bytes, got %v", ip, len(ip))
+	}
+	if ip.To4() != nil {
+		return nil, fmt.Errorf("Invalid IPv6 address %v: IPv4 address", ip)
+	}
+	return ip16, nil
+}
+
+// checkIP6 returns an error unless ip is a 16-byte address, the only form
+// IPv6 address fields can be serialized from unchanged. Any 16-byte address
+// is accepted, including IPv4-mapped ones, which parsed options may carry.
+// The options with IPv6 address fields use it in their Valid method.
+func checkIP6(ip net.IP) error {
+	if ip == nil {
+		return fmt.Errorf("Invalid IPv6 address: nil")
+	}
+	if len(ip) != net.IPv6len {
+		return fmt.Errorf("Invalid IPv6 address %v: expected %v bytes, got %v", ip, net.IPv6len, len(ip))
+	}
+	return nil
+}
+
+// checkIP6List returns the error of checkIP6 for the first invalid address
+// of a list
+func checkIP6List(ips []net.IP) error {
+	for _, ip := range ips {
+		if err := checkIP6(ip); err != nil {
+			return err
 		}
 	}
-	return nil, fmt.Errorf("No link-local address found for interface %v", ifname)
+	return nil
+}
+
+// appendIP6 appends ip to b and returns the extended slice. 16-byte
+// addresses are appended unchanged. Since ToBytes cannot fail, the addresses
+// rejected by checkIP6 are still appended as 16 bytes, so that the option
+// stays well-formed: 4-byte IPv4 addresses in their IPv4-mapped form, and
+// anything else as zeros. The options using appendIP6 reject such addresses in
+// their Valid method, which DHCPv6Message.Validate, MarshalBinary and the
+// Client call before sending, see ValidOption.
+func appendIP6(b []byte, ip net.IP) []byte {
+	if len(ip) == net.IPv6len {
+		return append(b, ip...)
+	}
+	var buf [net.IPv6len]byte
+	copy(buf[:], ip.To16())
+	return append(b, buf[:]...)
+}
+
+// ip6ListToBytes serializes a list of IPv6 addresses as a sequence of
+// 16-byte addresses, see appendIP6.
+func ip6ListToBytes(ips []net.IP) []byte {
+	buf := make([]byte, 0, len(ips)*net.IPv6len)
+	for _, ip := range ips {
+		buf = appendIP6(buf, ip)
+	}
+	return buf
 }
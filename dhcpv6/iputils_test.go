@@ -0,0 +1,146 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIP6List(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("2001:db8::2"),
+	}
+	parsed, err := parseIP6List(ip6ListToBytes(ips))
+	require.NoError(t, err)
+	require.Equal(t, ips, parsed)
+
+	parsed, err = parseIP6List([]byte{})
+	require.NoError(t, err)
+	require.Empty(t, parsed)
+}
+
+func TestParseIP6ListInvalidLength(t *testing.T) {
+	_, err := parseIP6List(make([]byte, 15))
+	require.Error(t, err)
+	_, err = parseIP6List(make([]byte, 33))
+	require.Error(t, err)
+}
+
+func TestIP6ListRoundTrip(t *testing.T) {
+	ips := []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::53")}
+	for _, opt := range []Option{
+		&OptDNSRecursiveNameServer{NameServers: ips},
+		&OptSNTPServers{NTPServers: ips},
+		&OptDHCP4oDHCP6Server{DHCP4oDHCP6Servers: ips},
+	} {
+		parsed, err := ParseOption(opt.ToBytes())
+		require.NoError(t, err, "option %v", opt.Code())
+		require.Equal(t, opt, parsed)
+	}
+}
+
+func TestGetLinkLocalIPNoInterface(t *testing.T) {
+	_, err := GetLinkLocalIP("nonexistent0")
+	require.Error(t, err)
+}
+
+func TestBindUDPInterface(t *testing.T) {
+	conn, err := BindUDPInterface("lo", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("cannot bind to the loopback interface: %v", err)
+	}
+	defer conn.Close()
+	require.True(t, conn.LocalAddr().(*net.UDPAddr).IP.Equal(net.IPv6loopback))
+}
+
+func TestBindUDPInterfaceNoInterface(t *testing.T) {
+	_, err := BindUDPInterface("nonexistent0", &net.UDPAddr{IP: net.IPv6loopback})
+	require.Error(t, err)
+}
+
+func TestEnsureIP6(t *testing.T) {
+	ip, err := ensureIP6(net.ParseIP("2001:db8::1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte(net.ParseIP("2001:db8::1")), ip)
+	ip, err = ensureIP6(net.IPv6unspecified)
+	require.NoError(t, err)
+	require.Equal(t, 16, len(ip))
+
+	for _, bad := range []net.IP{
+		nil,
+		net.IP{},
+		net.IP{1, 2, 3},
+		net.IPv4(192, 0, 2, 1).To4(),
+		net.ParseIP("192.0.2.1"),
+	} {
+		_, err = ensureIP6(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestIP6ListToBytesInvalid(t *testing.T) {
+	ips := []net.IP{net.IPv4(192, 0, 2, 1).To4(), nil, net.ParseIP("2001:db8::1")}
+	require.Error(t, checkIP6List(ips))
+	buf := ip6ListToBytes(ips)
+	require.Equal(t, 48, len(buf))
+	require.Equal(t, []byte(net.ParseIP("192.0.2.1")), buf[:16])
+	require.Equal(t, make([]byte, 16), buf[16:32])
+	require.Equal(t, []byte(net.ParseIP("2001:db8::1")), buf[32:])
+}
+
+func TestCheckIP6(t *testing.T) {
+	for _, ip := range []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.IPv6unspecified,
+		// IPv4-mapped addresses are serialized unchanged
+		net.ParseIP("::ffff:192.0.2.1"),
+	} {
+		require.NoError(t, checkIP6(ip), ip)
+		require.Equal(t, []byte(ip), appendIP6(nil, ip))
+	}
+	for _, ip := range []net.IP{nil, {}, {1, 2, 3}, net.IPv4(192, 0, 2, 1).To4()} {
+		require.Error(t, checkIP6(ip), ip)
+	}
+	require.NoError(t, checkIP6List(nil))
+}
+
+func TestOptionsValidIPv6Addresses(t *testing.T) {
+	v4 := net.IPv4(192, 0, 2, 1).To4()
+	for _, opt := range []OptionValidator{
+		&OptDNSRecursiveNameServer{NameServers: []net.IP{v4}},
+		&OptSNTPServers{NTPServers: []net.IP{v4}},
+		&OptNISServers{NISServers: []net.IP{v4}},
+		&OptNISPServers{NISPServers: []net.IP{v4}},
+		&OptSIPServersAddressList{SIPServers: []net.IP{v4}},
+		&OptBCMCSControllerIPv6AddressList{Controllers: []net.IP{v4}},
+		&OptPanaAgent{Agents: []net.IP{v4}},
+		&OptLQClientLink{LinkAddresses: []net.IP{v4}},
+		&OptDHCP4oDHCP6Server{DHCP4oDHCP6Servers: []net.IP{v4}},
+		&OptLQQuery{LinkAddress: v4},
+		&OptLQRelayData{PeerAddress: v4},
+		&OptS46BR{BRIPv6Address: v4},
+		&OptNTPServer{Suboptions: []NTPSuboption{{SuboptionType: NTP_SUBOPTION_SRV_ADDR, Addr: v4}}},
+		&OptIAAddress{IPv6Addr: v4},
+	} {
+		require.Error(t, opt.Valid(), "%v", opt)
+	}
+	require.NoError(t, (&OptLQQuery{LinkAddress: net.ParseIP("::ffff:192.0.2.1")}).Valid())
+	require.NoError(t, (&OptNTPServer{Suboptions: []NTPSuboption{{SuboptionType: NTP_SUBOPTION_SRV_FQDN, FQDN: "ntp"}}}).Valid())
+}
+
+func TestOptionsValidNested(t *testing.T) {
+	addr := &OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")}
+	require.NoError(t, Options{NewOptIANA(IAID{1, 2, 3, 4}, addr)}.Valid())
+
+	// an IA address without address, nested in an IA_NA
+	require.Error(t, Options{NewOptIANA(IAID{1, 2, 3, 4}, &OptIAAddress{})}.Valid())
+
+	// an invalid option in a relayed message
+	msg := DHCPv6Message{messageType: SOLICIT}
+	msg.AddOption(&OptDNSRecursiveNameServer{NameServers: []net.IP{nil}})
+	relay, err := EncapsulateRelay(&msg, RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	require.Error(t, Options(relay.Options()).Valid())
+}
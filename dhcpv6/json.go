@@ -0,0 +1,156 @@
+package dhcpv6
+
+// This module implements the JSON representation of messages and options.
+// Options are represented as {"code": 1, "name": "OPTION_CLIENTID", "data":
+// {...}, "raw": "..."}, where data holds the typed fields of the option, and
+// raw its base64-encoded payload. Options without a typed representation
+// only carry their payload, base64-encoded, in data.
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// optionJSON is the JSON representation of an option
+type optionJSON struct {
+	Code OptionCode  `json:"code"`
+	Name string      `json:"name"`
+	Data interface{} `json:"data"`
+	Raw  []byte      `json:"raw"`
+}
+
+// genericOptionJSON is the JSON representation of an option without typed
+// data
+type genericOptionJSON struct {
+	Code OptionCode `json:"code"`
+	Name string     `json:"name"`
+	Data []byte     `json:"data"`
+}
+
+// marshalOptionJSON returns the JSON representation of an option with the
+// given typed data
+func marshalOptionJSON(opt Option, data interface{}) ([]byte, error) {
+	name, ok := optionCodeName(opt.Code())
+	if !ok {
+		name = "Unknown"
+	}
+	return json.Marshal(optionJSON{Code: opt.Code(), Name: name, Data: data, Raw: opt.ToBytes()[4:]})
+}
+
+// MarshalJSON returns the JSON representation of the option, with its data
+// encoded in base64
+func (og *OptionGeneric) MarshalJSON() ([]byte, error) {
+	name, ok := optionCodeName(og.OptionCode)
+	if !ok {
+		name = "Unknown"
+	}
+	data := og.OptionData
+	if data == nil {
+		data = []byte{}
+	}
+	return json.Marshal(genericOptionJSON{Code: og.OptionCode, Name: name, Data: data})
+}
+
+// jsonOptions returns the options in a form suitable for json.Marshal. Options
+// that do not implement json.Marshaler are represented like OptionGeneric.
+func jsonOptions(options []Option) []interface{} {
+	ret := make([]interface{}, 0, len(options))
+	for _, opt := range options {
+		if _, ok := opt.(json.Marshaler); ok {
+			ret = append(ret, opt)
+		} else {
+			ret = append(ret, &OptionGeneric{OptionCode: opt.Code(), OptionData: opt.ToBytes()[4:]})
+		}
+	}
+	return ret
+}
+
+// messageJSON is the JSON representation of a message
+type messageJSON struct {
+	Type          MessageType       `json:"type"`
+	Name          string            `json:"name"`
+	TransactionID uint32            `json:"transaction_id"`
+	Options       []json.RawMessage `json:"options"`
+}
+
+// MarshalJSON returns the JSON representation of the message
+func (d *DHCPv6Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type          MessageType   `json:"type"`
+		Name          string        `json:"name"`
+		TransactionID uint32        `json:"transaction_id"`
+		Options       []interface{} `json:"options"`
+	}{
+		Type:          d.messageType,
+		Name:          d.MessageTypeToString(),
+		TransactionID: d.transactionID,
+		Options:       jsonOptions(d.options),
+	})
+}
+
+// UnmarshalJSON builds the message from its JSON representation. The options
+// are rebuilt from their raw payload, or from their data if they have no
+// typed representation, and parsed as ParseOption does.
+func (d *DHCPv6Message) UnmarshalJSON(data []byte) error {
+	var m messageJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m.Type == RELAY_FORW || m.Type == RELAY_REPL {
+		return fmt.Errorf("Invalid message type %v: not a client/server message", MessageTypeToString(m.Type))
+	}
+	if m.TransactionID > 0xffffff {
+		return fmt.Errorf("Invalid transaction ID %v: longer than 24 bits", m.TransactionID)
+	}
+	options := make(Options, 0, len(m.Options))
+	for _, o := range m.Options {
+		opt, err := optionFromJSON(o)
+		if err != nil {
+			return err
+		}
+		options = append(options, opt)
+	}
+	d.messageType = m.Type
+	d.transactionID = m.TransactionID
+	d.options = options
+	return nil
+}
+
+// optionFromJSON builds an option from its JSON representation
+func optionFromJSON(data []byte) (Option, error) {
+	var o struct {
+		Code OptionCode      `json:"code"`
+		Data json.RawMessage `json:"data"`
+		Raw  []byte          `json:"raw"`
+	}
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+	raw := o.Raw
+	if raw == nil {
+		// no typed representation, data holds the payload
+		if err := json.Unmarshal(o.Data, &raw); err != nil {
+			return nil, fmt.Errorf("Invalid JSON data for option %v: %v", o.Code, err)
+		}
+	}
+	return parseOption(o.Code, raw, 0)
+}
+
+// MarshalJSON returns the JSON representation of the relay message
+func (r *DHCPv6Relay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     MessageType   `json:"type"`
+		Name     string        `json:"name"`
+		HopCount uint8         `json:"hop_count"`
+		LinkAddr string        `json:"link_addr"`
+		PeerAddr string        `json:"peer_addr"`
+		Options  []interface{} `json:"options"`
+	}{
+		Type:     r.messageType,
+		Name:     r.MessageTypeToString(),
+		HopCount: r.hopCount,
+		LinkAddr: r.linkAddr.String(),
+		PeerAddr: r.peerAddr.String(),
+		Options:  jsonOptions(r.options),
+	})
+}
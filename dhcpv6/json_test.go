@@ -0,0 +1,103 @@
+package dhcpv6
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONTestMessage() *DHCPv6Message {
+	ia := &OptIANA{IaId: IAID{0xfa, 0xce, 0xb0, 0x0c}, T1: 10, T2: 20}
+	ia.Options.Add(&OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")})
+	return &DHCPv6Message{
+		messageType:   SOLICIT,
+		transactionID: 0x1a2b3c,
+		options: Options{
+			&OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff})},
+			&OptRapidCommit{},
+			ia,
+			// a code that no test registers a name or parser for
+			&OptionGeneric{OptionCode: 65201, OptionData: []byte{1, 2, 3}},
+		},
+	}
+}
+
+func TestMessageMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(newJSONTestMessage())
+	require.NoError(t, err)
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, "SOLICIT", m["name"])
+	require.Equal(t, float64(0x1a2b3c), m["transaction_id"])
+	options := m["options"].([]interface{})
+	require.Equal(t, 4, len(options))
+
+	cid := options[0].(map[string]interface{})
+	require.Equal(t, float64(OPTION_CLIENTID), cid["code"])
+	require.Equal(t, "OPTION_CLIENTID", cid["name"])
+	duid := cid["data"].(map[string]interface{})["duid"].(map[string]interface{})
+	require.Equal(t, "DUID-LL", duid["type"])
+	require.Equal(t, "aa:bb:cc:dd:ee:ff", duid["link_layer_addr"])
+
+	ia := options[2].(map[string]interface{})["data"].(map[string]interface{})
+	require.Equal(t, "faceb00c", ia["iaid"])
+	addr := ia["options"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "2001:db8::1", addr["data"].(map[string]interface{})["ipv6_addr"])
+
+	generic := options[3].(map[string]interface{})
+	require.Equal(t, "Unknown", generic["name"])
+	require.Equal(t, "AQID", generic["data"])
+	require.NotContains(t, generic, "raw")
+}
+
+func TestMessageJSONRoundTrip(t *testing.T) {
+	msg := newJSONTestMessage()
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	var decoded DHCPv6Message
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, msg.ToBytes(), decoded.ToBytes())
+	require.IsType(t, &OptClientId{}, decoded.GetOneOption(OPTION_CLIENTID))
+	require.IsType(t, &OptIANA{}, decoded.GetOneOption(OPTION_IA_NA))
+}
+
+func TestMessageUnmarshalJSONInvalid(t *testing.T) {
+	var m DHCPv6Message
+	require.Error(t, json.Unmarshal([]byte(`{"type": 12, "transaction_id": 1, "options": []}`), &m))
+	require.Error(t, json.Unmarshal([]byte(`{"type": 1, "transaction_id": 16777216, "options": []}`), &m))
+	require.Error(t, json.Unmarshal([]byte(`{"type": 1, "transaction_id": 1, "options": [{"code": 1, "data": {}}]}`), &m))
+	// the raw data must be a valid option
+	require.Error(t, json.Unmarshal([]byte(`{"type": 1, "transaction_id": 1, "options": [{"code": 8, "data": {}, "raw": "AQ=="}]}`), &m))
+}
+
+// noJSONOption is an option that does not implement json.Marshaler
+type noJSONOption struct{}
+
+func (o *noJSONOption) Code() OptionCode { return 65002 }
+func (o *noJSONOption) ToBytes() []byte  { return []byte{0xfd, 0xea, 0, 1, 0xff} }
+func (o *noJSONOption) Length() int      { return 1 }
+func (o *noJSONOption) String() string   { return "noJSONOption" }
+
+func TestMessageMarshalJSONUntypedOption(t *testing.T) {
+	msg := DHCPv6Message{messageType: REPLY, options: Options{&noJSONOption{}}}
+	data, err := json.Marshal(&msg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": 7, "name": "REPLY", "transaction_id": 0, "options": [{"code": 65002, "name": "Unknown", "data": "/w=="}]}`, string(data))
+}
+
+func TestRelayMarshalJSON(t *testing.T) {
+	relay, err := EncapsulateRelay(newJSONTestMessage(), RELAY_FORW, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	data, err := json.Marshal(relay)
+	require.NoError(t, err)
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, "::1", m["link_addr"])
+	relayMsg := m["options"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "OPTION_RELAY_MSG", relayMsg["name"])
+	inner := relayMsg["data"].(map[string]interface{})["message"].(map[string]interface{})
+	require.Equal(t, "SOLICIT", inner["name"])
+}
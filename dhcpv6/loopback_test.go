@@ -0,0 +1,132 @@
+package dhcpv6
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errLoopbackClosed = errors.New("use of closed loopback connection")
+
+// loopbackPacket is a packet in flight between two loopbackConns
+type loopbackPacket struct {
+	data []byte
+	from net.Addr
+}
+
+// loopbackConn is an in-memory net.PacketConn. Packets written to one end of
+// a pair created by newLoopback are read from the other end, whatever their
+// destination address.
+type loopbackConn struct {
+	addr   *net.UDPAddr
+	in     chan loopbackPacket
+	peer   *loopbackConn
+	closed chan struct{}
+	once   sync.Once
+
+	mu           sync.Mutex
+	readDeadline time.Time
+}
+
+// newLoopback returns the two connected ends of an in-memory transport, a
+// client end bound to [::1]:546 and a server end bound to [::1]:547
+func newLoopback() (*loopbackConn, *loopbackConn) {
+	client := &loopbackConn{
+		addr:   &net.UDPAddr{IP: net.IPv6loopback, Port: ClientPort},
+		in:     make(chan loopbackPacket, 16),
+		closed: make(chan struct{}),
+	}
+	server := &loopbackConn{
+		addr:   &net.UDPAddr{IP: net.IPv6loopback, Port: ServerPort},
+		in:     make(chan loopbackPacket, 16),
+		closed: make(chan struct{}),
+	}
+	client.peer, server.peer = server, client
+	return client, server
+}
+
+func (l *loopbackConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	l.mu.Lock()
+	deadline := l.readDeadline
+	l.mu.Unlock()
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case p := <-l.in:
+		return copy(b, p.data), p.from, nil
+	case <-l.closed:
+		return 0, nil, errLoopbackClosed
+	case <-timeout:
+		return 0, nil, timeoutError{}
+	}
+}
+
+func (l *loopbackConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	p := loopbackPacket{data: append([]byte(nil), b...), from: l.addr}
+	select {
+	case l.peer.in <- p:
+		return len(b), nil
+	case <-l.peer.closed:
+		return 0, errLoopbackClosed
+	case <-l.closed:
+		return 0, errLoopbackClosed
+	}
+}
+
+func (l *loopbackConn) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *loopbackConn) LocalAddr() net.Addr { return l.addr }
+
+func (l *loopbackConn) SetDeadline(t time.Time) error { return l.SetReadDeadline(t) }
+
+func (l *loopbackConn) SetReadDeadline(t time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.readDeadline = t
+	return nil
+}
+
+func (l *loopbackConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func TestClientServerLoopback(t *testing.T) {
+	clientConn, serverConn := newLoopback()
+	handle := rapidCommitHandler(false, false)
+	s := &Server{
+		PacketConn: serverConn,
+		Handler: func(conn net.PacketConn, peer net.Addr, m *DHCPv6Message) {
+			if reply := handle(m); reply != nil {
+				conn.WriteTo(reply.ToBytes(), peer)
+			}
+		},
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ActivateAndServe()
+	}()
+
+	c := NewClient()
+	c.conn = clientConn
+	c.RemoteAddr = serverConn.LocalAddr()
+	conversation, err := c.Exchange("lo", newTestSolicit(t))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(conversation))
+	for i, typ := range []MessageType{SOLICIT, ADVERTISE, REQUEST, REPLY} {
+		require.Equal(t, typ, conversation[i].Type())
+	}
+	xid := conversation[2].(*DHCPv6Message).TransactionID()
+	require.Equal(t, xid, conversation[3].(*DHCPv6Message).TransactionID())
+
+	require.NoError(t, s.Close())
+	require.NoError(t, <-done)
+}
@@ -2,6 +2,9 @@ package dhcpv6
 
 import (
 	"log"
+	"net"
+
+	"github.com/insomniacslk/dhcp/iana"
 )
 
 // WithClientID adds a client ID option to a DHCPv6 packet
@@ -22,6 +25,64 @@ func WithServerID(duid Duid) Modifier {
 	}
 }
 
+// WithRequestedOptions adds the given option codes to the requested options
+// list of a DHCPv6 packet, skipping the ones that are already requested
+func WithRequestedOptions(codes ...OptionCode) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		opt := d.GetOneOption(OPTION_ORO)
+		if opt == nil {
+			opt = &OptRequestedOption{}
+		}
+		oro := opt.(*OptRequestedOption)
+		for _, code := range codes {
+			oro.Add(code)
+		}
+		d.UpdateOption(oro)
+		return d
+	}
+}
+
+// WithRapidCommit adds a rapid commit option to a DHCPv6 packet
+func WithRapidCommit(d DHCPv6) DHCPv6 {
+	d.UpdateOption(&OptRapidCommit{})
+	return d
+}
+
+// WithIANA adds or replaces an IA_NA option in a DHCPv6 packet. An existing
+// IA_NA is replaced only if it has the same IAID.
+func WithIANA(iaNa *OptIANA) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		opts := d.Options()
+		for idx, opt := range opts {
+			if o, ok := opt.(*OptIANA); ok && o.IaId == iaNa.IaId {
+				opts[idx] = iaNa
+				d.SetOptions(opts)
+				return d
+			}
+		}
+		d.AddOption(iaNa)
+		return d
+	}
+}
+
+// WithDNS adds or replaces the DNS recursive name server option of a DHCPv6
+// packet, e.g. in a server's replies
+func WithDNS(servers ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptDNSRecursiveNameServer{NameServers: servers})
+		return d
+	}
+}
+
+// WithDomainSearchList adds or replaces the domain search list option of a
+// DHCPv6 packet
+func WithDomainSearchList(domains ...string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptDomainSearchList{DomainSearchList: domains})
+		return d
+	}
+}
+
 // WithNetboot adds bootfile URL and bootfile param options to a DHCPv6 packet.
 func WithNetboot(d DHCPv6) DHCPv6 {
 	msg, ok := d.(*DHCPv6Message)
@@ -53,9 +114,9 @@ func WithUserClass(uc []byte) Modifier {
 }
 
 // WithArchType adds an arch type option to the packet
-func WithArchType(at ArchType) Modifier {
+func WithArchType(at ...iana.Arch) Modifier {
 	return func(d DHCPv6) DHCPv6 {
-		ao := OptClientArchType{ArchType: at}
+		ao := OptClientArchType{ArchTypes: at}
 		d.AddOption(&ao)
 		return d
 	}
@@ -35,3 +35,63 @@ func TestWithServerID(t *testing.T) {
 	sid := opt.(*OptServerId)
 	require.Equal(t, sid.Sid, duid)
 }
+
+func TestWithRequestedOptions(t *testing.T) {
+	// Check if ORO is created when no ORO present
+	m, err := NewMessage(WithRequestedOptions(OPTION_CLIENTID))
+	require.NoError(t, err)
+	opt := m.GetOneOption(OPTION_ORO)
+	require.NotNil(t, opt)
+	oro := opt.(*OptRequestedOption)
+	require.ElementsMatch(t, oro.RequestedOptions(), []OptionCode{OPTION_CLIENTID})
+	// Check if already set options are preserved and duplicates skipped
+	WithRequestedOptions(OPTION_SERVERID, OPTION_CLIENTID)(m)
+	opt = m.GetOneOption(OPTION_ORO)
+	require.NotNil(t, opt)
+	oro = opt.(*OptRequestedOption)
+	require.ElementsMatch(t, oro.RequestedOptions(), []OptionCode{OPTION_CLIENTID, OPTION_SERVERID})
+}
+
+func TestWithRapidCommit(t *testing.T) {
+	m, err := NewMessage(WithRapidCommit)
+	require.NoError(t, err)
+	require.NotNil(t, m.GetOneOption(OPTION_RAPID_COMMIT))
+	require.Equal(t, 1, len(m.GetOption(OPTION_RAPID_COMMIT)))
+}
+
+func TestWithIANA(t *testing.T) {
+	m, err := NewMessage()
+	require.NoError(t, err)
+	WithIANA(&OptIANA{IaId: IAID{1, 2, 3, 4}, T1: 10})(m)
+	WithIANA(&OptIANA{IaId: IAID{5, 6, 7, 8}})(m)
+	require.Equal(t, 2, len(m.GetOption(OPTION_IA_NA)))
+
+	// same IAID replaces the existing IA_NA
+	WithIANA(&OptIANA{IaId: IAID{1, 2, 3, 4}, T1: 20})(m)
+	opts := m.GetOption(OPTION_IA_NA)
+	require.Equal(t, 2, len(opts))
+	require.Equal(t, uint32(20), opts[0].(*OptIANA).T1)
+}
+
+func TestWithDNS(t *testing.T) {
+	m, err := NewMessage(
+		WithDNS(net.ParseIP("2001:db8::1")),
+		WithDNS(net.ParseIP("2001:db8::2"), net.ParseIP("2001:db8::3")),
+	)
+	require.NoError(t, err)
+	opts := m.GetOption(DNS_RECURSIVE_NAME_SERVER)
+	require.Equal(t, 1, len(opts))
+	dns := opts[0].(*OptDNSRecursiveNameServer)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::2"), net.ParseIP("2001:db8::3")}, dns.NameServers)
+}
+
+func TestWithDomainSearchList(t *testing.T) {
+	m, err := NewMessage(
+		WithDomainSearchList("example.com"),
+		WithDomainSearchList("example.org", "example.net"),
+	)
+	require.NoError(t, err)
+	opts := m.GetOption(DOMAIN_SEARCH_LIST)
+	require.Equal(t, 1, len(opts))
+	require.Equal(t, []string{"example.org", "example.net"}, opts[0].(*OptDomainSearchList).DomainSearchList)
+}
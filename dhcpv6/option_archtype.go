@@ -6,42 +6,14 @@ package dhcpv6
 import (
 	"encoding/binary"
 	"fmt"
-)
-
-//ArchType encodes an architecture type in an uint16
-type ArchType uint16
+	"strings"
 
-// see rfc4578
-const (
-	INTEL_X86PC       ArchType = 0
-	NEC_PC98          ArchType = 1
-	EFI_ITANIUM       ArchType = 2
-	DEC_ALPHA         ArchType = 3
-	ARC_X86           ArchType = 4
-	INTEL_LEAN_CLIENT ArchType = 5
-	EFI_IA32          ArchType = 6
-	EFI_BC            ArchType = 7
-	EFI_XSCALE        ArchType = 8
-	EFI_X86_64        ArchType = 9
+	"github.com/insomniacslk/dhcp/iana"
 )
 
-// ArchTypeToStringMap maps an ArchType to a mnemonic name
-var ArchTypeToStringMap = map[ArchType]string{
-	INTEL_X86PC:       "Intel x86PC",
-	NEC_PC98:          "NEC/PC98",
-	EFI_ITANIUM:       "EFI Itanium",
-	DEC_ALPHA:         "DEC Alpha",
-	ARC_X86:           "Arc x86",
-	INTEL_LEAN_CLIENT: "Intel Lean Client",
-	EFI_IA32:          "EFI IA32",
-	EFI_BC:            "EFI BC",
-	EFI_XSCALE:        "EFI Xscale",
-	EFI_X86_64:        "EFI x86-64",
-}
-
 // OptClientArchType represents an option CLIENT_ARCH_TYPE
 type OptClientArchType struct {
-	ArchType ArchType
+	ArchTypes []iana.Arch
 }
 
 func (op *OptClientArchType) Code() OptionCode {
@@ -49,23 +21,30 @@ func (op *OptClientArchType) Code() OptionCode {
 }
 
 func (op *OptClientArchType) ToBytes() []byte {
-	buf := make([]byte, 6)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_CLIENT_ARCH_TYPE))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	binary.BigEndian.PutUint16(buf[4:6], uint16(op.ArchType))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_CLIENT_ARCH_TYPE))
+	buf = appendUint16(buf, uint16(op.Length()))
+	for _, at := range op.ArchTypes {
+		buf = appendUint16(buf, uint16(at))
+	}
 	return buf
 }
 
 func (op *OptClientArchType) Length() int {
-	return 2
+	return 2 * len(op.ArchTypes)
 }
 
 func (op *OptClientArchType) String() string {
-	name, ok := ArchTypeToStringMap[op.ArchType]
-	if !ok {
-		name = "Unknown"
+	atStrings := make([]string, 0)
+	for _, at := range op.ArchTypes {
+		atStrings = append(atStrings, at.String())
 	}
-	return fmt.Sprintf("OptClientArchType{archtype=%v}", name)
+	return fmt.Sprintf("OptClientArchType{archtype=%v}", strings.Join(atStrings, ", "))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptClientArchType) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"arch_types": op.ArchTypes})
 }
 
 // ParseOptClientArchType builds an OptClientArchType structure from
@@ -73,9 +52,11 @@ func (op *OptClientArchType) String() string {
 // length bytes.
 func ParseOptClientArchType(data []byte) (*OptClientArchType, error) {
 	opt := OptClientArchType{}
-	if len(data) != 2 {
-		return nil, fmt.Errorf("Invalid arch type data length. Expected 2 bytes, got %v", len(data))
+	if len(data) == 0 || len(data)%2 != 0 {
+		return nil, fmt.Errorf("Invalid arch type data length. Expected a nonzero multiple of 2 bytes, got %v", len(data))
+	}
+	for idx := 0; idx < len(data); idx += 2 {
+		opt.ArchTypes = append(opt.ArchTypes, iana.Arch(binary.BigEndian.Uint16(data[idx:idx+2])))
 	}
-	opt.ArchType = ArchType(binary.BigEndian.Uint16(data))
 	return &opt, nil
 }
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"testing"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,23 +13,42 @@ func TestParseOptClientArchType(t *testing.T) {
 	}
 	opt, err := ParseOptClientArchType(data)
 	require.NoError(t, err)
-	require.Equal(t, opt.ArchType, EFI_IA32)
+	require.Equal(t, []iana.Arch{iana.EFI_IA32}, opt.ArchTypes)
+}
+
+func TestParseOptClientArchTypeMultiple(t *testing.T) {
+	data := []byte{
+		0, 6, // EFI_IA32
+		0, 11, // EFI_ARM64
+	}
+	opt, err := ParseOptClientArchType(data)
+	require.NoError(t, err)
+	require.Equal(t, []iana.Arch{iana.EFI_IA32, iana.EFI_ARM64}, opt.ArchTypes)
+	require.Equal(t, "OptClientArchType{archtype=EFI IA32, EFI ARM64}", opt.String())
 }
 
 func TestParseOptClientArchTypeInvalid(t *testing.T) {
 	data := []byte{42}
 	_, err := ParseOptClientArchType(data)
 	require.Error(t, err)
+
+	_, err = ParseOptClientArchType([]byte{})
+	require.Error(t, err)
+
+	_, err = ParseOptClientArchType([]byte{0, 6, 0})
+	require.Error(t, err)
 }
 
 func TestOptClientArchTypeParseAndToBytes(t *testing.T) {
 	data := []byte{
 		0, 8, // EFI_XSCALE
+		0, 9, // EFI_X86_64
 	}
 	expected := []byte{
 		0, 61, // OPTION_CLIENT_ARCH_TYPE
-		0, 2, // length
+		0, 4, // length
 		0, 8, // EFI_XSCALE
+		0, 9, // EFI_X86_64
 	}
 	opt, err := ParseOptClientArchType(data)
 	require.NoError(t, err)
@@ -37,7 +57,7 @@ func TestOptClientArchTypeParseAndToBytes(t *testing.T) {
 
 func TestOptClientArchType(t *testing.T) {
 	opt := OptClientArchType{
-		ArchType: EFI_ITANIUM,
+		ArchTypes: []iana.Arch{iana.EFI_ITANIUM},
 	}
 	require.Equal(t, opt.Length(), 2)
 	require.Equal(t, opt.Code(), OPTION_CLIENT_ARCH_TYPE)
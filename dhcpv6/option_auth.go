@@ -0,0 +1,81 @@
+package dhcpv6
+
+// This module defines the OptAuthentication structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"fmt"
+)
+
+// Authentication protocols, algorithms and replay detection methods, as
+// defined by RFC 3315, section 21
+const (
+	AuthProtocolDelayed        uint8 = 2
+	AuthProtocolReconfigureKey uint8 = 3
+
+	AuthAlgorithmHMACMD5 uint8 = 1
+
+	AuthRDMMonotonicCounter uint8 = 0
+)
+
+// OptAuthentication implements the OPTION_AUTH option
+type OptAuthentication struct {
+	Protocol        uint8
+	Algorithm       uint8
+	RDM             uint8
+	ReplayDetection [8]byte
+	AuthInfo        []byte
+}
+
+// Code returns the option code
+func (op *OptAuthentication) Code() OptionCode {
+	return OPTION_AUTH
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptAuthentication) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_AUTH))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Protocol, op.Algorithm, op.RDM)
+	buf = append(buf, op.ReplayDetection[:]...)
+	buf = append(buf, op.AuthInfo...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptAuthentication) Length() int {
+	return 11 + len(op.AuthInfo)
+}
+
+func (op *OptAuthentication) String() string {
+	return fmt.Sprintf("OptAuthentication{protocol=%v, algorithm=%v, rdm=%v, replaydetection=%x, authinfo=%x}",
+		op.Protocol, op.Algorithm, op.RDM, op.ReplayDetection, op.AuthInfo)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptAuthentication) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"protocol":         op.Protocol,
+		"algorithm":        op.Algorithm,
+		"rdm":              op.RDM,
+		"replay_detection": op.ReplayDetection[:],
+		"auth_info":        op.AuthInfo,
+	})
+}
+
+// ParseOptAuthentication builds an OptAuthentication structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptAuthentication(data []byte) (*OptAuthentication, error) {
+	if len(data) < 11 {
+		return nil, errOptionTooShort("Invalid authentication data length. Expected at least 11 bytes, got %v", len(data))
+	}
+	opt := OptAuthentication{}
+	opt.Protocol = data[0]
+	opt.Algorithm = data[1]
+	opt.RDM = data[2]
+	copy(opt.ReplayDetection[:], data[3:11])
+	opt.AuthInfo = append([]byte(nil), data[11:]...)
+	return &opt, nil
+}
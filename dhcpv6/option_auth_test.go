@@ -0,0 +1,54 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptAuthentication(t *testing.T) {
+	data := []byte{
+		3,                      // protocol: reconfigure key
+		1,                      // algorithm: HMAC-MD5
+		0,                      // RDM: monotonic counter
+		0, 0, 0, 0, 0, 0, 0, 1, // replay detection
+		2, 0xaa, 0xbb, // auth info
+	}
+	opt, err := ParseOptAuthentication(data)
+	require.NoError(t, err)
+	require.Equal(t, AuthProtocolReconfigureKey, opt.Protocol)
+	require.Equal(t, AuthAlgorithmHMACMD5, opt.Algorithm)
+	require.Equal(t, AuthRDMMonotonicCounter, opt.RDM)
+	require.Equal(t, [8]byte{0, 0, 0, 0, 0, 0, 0, 1}, opt.ReplayDetection)
+	require.Equal(t, []byte{2, 0xaa, 0xbb}, opt.AuthInfo)
+	require.Equal(t, len(data), opt.Length())
+}
+
+func TestParseOptAuthenticationNoAuthInfo(t *testing.T) {
+	opt, err := ParseOptAuthentication(make([]byte, 11))
+	require.NoError(t, err)
+	require.Empty(t, opt.AuthInfo)
+}
+
+func TestParseOptAuthenticationShortData(t *testing.T) {
+	_, err := ParseOptAuthentication(make([]byte, 10))
+	require.Error(t, err)
+}
+
+func TestOptAuthenticationToBytes(t *testing.T) {
+	opt := OptAuthentication{
+		Protocol:        AuthProtocolReconfigureKey,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             AuthRDMMonotonicCounter,
+		ReplayDetection: [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+		AuthInfo:        []byte{1, 0xaa},
+	}
+	expected := []byte{
+		0, 11, // OPTION_AUTH
+		0, 13, // length
+		3, 1, 0,
+		1, 2, 3, 4, 5, 6, 7, 8,
+		1, 0xaa,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
@@ -0,0 +1,56 @@
+package dhcpv6
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptBCMCSControllerDomainNameList(t *testing.T) {
+	data := []byte{
+		0, 33, // BCMCS_CONTROLLER_DOMAIN_NAME_LIST
+		0, 28, // length
+		5, 'b', 'c', 'm', 'c', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0,
+	}
+	o, err := ParseOption(data)
+	require.NoError(t, err)
+	opt, ok := o.(*OptBCMCSControllerDomainNameList)
+	require.True(t, ok)
+	require.Equal(t, []string{"bcmcs.example.com", "example"}, opt.DomainNames)
+	require.Equal(t, data, opt.ToBytes())
+	require.Equal(t, "OptBCMCSControllerDomainNameList{controllers=[bcmcs.example.com example]}", opt.String())
+}
+
+func TestParseOptBCMCSControllerDomainNameListInvalid(t *testing.T) {
+	_, err := ParseOptBCMCSControllerDomainNameList([]byte{5, 'b', 'c'})
+	require.Error(t, err)
+}
+
+func TestParseOptBCMCSControllerIPv6AddressList(t *testing.T) {
+	data := []byte{
+		0, 34, // BCMCS_CONTROLLER_IPV6_ADDRESS_LIST
+		0, 32, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02,
+	}
+	o, err := ParseOption(data)
+	require.NoError(t, err)
+	opt, ok := o.(*OptBCMCSControllerIPv6AddressList)
+	require.True(t, ok)
+	require.Equal(t, 2, len(opt.Controllers))
+	require.True(t, opt.Controllers[0].Equal(net.ParseIP("2001:db8::1")))
+	require.True(t, opt.Controllers[1].Equal(net.ParseIP("2001:db8::2")))
+	require.Equal(t, data, opt.ToBytes())
+	require.Equal(t, "OptBCMCSControllerIPv6AddressList{controllers=[2001:db8::1 2001:db8::2]}", opt.String())
+	b, err := json.Marshal(opt)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"controllers":["2001:db8::1","2001:db8::2"]`)
+}
+
+func TestParseOptBCMCSControllerIPv6AddressListInvalidLength(t *testing.T) {
+	_, err := ParseOptBCMCSControllerIPv6AddressList(make([]byte, 15))
+	require.Error(t, err)
+}
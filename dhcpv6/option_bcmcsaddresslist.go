@@ -0,0 +1,61 @@
+package dhcpv6
+
+// This module defines the OptBCMCSControllerIPv6AddressList structure.
+// https://www.ietf.org/rfc/rfc4280.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptBCMCSControllerIPv6AddressList implements the
+// BCMCS_CONTROLLER_IPV6_ADDRESS_LIST option
+type OptBCMCSControllerIPv6AddressList struct {
+	Controllers []net.IP
+}
+
+// Code returns the option code
+func (op *OptBCMCSControllerIPv6AddressList) Code() OptionCode {
+	return BCMCS_CONTROLLER_IPV6_ADDRESS_LIST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptBCMCSControllerIPv6AddressList) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(BCMCS_CONTROLLER_IPV6_ADDRESS_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.Controllers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptBCMCSControllerIPv6AddressList) Length() int {
+	return len(op.Controllers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptBCMCSControllerIPv6AddressList) Valid() error {
+	return checkIP6List(op.Controllers)
+}
+
+func (op *OptBCMCSControllerIPv6AddressList) String() string {
+	return fmt.Sprintf("OptBCMCSControllerIPv6AddressList{controllers=%v}", op.Controllers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptBCMCSControllerIPv6AddressList) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"controllers": op.Controllers})
+}
+
+// ParseOptBCMCSControllerIPv6AddressList builds an
+// OptBCMCSControllerIPv6AddressList structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func ParseOptBCMCSControllerIPv6AddressList(data []byte) (*OptBCMCSControllerIPv6AddressList, error) {
+	controllers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptBCMCSControllerIPv6AddressList{Controllers: controllers}
+	return &opt, nil
+}
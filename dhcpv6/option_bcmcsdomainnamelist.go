@@ -0,0 +1,61 @@
+package dhcpv6
+
+// This module defines the OptBCMCSControllerDomainNameList structure.
+// https://www.ietf.org/rfc/rfc4280.txt
+
+import (
+	"fmt"
+)
+
+// OptBCMCSControllerDomainNameList implements the
+// BCMCS_CONTROLLER_DOMAIN_NAME_LIST option
+type OptBCMCSControllerDomainNameList struct {
+	DomainNames []string
+}
+
+// Code returns the option code
+func (op *OptBCMCSControllerDomainNameList) Code() OptionCode {
+	return BCMCS_CONTROLLER_DOMAIN_NAME_LIST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptBCMCSControllerDomainNameList) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(BCMCS_CONTROLLER_DOMAIN_NAME_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, LabelsToBytes(op.DomainNames)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptBCMCSControllerDomainNameList) Length() int {
+	return len(LabelsToBytes(op.DomainNames))
+}
+
+// Valid returns an error if a domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptBCMCSControllerDomainNameList) Valid() error {
+	_, err := labelsToBytes(op.DomainNames)
+	return err
+}
+
+func (op *OptBCMCSControllerDomainNameList) String() string {
+	return fmt.Sprintf("OptBCMCSControllerDomainNameList{controllers=%v}", op.DomainNames)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptBCMCSControllerDomainNameList) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"domains": op.DomainNames})
+}
+
+// ParseOptBCMCSControllerDomainNameList builds an
+// OptBCMCSControllerDomainNameList structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func ParseOptBCMCSControllerDomainNameList(data []byte) (*OptBCMCSControllerDomainNameList, error) {
+	domainNames, err := LabelsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptBCMCSControllerDomainNameList{DomainNames: domainNames}
+	return &opt, nil
+}
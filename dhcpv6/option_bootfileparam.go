@@ -0,0 +1,67 @@
+package dhcpv6
+
+// This module defines the OptBootFileParam structure.
+// https://www.ietf.org/rfc/rfc5970.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptBootFileParam implements the OPT_BOOTFILE_PARAM option
+type OptBootFileParam struct {
+	BootFileParam []string
+}
+
+// Code returns the option code
+func (op *OptBootFileParam) Code() OptionCode {
+	return OPT_BOOTFILE_PARAM
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptBootFileParam) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPT_BOOTFILE_PARAM))
+	buf = appendUint16(buf, uint16(op.Length()))
+	for _, param := range op.BootFileParam {
+		buf = appendUint16(buf, uint16(len(param)))
+		buf = append(buf, param...)
+	}
+	return buf
+}
+
+// Length returns the option length in bytes
+func (op *OptBootFileParam) Length() int {
+	ret := 0
+	for _, param := range op.BootFileParam {
+		ret += 2 + len(param)
+	}
+	return ret
+}
+
+func (op *OptBootFileParam) String() string {
+	return fmt.Sprintf("OptBootFileParam{BootFileParam=%v}", op.BootFileParam)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptBootFileParam) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"params": op.BootFileParam})
+}
+
+// ParseOptBootFileParam builds an OptBootFileParam structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptBootFileParam(data []byte) (*OptBootFileParam, error) {
+	opt := OptBootFileParam{}
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errOptionTooShort("ParseOptBootFileParam: short data: missing length field")
+		}
+		paramLen := int(binary.BigEndian.Uint16(data[:2]))
+		if len(data) < paramLen+2 {
+			return nil, errOptionTooShort("ParseOptBootFileParam: short data: less than %d bytes", paramLen+2)
+		}
+		opt.BootFileParam = append(opt.BootFileParam, string(data[2:paramLen+2]))
+		data = data[2+paramLen:]
+	}
+	return &opt, nil
+}
@@ -0,0 +1,39 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptBootFileParam(t *testing.T) {
+	data := []byte{
+		0, 4, 'r', 'o', 'o', 't',
+		0, 7, 'c', 'o', 'n', 's', 'o', 'l', 'e',
+	}
+	opt, err := ParseOptBootFileParam(data)
+	require.NoError(t, err)
+	require.Equal(t, []string{"root", "console"}, opt.BootFileParam)
+	require.Equal(t, len(data), opt.Length())
+}
+
+func TestParseOptBootFileParamShortData(t *testing.T) {
+	// declared length overruns the buffer
+	_, err := ParseOptBootFileParam([]byte{0, 10, 'r', 'o', 'o', 't'})
+	require.Error(t, err)
+
+	// truncated length field
+	_, err = ParseOptBootFileParam([]byte{0, 4, 'r', 'o', 'o', 't', 0})
+	require.Error(t, err)
+}
+
+func TestOptBootFileParamToBytes(t *testing.T) {
+	opt := OptBootFileParam{BootFileParam: []string{"root", "console"}}
+	expected := []byte{
+		0, 60, // OPT_BOOTFILE_PARAM
+		0, 15, // length
+		0, 4, 'r', 'o', 'o', 't',
+		0, 7, 'c', 'o', 'n', 's', 'o', 'l', 'e',
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
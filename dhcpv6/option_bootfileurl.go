@@ -4,15 +4,37 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc5970.txt
 
 import (
-	"encoding/binary"
 	"fmt"
+	"net/url"
 )
 
-// OptBootFileURL implements the OPT_BOOTFILE_URL option
+// bootFileURLSchemes are the URL schemes accepted by NewOptBootFileURL
+var bootFileURLSchemes = map[string]bool{
+	"tftp":  true,
+	"http":  true,
+	"https": true,
+	"ftp":   true,
+}
+
+// OptBootFileURL implements the OPT_BOOTFILE_URL option. BootFileURL holds
+// the raw option data, which is not required to be a valid URL, see URL.
 type OptBootFileURL struct {
 	BootFileURL []byte
 }
 
+// NewOptBootFileURL returns an OptBootFileURL for the given URL, which must
+// be an absolute URL with a tftp, http, https or ftp scheme
+func NewOptBootFileURL(u string) (*OptBootFileURL, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	if !bootFileURLSchemes[parsed.Scheme] {
+		return nil, fmt.Errorf("Invalid boot file URL %q: unsupported scheme %q", u, parsed.Scheme)
+	}
+	return &OptBootFileURL{BootFileURL: []byte(u)}, nil
+}
+
 // Code returns the option code
 func (op *OptBootFileURL) Code() OptionCode {
 	return OPT_BOOTFILE_URL
@@ -20,9 +42,9 @@ func (op *OptBootFileURL) Code() OptionCode {
 
 // ToBytes serializes the option and returns it as a sequence of bytes
 func (op *OptBootFileURL) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPT_BOOTFILE_URL))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(len(op.BootFileURL)))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPT_BOOTFILE_URL))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, op.BootFileURL...)
 	return buf
 }
@@ -32,12 +54,24 @@ func (op *OptBootFileURL) Length() int {
 	return len(op.BootFileURL)
 }
 
+// URL parses the boot file URL
+func (op *OptBootFileURL) URL() (*url.URL, error) {
+	return url.Parse(string(op.BootFileURL))
+}
+
 func (op *OptBootFileURL) String() string {
 	return fmt.Sprintf("OptBootFileURL{BootFileUrl=%s}", op.BootFileURL)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptBootFileURL) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"url": string(op.BootFileURL)})
+}
+
 // ParseOptBootFileURL builds an OptBootFileURL structure from a sequence
 // of bytes. The input data does not include option code and length bytes.
+// The data is not validated, so that options carrying something other than a
+// URL can still be inspected.
 func ParseOptBootFileURL(data []byte) (*OptBootFileURL, error) {
 	opt := OptBootFileURL{}
 	opt.BootFileURL = append([]byte(nil), data...)
@@ -31,3 +31,41 @@ func TestOptBootFileURLToBytes(t *testing.T) {
 		t.Fatalf("Invalid ToBytes result. Expected %v, got %v", expected, toBytes)
 	}
 }
+
+func TestOptBootFileURLURL(t *testing.T) {
+	opt, err := NewOptBootFileURL("tftp://[2001:db8::1]/boot/pxelinux.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := opt.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "tftp" || u.Hostname() != "2001:db8::1" || u.Path != "/boot/pxelinux.0" {
+		t.Fatalf("Invalid URL. Expected tftp://[2001:db8::1]/boot/pxelinux.0, got %v", u)
+	}
+}
+
+func TestOptBootFileURLMalformed(t *testing.T) {
+	// parsing tolerates non-URL data, only the accessor fails
+	data := []byte("http://[::1%zz/\x00")
+	opt, err := ParseOptBootFileURL(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opt.BootFileURL, data) {
+		t.Fatalf("Invalid boot file URL. Expected %v, got %v", data, opt.BootFileURL)
+	}
+	if _, err := opt.URL(); err == nil {
+		t.Fatal("Expected error on malformed URL, got nil")
+	}
+	if _, err := NewOptBootFileURL(string(data)); err == nil {
+		t.Fatal("Expected error on malformed URL, got nil")
+	}
+	if _, err := NewOptBootFileURL("file:///boot/pxelinux.0"); err == nil {
+		t.Fatal("Expected error on unsupported scheme, got nil")
+	}
+	if _, err := NewOptBootFileURL("/boot/pxelinux.0"); err == nil {
+		t.Fatal("Expected error on relative URL, got nil")
+	}
+}
@@ -0,0 +1,65 @@
+package dhcpv6
+
+// This module defines the OptClientData structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"fmt"
+)
+
+// OptClientData implements the OPTION_CLIENT_DATA option, which carries the
+// data a server holds about a client in a LEASEQUERY-REPLY, e.g. its client
+// ID, addresses, prefixes and OPTION_CLT_TIME.
+type OptClientData struct {
+	Options Options
+}
+
+// Code returns the option code
+func (op *OptClientData) Code() OptionCode {
+	return OPTION_CLIENT_DATA
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptClientData) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_CLIENT_DATA))
+	buf = appendUint16(buf, uint16(op.Length()))
+	for _, opt := range op.Options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptClientData) Length() int {
+	l := 0
+	for _, opt := range op.Options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+func (op *OptClientData) String() string {
+	return fmt.Sprintf("OptClientData{options=%v}", op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptClientData) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": jsonOptions(op.Options)})
+}
+
+// ParseOptClientData builds an OptClientData structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptClientData(data []byte) (*OptClientData, error) {
+	return parseOptClientData(data, 0)
+}
+
+// parseOptClientData parses an OPTION_CLIENT_DATA found at the given relay
+// depth, see parseOptLQRelayData, which it passes to its OPTION_LQ_RELAY_DATA
+func parseOptClientData(data []byte, depth int) (*OptClientData, error) {
+	options, errs := optionsFromBytes(data, true, depth, MaxOptions)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return &OptClientData{Options: options}, nil
+}
@@ -0,0 +1,123 @@
+package dhcpv6
+
+// This module defines the OptClientFQDN structure.
+// https://www.ietf.org/rfc/rfc4704.txt
+
+import (
+	"fmt"
+)
+
+// Client FQDN option flags, as defined by RFC 4704 section 4.1
+const (
+	FQDNFlagS uint8 = 1 << 0 // the server should perform the AAAA RR update
+	FQDNFlagO uint8 = 1 << 1 // the server has overridden the client's S bit
+	FQDNFlagN uint8 = 1 << 2 // the server should not perform any update
+)
+
+// OptClientFQDN implements the OPTION_CLIENT_FQDN option. DomainName is
+// fully qualified unless Partial is set, in which case it is encoded without
+// the terminating root label, see RFC 4704 section 4.2. An empty DomainName
+// is encoded as no name at all, and "." as the root label alone.
+type OptClientFQDN struct {
+	Flags      uint8
+	DomainName string
+	Partial    bool
+}
+
+// Code returns the option code
+func (op *OptClientFQDN) Code() OptionCode {
+	return FQDN
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptClientFQDN) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(FQDN))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Flags)
+	buf = append(buf, op.nameBytes()...)
+	return buf
+}
+
+// nameBytes returns the encoded domain name, see OptClientFQDN
+func (op *OptClientFQDN) nameBytes() []byte {
+	if op.DomainName == "" {
+		return nil
+	}
+	name := LabelToBytes(op.DomainName)
+	if op.Partial {
+		// drop the root label
+		name = name[:len(name)-1]
+	}
+	return name
+}
+
+// Length returns the option length
+func (op *OptClientFQDN) Length() int {
+	return 1 + len(op.nameBytes())
+}
+
+// Valid returns an error if the domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptClientFQDN) Valid() error {
+	if op.DomainName == "" {
+		return nil
+	}
+	_, err := labelToBytes(op.DomainName)
+	return err
+}
+
+func (op *OptClientFQDN) String() string {
+	return fmt.Sprintf("OptClientFQDN{flags=0x%02x, domainname=%v, partial=%v}", op.Flags, op.DomainName, op.Partial)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptClientFQDN) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"flags":       op.Flags,
+		"domain_name": op.DomainName,
+		"partial":     op.Partial,
+	})
+}
+
+// ServerShouldUpdate returns true if the N bit is not set, that is if the
+// server is expected to perform DNS updates on behalf of the client.
+func (op *OptClientFQDN) ServerShouldUpdate() bool {
+	return op.Flags&FQDNFlagN == 0
+}
+
+// ClientWantsServerUpdate returns true if the S bit is set, that is if the
+// client asks the server to perform the AAAA RR update.
+func (op *OptClientFQDN) ClientWantsServerUpdate() bool {
+	return op.Flags&FQDNFlagS != 0
+}
+
+// ParseOptClientFQDN builds an OptClientFQDN structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptClientFQDN(data []byte) (*OptClientFQDN, error) {
+	if len(data) < 1 {
+		return nil, errOptionTooShort("Invalid client FQDN data length. Expected at least 1 byte, got %v", len(data))
+	}
+	opt := OptClientFQDN{}
+	opt.Flags = data[0]
+	name := data[1:]
+	if len(name) == 0 {
+		// no name, e.g. when the client lets the server choose one
+		return &opt, nil
+	}
+	if name[len(name)-1] != 0 {
+		// a partial name lacks the root label, add it to parse the name
+		opt.Partial = true
+		name = append(append([]byte(nil), name...), 0)
+	}
+	domainName, err := domainNameFromBytes(name)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid client FQDN: %v", err)
+	}
+	if domainName == "" {
+		// the root label alone
+		domainName = "."
+	}
+	opt.DomainName = domainName
+	return &opt, nil
+}
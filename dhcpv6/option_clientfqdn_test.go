@@ -0,0 +1,87 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptClientFQDN(t *testing.T) {
+	data := []byte{
+		FQDNFlagS, // flags
+		4, 'h', 'o', 's', 't', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	opt, err := ParseOptClientFQDN(data)
+	require.NoError(t, err)
+	require.Equal(t, FQDNFlagS, opt.Flags)
+	require.Equal(t, "host.example.com", opt.DomainName)
+	require.Equal(t, len(data), opt.Length())
+	require.True(t, opt.ClientWantsServerUpdate())
+	require.True(t, opt.ServerShouldUpdate())
+}
+
+func TestParseOptClientFQDNNoUpdate(t *testing.T) {
+	opt, err := ParseOptClientFQDN([]byte{FQDNFlagN, 4, 'h', 'o', 's', 't', 0})
+	require.NoError(t, err)
+	require.False(t, opt.ClientWantsServerUpdate())
+	require.False(t, opt.ServerShouldUpdate())
+}
+
+func TestParseOptClientFQDNShortData(t *testing.T) {
+	_, err := ParseOptClientFQDN([]byte{})
+	require.Error(t, err)
+
+	_, err = ParseOptClientFQDN([]byte{0, 4, 'h', 'o'})
+	require.Error(t, err)
+}
+
+func TestOptClientFQDNToBytes(t *testing.T) {
+	opt := OptClientFQDN{
+		Flags:      FQDNFlagS | FQDNFlagO,
+		DomainName: "host.example.com",
+	}
+	expected := []byte{
+		0, 39, // FQDN
+		0, 19, // length
+		FQDNFlagS | FQDNFlagO,
+		4, 'h', 'o', 's', 't', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestOptClientFQDNEmptyName(t *testing.T) {
+	// flags only, RFC 4704 section 4.1
+	data := []byte{0, 39, 0, 1, FQDNFlagS}
+	opt, err := ParseOption(data)
+	require.NoError(t, err)
+	fqdn := opt.(*OptClientFQDN)
+	require.Equal(t, "", fqdn.DomainName)
+	require.False(t, fqdn.Partial)
+	require.Equal(t, 1, fqdn.Length())
+	require.Equal(t, data, fqdn.ToBytes())
+
+	// the root label alone
+	data = []byte{0, 39, 0, 2, FQDNFlagS, 0}
+	opt, err = ParseOption(data)
+	require.NoError(t, err)
+	require.Equal(t, ".", opt.(*OptClientFQDN).DomainName)
+	require.Equal(t, data, opt.ToBytes())
+}
+
+func TestOptClientFQDNPartialName(t *testing.T) {
+	data := []byte{0, 39, 0, 6, FQDNFlagS, 4, 'h', 'o', 's', 't'}
+	opt, err := ParseOption(data)
+	require.NoError(t, err)
+	fqdn := opt.(*OptClientFQDN)
+	require.Equal(t, "host", fqdn.DomainName)
+	require.True(t, fqdn.Partial)
+	require.Equal(t, 6, fqdn.Length())
+	require.Equal(t, data, fqdn.ToBytes())
+
+	partial := OptClientFQDN{DomainName: "host.example", Partial: true}
+	require.Equal(t, []byte{0, 39, 0, 14, 0, 4, 'h', 'o', 's', 't', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e'}, partial.ToBytes())
+
+	// a partial name must still be a single name
+	_, err = ParseOptClientFQDN([]byte{0, 4, 'h', 'o', 's', 't', 0, 3, 'c', 'o', 'm'})
+	require.Error(t, err)
+}
@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3315.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 )
 
@@ -18,13 +17,30 @@ func (op *OptClientId) Code() OptionCode {
 }
 
 func (op *OptClientId) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_CLIENTID))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_CLIENTID))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, op.Cid.ToBytes()...)
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptClientId) Clone() Option {
+	return &OptClientId{Cid: op.Cid.clone()}
+}
+
+// Equal returns true if other is an OptClientId with an equal DUID
+func (op *OptClientId) Equal(other Option) bool {
+	o, ok := other.(*OptClientId)
+	return ok && op.Cid.Equal(&o.Cid)
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptClientId) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_CLIENTID, op.Length())
+	op.Cid.SerializeTo(s)
+}
+
 func (op *OptClientId) Length() int {
 	return op.Cid.Length()
 }
@@ -33,13 +49,18 @@ func (op *OptClientId) String() string {
 	return fmt.Sprintf("OptClientId{cid=%v}", op.Cid.String())
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptClientId) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"duid": &op.Cid})
+}
+
 // ParseOptClientId builds an OptClientId structure from a sequence
 // of bytes. The input data does not include option code and length
 // bytes.
 func ParseOptClientId(data []byte) (*OptClientId, error) {
 	if len(data) < 2 {
 		// at least the DUID type is necessary to continue
-		return nil, fmt.Errorf("Invalid OptClientId data: shorter than 2 bytes")
+		return nil, errOptionTooShort("Invalid OptClientId data: shorter than 2 bytes")
 	}
 	opt := OptClientId{}
 	cid, err := DuidFromBytes(data)
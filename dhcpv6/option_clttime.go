@@ -0,0 +1,57 @@
+package dhcpv6
+
+// This module defines the OptCLTTime structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// OptCLTTime implements the OPTION_CLT_TIME option, the time elapsed since
+// the server last communicated with the client. It is carried on the wire in
+// seconds: ToBytes truncates it to seconds, and clamps it between zero and
+// 2^32-1 seconds like the IA address lifetimes.
+type OptCLTTime struct {
+	CLTTime time.Duration
+}
+
+// Code returns the option code
+func (op *OptCLTTime) Code() OptionCode {
+	return OPTION_CLT_TIME
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptCLTTime) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_CLT_TIME))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, lifetimeToSeconds(op.CLTTime))
+	return buf
+}
+
+// Length returns the option length
+func (op *OptCLTTime) Length() int {
+	return 4
+}
+
+func (op *OptCLTTime) String() string {
+	return fmt.Sprintf("OptCLTTime{clttime=%v}", op.CLTTime)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptCLTTime) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"clt_time": op.CLTTime.Seconds()})
+}
+
+// ParseOptCLTTime builds an OptCLTTime structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptCLTTime(data []byte) (*OptCLTTime, error) {
+	if len(data) != 4 {
+		return nil, fmt.Errorf("Invalid CLT time data length. Expected 4 bytes, got %v", len(data))
+	}
+	opt := OptCLTTime{}
+	opt.CLTTime = time.Duration(binary.BigEndian.Uint32(data)) * time.Second
+	return &opt, nil
+}
@@ -0,0 +1,62 @@
+package dhcpv6
+
+// This module defines the OptDHCP4oDHCP6Server structure.
+// https://www.ietf.org/rfc/rfc7341.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptDHCP4oDHCP6Server represents a OPTION_DHCP4_O_DHCP6_SERVER option. An
+// empty list of servers means that the client should use the default
+// DHCPv4-over-DHCPv6 servers.
+type OptDHCP4oDHCP6Server struct {
+	DHCP4oDHCP6Servers []net.IP
+}
+
+// Code returns the option code
+func (op *OptDHCP4oDHCP6Server) Code() OptionCode {
+	return OPTION_DHCP4_O_DHCP6_SERVER
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptDHCP4oDHCP6Server) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_DHCP4_O_DHCP6_SERVER))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.DHCP4oDHCP6Servers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptDHCP4oDHCP6Server) Length() int {
+	return len(op.DHCP4oDHCP6Servers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptDHCP4oDHCP6Server) Valid() error {
+	return checkIP6List(op.DHCP4oDHCP6Servers)
+}
+
+func (op *OptDHCP4oDHCP6Server) String() string {
+	return fmt.Sprintf("OptDHCP4oDHCP6Server{4o6-servers=%v}", op.DHCP4oDHCP6Servers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptDHCP4oDHCP6Server) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"servers": op.DHCP4oDHCP6Servers})
+}
+
+// ParseOptDHCP4oDHCP6Server builds an OptDHCP4oDHCP6Server structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptDHCP4oDHCP6Server(data []byte) (*OptDHCP4oDHCP6Server, error) {
+	servers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptDHCP4oDHCP6Server{DHCP4oDHCP6Servers: servers}
+	return &opt, nil
+}
@@ -0,0 +1,45 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptDHCP4oDHCP6Server(t *testing.T) {
+	data := []byte{
+		0x2a, 0x03, 0x28, 0x80, 0xff, 0xfe, 0x00, 0x0c, 0xfa, 0xce, 0xb0, 0x0c, 0x00, 0x00, 0x00, 0x35,
+	}
+	expected := []net.IP{
+		net.IP(data),
+	}
+	opt, err := ParseOptDHCP4oDHCP6Server(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, opt.DHCP4oDHCP6Servers)
+	require.Equal(t, OPTION_DHCP4_O_DHCP6_SERVER, opt.Code())
+	require.Equal(t, 16, opt.Length())
+	require.Contains(t, opt.String(), "4o6-servers=[2a03:2880:fffe:c:face:b00c:0:35]")
+}
+
+func TestParseOptDHCP4oDHCP6ServerEmpty(t *testing.T) {
+	opt, err := ParseOptDHCP4oDHCP6Server([]byte{})
+	require.NoError(t, err)
+	require.Empty(t, opt.DHCP4oDHCP6Servers)
+	require.Equal(t, 0, opt.Length())
+}
+
+func TestParseOptDHCP4oDHCP6ServerInvalidLength(t *testing.T) {
+	_, err := ParseOptDHCP4oDHCP6Server([]byte{0x2a, 0x03, 0x28, 0x80})
+	require.Error(t, err)
+}
+
+func TestOptDHCP4oDHCP6ServerToBytes(t *testing.T) {
+	ip1 := net.ParseIP("2a03:2880:fffe:c:face:b00c:0:35")
+	ip2 := net.ParseIP("2001:4860:4860::8888")
+	opt := OptDHCP4oDHCP6Server{DHCP4oDHCP6Servers: []net.IP{ip1, ip2}}
+	expected := []byte{0, 88, 0, 32}
+	expected = append(expected, ip1...)
+	expected = append(expected, ip2...)
+	require.Equal(t, expected, opt.ToBytes())
+}
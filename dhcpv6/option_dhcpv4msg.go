@@ -0,0 +1,85 @@
+package dhcpv6
+
+// This module defines the OptDHCPv4Msg structure.
+// https://www.ietf.org/rfc/rfc7341.txt
+
+import (
+	"fmt"
+	"sync"
+)
+
+// OptDHCPv4Msg represents an OPTION_DHCPV4_MSG option, which carries a
+// DHCPv4 message in DHCPV4-QUERY and DHCPV4-RESPONSE messages. The DHCPv4
+// message is kept as raw bytes, so that this package does not depend on a
+// DHCPv4 implementation; see RegisterDHCPv4Parser to decode it.
+type OptDHCPv4Msg struct {
+	Msg []byte
+}
+
+// Code returns the option code
+func (op *OptDHCPv4Msg) Code() OptionCode {
+	return OPTION_DHCPV4_MSG
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptDHCPv4Msg) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_DHCPV4_MSG))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Msg...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptDHCPv4Msg) Length() int {
+	return len(op.Msg)
+}
+
+func (op *OptDHCPv4Msg) String() string {
+	if msg, err := op.Message(); err == nil {
+		return fmt.Sprintf("OptDHCPv4Msg{msg=%v}", msg)
+	}
+	return fmt.Sprintf("OptDHCPv4Msg{msg=%v bytes}", len(op.Msg))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptDHCPv4Msg) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"msg": op.Msg})
+}
+
+// Message decodes the encapsulated DHCPv4 message with the parser registered
+// with RegisterDHCPv4Parser. It returns ErrNoDHCPv4Parser if none is.
+func (op *OptDHCPv4Msg) Message() (fmt.Stringer, error) {
+	dhcpv4ParserMu.RLock()
+	fn := dhcpv4Parser
+	dhcpv4ParserMu.RUnlock()
+	if fn == nil {
+		return nil, ErrNoDHCPv4Parser
+	}
+	return fn(op.Msg)
+}
+
+// ParseOptDHCPv4Msg builds an OptDHCPv4Msg structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptDHCPv4Msg(data []byte) (*OptDHCPv4Msg, error) {
+	opt := OptDHCPv4Msg{Msg: data}
+	return &opt, nil
+}
+
+// DHCPv4Parser parses a DHCPv4 message carried in an OptDHCPv4Msg, e.g.
+// dhcpv4.FromBytes wrapped to return a fmt.Stringer
+type DHCPv4Parser func(data []byte) (fmt.Stringer, error)
+
+var (
+	dhcpv4ParserMu sync.RWMutex
+	dhcpv4Parser   DHCPv4Parser
+)
+
+// RegisterDHCPv4Parser registers the parser used by OptDHCPv4Msg.Message,
+// replacing any parser previously registered. Passing a nil parser
+// unregisters it.
+func RegisterDHCPv4Parser(fn DHCPv4Parser) {
+	dhcpv4ParserMu.Lock()
+	defer dhcpv4ParserMu.Unlock()
+	dhcpv4Parser = fn
+}
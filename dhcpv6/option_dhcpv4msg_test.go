@@ -0,0 +1,76 @@
+package dhcpv6
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// a truncated DHCPDISCOVER, opaque to this package
+var dhcpv4Payload = []byte{
+	1, 1, 6, 0, // op, htype, hlen, hops
+	0xaa, 0xbb, 0xcc, 0xdd, // xid
+	0, 0, 0x80, 0, // secs, flags
+}
+
+func TestParseOptDHCPv4Msg(t *testing.T) {
+	opt, err := ParseOptDHCPv4Msg(dhcpv4Payload)
+	require.NoError(t, err)
+	require.Equal(t, OPTION_DHCPV4_MSG, opt.Code())
+	require.Equal(t, dhcpv4Payload, opt.Msg)
+	require.Equal(t, len(dhcpv4Payload), opt.Length())
+	require.Equal(t, "OptDHCPv4Msg{msg=12 bytes}", opt.String())
+}
+
+func TestOptDHCPv4MsgRoundTrip(t *testing.T) {
+	data := append([]byte{0, 87, 0, 12}, dhcpv4Payload...)
+	opt, err := ParseOption(data)
+	require.NoError(t, err)
+	require.IsType(t, &OptDHCPv4Msg{}, opt)
+	require.Equal(t, data, opt.ToBytes())
+
+	// inside a DHCPV4-QUERY message
+	msg := DHCPv6Message{messageType: DHCPV4_QUERY, transactionID: 0x800000}
+	require.Error(t, msg.Validate())
+	msg.AddOption(opt)
+	require.NoError(t, msg.Validate())
+	parsed, err := FromBytes(msg.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, DHCPV4_QUERY, parsed.Type())
+	require.Equal(t, "DHCPV4-QUERY", parsed.(*DHCPv6Message).MessageTypeToString())
+	require.Equal(t, dhcpv4Payload, parsed.GetOneOption(OPTION_DHCPV4_MSG).(*OptDHCPv4Msg).Msg)
+}
+
+type fakeDHCPv4 struct {
+	xid []byte
+}
+
+func (f *fakeDHCPv4) String() string {
+	return fmt.Sprintf("DHCPv4(xid=%x)", f.xid)
+}
+
+func TestOptDHCPv4MsgMessage(t *testing.T) {
+	opt := OptDHCPv4Msg{Msg: dhcpv4Payload}
+	_, err := opt.Message()
+	require.Equal(t, ErrNoDHCPv4Parser, err)
+
+	RegisterDHCPv4Parser(func(data []byte) (fmt.Stringer, error) {
+		if len(data) < 8 {
+			return nil, errors.New("short DHCPv4 message")
+		}
+		return &fakeDHCPv4{xid: data[4:8]}, nil
+	})
+	defer RegisterDHCPv4Parser(nil)
+	msg, err := opt.Message()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xaa, 0xbb, 0xcc, 0xdd}, msg.(*fakeDHCPv4).xid)
+	require.Equal(t, "OptDHCPv4Msg{msg=DHCPv4(xid=aabbccdd)}", opt.String())
+
+	// the raw bytes are still available when the parser fails
+	short := OptDHCPv4Msg{Msg: []byte{1, 2}}
+	_, err = short.Message()
+	require.Error(t, err)
+	require.Equal(t, "OptDHCPv4Msg{msg=2 bytes}", short.String())
+}
@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3646.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 	"net"
 )
@@ -22,12 +21,10 @@ func (op *OptDNSRecursiveNameServer) Code() OptionCode {
 // ToBytes returns the option serialized to bytes, including option code and
 // length
 func (op *OptDNSRecursiveNameServer) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(DNS_RECURSIVE_NAME_SERVER))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	for _, ns := range op.NameServers {
-		buf = append(buf, ns...)
-	}
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(DNS_RECURSIVE_NAME_SERVER))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.NameServers)...)
 	return buf
 }
 
@@ -36,22 +33,29 @@ func (op *OptDNSRecursiveNameServer) Length() int {
 	return len(op.NameServers) * net.IPv6len
 }
 
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptDNSRecursiveNameServer) Valid() error {
+	return checkIP6List(op.NameServers)
+}
+
 func (op *OptDNSRecursiveNameServer) String() string {
 	return fmt.Sprintf("OptDNSRecursiveNameServer{nameservers=%v}", op.NameServers)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptDNSRecursiveNameServer) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"name_servers": op.NameServers})
+}
+
 // ParseOptDNSRecursiveNameServer builds an OptDNSRecursiveNameServer structure
 // from a sequence of bytes. The input data does not include option code and length
 // bytes.
 func ParseOptDNSRecursiveNameServer(data []byte) (*OptDNSRecursiveNameServer, error) {
-	if len(data)%net.IPv6len != 0 {
-		return nil, fmt.Errorf("Invalid OptDNSRecursiveNameServer data: length is not a multiple of %d", net.IPv6len)
-	}
-	opt := OptDNSRecursiveNameServer{}
-	var nameServers []net.IP
-	for i := 0; i < len(data); i += net.IPv6len {
-		nameServers = append(nameServers, data[i:i+net.IPv6len])
+	nameServers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
 	}
-	opt.NameServers = nameServers
+	opt := OptDNSRecursiveNameServer{NameServers: nameServers}
 	return &opt, nil
 }
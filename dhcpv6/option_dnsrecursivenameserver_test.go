@@ -33,3 +33,40 @@ func TestOptDNSRecursiveNameServerToBytes(t *testing.T) {
 	opt := OptDNSRecursiveNameServer{NameServers: nameservers}
 	require.Equal(t, opt.ToBytes(), expected)
 }
+
+func TestParseOptDNSRecursiveNameServerTruncated(t *testing.T) {
+	data := []byte{
+		0x2a, 0x03, 0x28, 0x80, 0xff, 0xfe, 0x00, 0x0c, 0xfa, 0xce, 0xb0, 0x0c, 0x00, 0x00, 0x00, 0x35,
+		0x20, // truncated second address
+	}
+	_, err := ParseOptDNSRecursiveNameServer(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "got 17")
+}
+
+func TestOptDNSRecursiveNameServerToBytesIPv4(t *testing.T) {
+	// a 4-byte address is serialized in its IPv4-mapped form, and reported
+	// by Valid
+	opt := OptDNSRecursiveNameServer{NameServers: []net.IP{net.IPv4(8, 8, 8, 8).To4()}}
+	require.Error(t, opt.Valid())
+	data := opt.ToBytes()
+	require.Equal(t, 4+opt.Length(), len(data))
+	parsed, err := ParseOptDNSRecursiveNameServer(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, []net.IP{net.ParseIP("::ffff:8.8.8.8")}, parsed.NameServers)
+
+	opt = OptDNSRecursiveNameServer{NameServers: []net.IP{net.ParseIP("2001:db8::53"), nil}}
+	require.Error(t, opt.Valid())
+}
+
+func TestOptDNSRecursiveNameServerIPv4MappedRoundTrip(t *testing.T) {
+	// net.ParseIP returns IPv4 addresses in their 16-byte IPv4-mapped form
+	opt := OptDNSRecursiveNameServer{NameServers: []net.IP{net.ParseIP("1.2.3.4"), net.ParseIP("2001:db8::53")}}
+	require.NoError(t, opt.Valid())
+	data := opt.ToBytes()
+	require.Equal(t, []byte(net.ParseIP("1.2.3.4")), data[4:20])
+	parsed, err := ParseOption(data)
+	require.NoError(t, err)
+	require.Equal(t, opt.NameServers, parsed.(*OptDNSRecursiveNameServer).NameServers)
+	require.Equal(t, data, CloneOption(parsed).ToBytes())
+}
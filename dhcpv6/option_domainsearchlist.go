@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3646.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 )
 
@@ -18,25 +17,33 @@ func (op *OptDomainSearchList) Code() OptionCode {
 }
 
 func (op *OptDomainSearchList) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(DOMAIN_SEARCH_LIST))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(DOMAIN_SEARCH_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, LabelsToBytes(op.DomainSearchList)...)
 	return buf
 }
 
 func (op *OptDomainSearchList) Length() int {
-	var length int
-	for _, label := range op.DomainSearchList {
-		length += len(label) + 2 // add the first and the last length bytes
-	}
-	return length
+	return len(LabelsToBytes(op.DomainSearchList))
+}
+
+// Valid returns an error if a domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptDomainSearchList) Valid() error {
+	_, err := labelsToBytes(op.DomainSearchList)
+	return err
 }
 
 func (op *OptDomainSearchList) String() string {
 	return fmt.Sprintf("OptDomainSearchList{searchlist=%v}", op.DomainSearchList)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptDomainSearchList) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"domains": op.DomainSearchList})
+}
+
 // build an OptDomainSearchList structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptDomainSearchList(data []byte) (*OptDomainSearchList, error) {
@@ -33,3 +33,21 @@ func TestOptDomainSearchListToBytes(t *testing.T) {
 	}
 	require.Equal(t, opt.ToBytes(), expected)
 }
+
+func TestParseOptDomainSearchListCompressed(t *testing.T) {
+	data := []byte{
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		6, 's', 'u', 'b', 'n', 'e', 't', 0xc0, 0x00, // pointer to offset 0
+	}
+	_, err := ParseOptDomainSearchList(data)
+	require.Error(t, err)
+}
+
+func TestOptDomainSearchListLength(t *testing.T) {
+	opt := OptDomainSearchList{
+		DomainSearchList: []string{"example.com.", ""},
+	}
+	// "example.com." encodes to 13 bytes, the root domain to a single 0
+	require.Equal(t, 14, opt.Length())
+	require.Equal(t, opt.Length(), len(opt.ToBytes())-4)
+}
@@ -0,0 +1,58 @@
+package dhcpv6
+
+// This module defines the OptEchoRequest structure.
+// https://www.ietf.org/rfc/rfc4994.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptEchoRequest implements the ECHO_REQUEST option, used by relay agents to
+// ask the server to echo the listed options back in the RELAY_REPL message
+type OptEchoRequest struct {
+	Options []OptionCode
+}
+
+// Code returns the option code
+func (op *OptEchoRequest) Code() OptionCode {
+	return ECHO_REQUEST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptEchoRequest) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(ECHO_REQUEST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	for _, code := range op.Options {
+		buf = appendUint16(buf, uint16(code))
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptEchoRequest) Length() int {
+	return len(op.Options) * 2
+}
+
+func (op *OptEchoRequest) String() string {
+	return fmt.Sprintf("OptEchoRequest{options=%v}", optionCodesToString(op.Options))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptEchoRequest) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": op.Options})
+}
+
+// ParseOptEchoRequest builds an OptEchoRequest structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptEchoRequest(data []byte) (*OptEchoRequest, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("Invalid OptEchoRequest data length. Expected a multiple of 2 bytes, got %v", len(data))
+	}
+	opt := OptEchoRequest{}
+	for i := 0; i < len(data); i += 2 {
+		opt.Options = append(opt.Options, OptionCode(binary.BigEndian.Uint16(data[i:i+2])))
+	}
+	return &opt, nil
+}
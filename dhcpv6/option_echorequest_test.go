@@ -0,0 +1,32 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptEchoRequest(t *testing.T) {
+	data := []byte{0, 37, 0, 18}
+	opt, err := ParseOptEchoRequest(data)
+	require.NoError(t, err)
+	require.Equal(t, []OptionCode{OPTION_REMOTE_ID, OPTION_INTERFACE_ID}, opt.Options)
+	require.Equal(t, 4, opt.Length())
+	require.Equal(t, "OptEchoRequest{options=[OPTION_REMOTE_ID, OPTION_INTERFACE_ID]}", opt.String())
+}
+
+func TestParseOptEchoRequestInvalidLength(t *testing.T) {
+	_, err := ParseOptEchoRequest([]byte{0, 37, 0})
+	require.Error(t, err)
+}
+
+func TestOptEchoRequestToBytes(t *testing.T) {
+	opt := OptEchoRequest{Options: []OptionCode{OPTION_REMOTE_ID}}
+	require.Equal(t, []byte{0, 43, 0, 2, 0, 37}, opt.ToBytes())
+}
+
+func TestParseOptionEchoRequest(t *testing.T) {
+	opt, err := ParseOption([]byte{0, 43, 0, 2, 0, 37})
+	require.NoError(t, err)
+	require.IsType(t, &OptEchoRequest{}, opt)
+}
@@ -6,10 +6,23 @@ package dhcpv6
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
+	"time"
 )
 
+// OptElapsedTime implements the OPTION_ELAPSED_TIME option. The elapsed time
+// is carried on the wire in hundredths of a second.
 type OptElapsedTime struct {
-	ElapsedTime uint16
+	elapsedTime uint16
+}
+
+// NewOptElapsedTime returns an OptElapsedTime for the given duration. Values
+// that do not fit in the option are clamped to the maximum, 0xffff
+// hundredths of a second.
+func NewOptElapsedTime(d time.Duration) *OptElapsedTime {
+	opt := OptElapsedTime{}
+	opt.SetElapsedTime(d)
+	return &opt
 }
 
 func (op *OptElapsedTime) Code() OptionCode {
@@ -17,19 +30,61 @@ func (op *OptElapsedTime) Code() OptionCode {
 }
 
 func (op *OptElapsedTime) ToBytes() []byte {
-	buf := make([]byte, 6)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_ELAPSED_TIME))
-	binary.BigEndian.PutUint16(buf[2:4], 2)
-	binary.BigEndian.PutUint16(buf[4:6], uint16(op.ElapsedTime))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_ELAPSED_TIME))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint16(buf, op.elapsedTime)
 	return buf
 }
 
+// Clone returns a copy of the option
+func (op *OptElapsedTime) Clone() Option {
+	c := *op
+	return &c
+}
+
+// Equal returns true if other is an OptElapsedTime with the same value
+func (op *OptElapsedTime) Equal(other Option) bool {
+	o, ok := other.(*OptElapsedTime)
+	return ok && op.elapsedTime == o.elapsedTime
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptElapsedTime) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_ELAPSED_TIME, 2)
+	s.Write16(op.elapsedTime)
+}
+
+// ElapsedTime returns the elapsed time as a time.Duration
+func (op *OptElapsedTime) ElapsedTime() time.Duration {
+	return time.Duration(op.elapsedTime) * 10 * time.Millisecond
+}
+
+// SetElapsedTime sets the elapsed time, truncated to hundredths of a second
+// and clamped to the maximum value the option can carry
+func (op *OptElapsedTime) SetElapsedTime(d time.Duration) {
+	hundredths := d / (10 * time.Millisecond)
+	switch {
+	case hundredths < 0:
+		op.elapsedTime = 0
+	case hundredths > math.MaxUint16:
+		op.elapsedTime = math.MaxUint16
+	default:
+		op.elapsedTime = uint16(hundredths)
+	}
+}
+
 func (op *OptElapsedTime) Length() int {
 	return 2
 }
 
 func (op *OptElapsedTime) String() string {
-	return fmt.Sprintf("OptElapsedTime{elapsedtime=%v}", op.ElapsedTime)
+	return fmt.Sprintf("OptElapsedTime{elapsedtime=%v}", op.ElapsedTime())
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptElapsedTime) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"elapsed_time": op.ElapsedTime().Seconds()})
 }
 
 // build an OptElapsedTime structure from a sequence of bytes.
@@ -39,6 +94,6 @@ func ParseOptElapsedTime(data []byte) (*OptElapsedTime, error) {
 	if len(data) != 2 {
 		return nil, fmt.Errorf("Invalid elapsed time data length. Expected 2 bytes, got %v", len(data))
 	}
-	opt.ElapsedTime = binary.BigEndian.Uint16(data)
+	opt.elapsedTime = binary.BigEndian.Uint16(data)
 	return &opt, nil
 }
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"bytes"
 	"testing"
+	"time"
 )
 
 func TestOptElapsedTime(t *testing.T) {
@@ -13,8 +14,16 @@ func TestOptElapsedTime(t *testing.T) {
 	if optLen := opt.Length(); optLen != 2 {
 		t.Fatalf("Invalid length. Expected 2, got %v", optLen)
 	}
-	if elapsedTime := opt.ElapsedTime; elapsedTime != 0xaabb {
-		t.Fatalf("Invalid elapsed time. Expected 0xaabb, got %v", elapsedTime)
+	if elapsedTime := opt.ElapsedTime(); elapsedTime != 0xaabb*10*time.Millisecond {
+		t.Fatalf("Invalid elapsed time. Expected %v, got %v", 0xaabb*10*time.Millisecond, elapsedTime)
+	}
+}
+
+func TestParseOptElapsedTimeInvalidLength(t *testing.T) {
+	for _, data := range [][]byte{{}, {0xaa}, {0xaa, 0xbb, 0xcc}} {
+		if _, err := ParseOptElapsedTime(data); err == nil {
+			t.Fatalf("Expected error for %d bytes of data, got nil", len(data))
+		}
 	}
 }
 
@@ -28,16 +37,35 @@ func TestOptElapsedTimeToBytes(t *testing.T) {
 
 func TestOptElapsedTimeSetGetElapsedTime(t *testing.T) {
 	opt := OptElapsedTime{}
-	opt.ElapsedTime = 10
-	if elapsedTime := opt.ElapsedTime; elapsedTime != 10 {
-		t.Fatalf("Invalid elapsed time. Expected 10, got %v", elapsedTime)
+	opt.SetElapsedTime(10 * time.Second)
+	if elapsedTime := opt.ElapsedTime(); elapsedTime != 10*time.Second {
+		t.Fatalf("Invalid elapsed time. Expected 10s, got %v", elapsedTime)
+	}
+	expected := []byte{0, 8, 0, 2, 0x03, 0xe8} // 1000 hundredths
+	if toBytes := opt.ToBytes(); !bytes.Equal(expected, toBytes) {
+		t.Fatalf("Invalid ToBytes output. Expected %v, got %v", expected, toBytes)
+	}
+}
+
+func TestNewOptElapsedTimeClamp(t *testing.T) {
+	opt := NewOptElapsedTime(700 * time.Second)
+	if elapsedTime := opt.ElapsedTime(); elapsedTime != 655350*time.Millisecond {
+		t.Fatalf("Invalid elapsed time. Expected 655.35s, got %v", elapsedTime)
+	}
+	expected := []byte{0, 8, 0, 2, 0xff, 0xff}
+	if toBytes := opt.ToBytes(); !bytes.Equal(expected, toBytes) {
+		t.Fatalf("Invalid ToBytes output. Expected %v, got %v", expected, toBytes)
+	}
+
+	opt = NewOptElapsedTime(-time.Second)
+	if elapsedTime := opt.ElapsedTime(); elapsedTime != 0 {
+		t.Fatalf("Invalid elapsed time. Expected 0, got %v", elapsedTime)
 	}
 }
 
 func TestOptElapsedTimeString(t *testing.T) {
-	opt := OptElapsedTime{}
-	opt.ElapsedTime = 10
-	expected := "OptElapsedTime{elapsedtime=10}"
+	opt := NewOptElapsedTime(100 * time.Millisecond)
+	expected := "OptElapsedTime{elapsedtime=100ms}"
 	if optString := opt.String(); optString != expected {
 		t.Fatalf("Invalid elapsed time string. Expected %v, got %v", expected, optString)
 	}
@@ -0,0 +1,127 @@
+package dhcpv6
+
+// This module defines the OptGeoConfCivic structure.
+// https://www.ietf.org/rfc/rfc4776.txt
+
+import (
+	"fmt"
+)
+
+// maxCAValueLength is the maximum length of a civic address element value,
+// which has a one-byte length field
+const maxCAValueLength = 255
+
+// caValue returns the value of a civic address element, truncated to
+// maxCAValueLength bytes so that its length fits in the length field
+func (ca *CivicAddressElement) caValue() []byte {
+	if len(ca.CAValue) > maxCAValueLength {
+		return ca.CAValue[:maxCAValueLength]
+	}
+	return ca.CAValue
+}
+
+// CivicAddressElement is a civic address element of an OptGeoConfCivic,
+// e.g. a city or a street name. CAValue is at most 255 bytes long: longer
+// values are rejected by OptGeoConfCivic.Valid, and truncated by ToBytes.
+type CivicAddressElement struct {
+	CAType  uint8
+	CAValue []byte
+}
+
+// OptGeoConfCivic implements the OPTION_GEOCONF_CIVIC option. What tells
+// which location the option describes: 0 for the DHCP server, 1 for the
+// network element closest to the client and 2 for the client. CountryCode is
+// the ISO 3166 two-letter country code.
+type OptGeoConfCivic struct {
+	What                 uint8
+	CountryCode          [2]byte
+	CivicAddressElements []CivicAddressElement
+}
+
+// Code returns the option code
+func (op *OptGeoConfCivic) Code() OptionCode {
+	return OPTION_GEOCONF_CIVIC
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptGeoConfCivic) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_GEOCONF_CIVIC))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.What)
+	buf = append(buf, op.CountryCode[:]...)
+	for i := range op.CivicAddressElements {
+		ca := &op.CivicAddressElements[i]
+		buf = append(buf, ca.CAType, uint8(len(ca.caValue())))
+		buf = append(buf, ca.caValue()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptGeoConfCivic) Length() int {
+	l := 3
+	for i := range op.CivicAddressElements {
+		l += 2 + len(op.CivicAddressElements[i].caValue())
+	}
+	return l
+}
+
+// Valid returns an error if the value of a civic address element is longer
+// than 255 bytes
+func (op *OptGeoConfCivic) Valid() error {
+	for _, ca := range op.CivicAddressElements {
+		if len(ca.CAValue) > maxCAValueLength {
+			return fmt.Errorf("Invalid GeoConf civic address element %v length. Expected at most %v bytes, got %v", ca.CAType, maxCAValueLength, len(ca.CAValue))
+		}
+	}
+	return nil
+}
+
+func (op *OptGeoConfCivic) String() string {
+	elements := make([]string, 0, len(op.CivicAddressElements))
+	for _, ca := range op.CivicAddressElements {
+		elements = append(elements, fmt.Sprintf("%d=%q", ca.CAType, ca.CAValue))
+	}
+	return fmt.Sprintf("OptGeoConfCivic{what=%v, countrycode=%s, elements=%v}",
+		op.What, op.CountryCode[:], elements)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptGeoConfCivic) MarshalJSON() ([]byte, error) {
+	elements := make([]map[string]interface{}, 0, len(op.CivicAddressElements))
+	for _, ca := range op.CivicAddressElements {
+		elements = append(elements, map[string]interface{}{"ca_type": ca.CAType, "ca_value": string(ca.CAValue)})
+	}
+	return marshalOptionJSON(op, map[string]interface{}{
+		"what":         op.What,
+		"country_code": string(op.CountryCode[:]),
+		"elements":     elements,
+	})
+}
+
+// ParseOptGeoConfCivic builds an OptGeoConfCivic structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptGeoConfCivic(data []byte) (*OptGeoConfCivic, error) {
+	if len(data) < 3 {
+		return nil, errOptionTooShort("Invalid GeoConf civic data length. Expected at least 3 bytes, got %v", len(data))
+	}
+	opt := OptGeoConfCivic{What: data[0]}
+	copy(opt.CountryCode[:], data[1:3])
+	for pos := 3; pos < len(data); {
+		if len(data)-pos < 2 {
+			return nil, fmt.Errorf("Invalid GeoConf civic address element: truncated header at offset %v", pos)
+		}
+		caType, caLength := data[pos], int(data[pos+1])
+		pos += 2
+		if len(data)-pos < caLength {
+			return nil, fmt.Errorf("Invalid GeoConf civic address element %v length. Declared %v, actual %v", caType, caLength, len(data)-pos)
+		}
+		opt.CivicAddressElements = append(opt.CivicAddressElements, CivicAddressElement{
+			CAType:  caType,
+			CAValue: append([]byte(nil), data[pos:pos+caLength]...),
+		})
+		pos += caLength
+	}
+	return &opt, nil
+}
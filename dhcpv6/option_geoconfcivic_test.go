@@ -0,0 +1,73 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptGeoConfCivic(t *testing.T) {
+	data := []byte{
+		2,        // what: client
+		'U', 'S', // country code
+		0, 2, 'e', 'n', // language
+		1, 2, 'C', 'A', // state
+		3, 13, 'S', 'a', 'n', ' ', 'F', 'r', 'a', 'n', 'c', 'i', 's', 'c', 'o', // city
+		22, 0, // empty element
+	}
+	opt, err := ParseOptGeoConfCivic(data)
+	require.NoError(t, err)
+	require.Equal(t, uint8(2), opt.What)
+	require.Equal(t, [2]byte{'U', 'S'}, opt.CountryCode)
+	require.Equal(t, []CivicAddressElement{
+		{CAType: 0, CAValue: []byte("en")},
+		{CAType: 1, CAValue: []byte("CA")},
+		{CAType: 3, CAValue: []byte("San Francisco")},
+		{CAType: 22, CAValue: nil},
+	}, opt.CivicAddressElements)
+
+	// round trip
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 36, 0, byte(len(data))}, data...), opt.ToBytes())
+	parsed, err := ParseOption(opt.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, opt, parsed)
+}
+
+func TestParseOptGeoConfCivicInvalid(t *testing.T) {
+	// shorter than the header
+	_, err := ParseOptGeoConfCivic([]byte{2, 'U'})
+	require.Error(t, err)
+	// truncated element header
+	_, err = ParseOptGeoConfCivic([]byte{2, 'U', 'S', 3})
+	require.Error(t, err)
+	// element longer than the option
+	_, err = ParseOptGeoConfCivic([]byte{2, 'U', 'S', 3, 5, 'R', 'o', 'm'})
+	require.Error(t, err)
+
+	// no elements
+	opt, err := ParseOptGeoConfCivic([]byte{0, 'I', 'T'})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(opt.CivicAddressElements))
+}
+
+func TestOptGeoConfCivicValueTooLong(t *testing.T) {
+	opt := OptGeoConfCivic{
+		What:        2,
+		CountryCode: [2]byte{'U', 'S'},
+		CivicAddressElements: []CivicAddressElement{
+			{CAType: 3, CAValue: make([]byte, 256)},
+		},
+	}
+	require.Error(t, opt.Valid())
+	require.Error(t, ValidOption(&opt))
+
+	// the value is truncated, so that the option stays well-formed
+	require.Equal(t, 3+2+255, opt.Length())
+	parsed, err := ParseOptGeoConfCivic(opt.ToBytes()[4:])
+	require.NoError(t, err)
+	require.Equal(t, 255, len(parsed.CivicAddressElements[0].CAValue))
+
+	opt.CivicAddressElements[0].CAValue = make([]byte, 255)
+	require.NoError(t, opt.Valid())
+}
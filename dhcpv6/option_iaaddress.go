@@ -7,16 +7,47 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"time"
 )
 
+// InfiniteLifetime is the lifetime encoded on the wire as 0xffffffff, which
+// RFC 8415, section 7.7 defines as infinity
+const InfiniteLifetime = time.Duration(0xffffffff) * time.Second
+
+// lifetimeToSeconds converts a lifetime to its on-wire representation,
+// clamping it to InfiniteLifetime
+func lifetimeToSeconds(d time.Duration) uint32 {
+	if d <= 0 {
+		return 0
+	}
+	if d >= InfiniteLifetime {
+		return 0xffffffff
+	}
+	return uint32(d / time.Second)
+}
+
 // OptIAAddress represents an OPTION_IAADDR
 type OptIAAddress struct {
 	IPv6Addr          net.IP
-	PreferredLifetime uint32
-	ValidLifetime     uint32
+	preferredLifetime uint32
+	validLifetime     uint32
 	Options           []Option
 }
 
+// NewOptIAAddress returns an IA address option for the given address and
+// lifetimes, see SetPreferredLifetime and SetValidLifetime. It returns an
+// error if ip is not an IPv6 address, IPv4-mapped addresses included.
+func NewOptIAAddress(ip net.IP, preferred, valid time.Duration) (*OptIAAddress, error) {
+	ip6, err := ensureIP6(ip)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptIAAddress{IPv6Addr: ip6}
+	opt.SetPreferredLifetime(preferred)
+	opt.SetValidLifetime(valid)
+	return &opt, nil
+}
+
 // Code returns the option's code
 func (op *OptIAAddress) Code() OptionCode {
 	return OPTION_IAADDR
@@ -24,18 +55,78 @@ func (op *OptIAAddress) Code() OptionCode {
 
 // ToBytes serializes the option and returns it as a sequence of bytes
 func (op *OptIAAddress) ToBytes() []byte {
-	buf := make([]byte, 28)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_IAADDR))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	copy(buf[4:20], op.IPv6Addr[:])
-	binary.BigEndian.PutUint32(buf[20:24], op.PreferredLifetime)
-	binary.BigEndian.PutUint32(buf[24:28], op.ValidLifetime)
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_IAADDR))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendIP6(buf, op.IPv6Addr)
+	buf = appendUint32(buf, op.preferredLifetime)
+	buf = appendUint32(buf, op.validLifetime)
 	for _, opt := range op.Options {
 		buf = append(buf, opt.ToBytes()...)
 	}
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptIAAddress) Clone() Option {
+	c := *op
+	c.IPv6Addr = append(net.IP(nil), op.IPv6Addr...)
+	c.Options = Options(op.Options).Clone()
+	return &c
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptIAAddress) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_IAADDR, op.Length())
+	s.buf = appendIP6(s.buf, op.IPv6Addr)
+	s.Write32(op.preferredLifetime)
+	s.Write32(op.validLifetime)
+	s.WriteOptions(op.Options)
+}
+
+// PreferredLifetime returns the preferred lifetime of the address. An
+// infinite lifetime is returned as InfiniteLifetime.
+func (op *OptIAAddress) PreferredLifetime() time.Duration {
+	return time.Duration(op.preferredLifetime) * time.Second
+}
+
+// SetPreferredLifetime sets the preferred lifetime of the address, truncated
+// to seconds
+func (op *OptIAAddress) SetPreferredLifetime(d time.Duration) {
+	op.preferredLifetime = lifetimeToSeconds(d)
+}
+
+// ValidLifetime returns the valid lifetime of the address. An infinite
+// lifetime is returned as InfiniteLifetime.
+func (op *OptIAAddress) ValidLifetime() time.Duration {
+	return time.Duration(op.validLifetime) * time.Second
+}
+
+// SetValidLifetime sets the valid lifetime of the address, truncated to
+// seconds
+func (op *OptIAAddress) SetValidLifetime(d time.Duration) {
+	op.validLifetime = lifetimeToSeconds(d)
+}
+
+// Valid checks that the address is a 16-byte address, see checkIP6, and that
+// the preferred lifetime is not greater than the valid lifetime, as required
+// by RFC 8415, section 21.6. Only finite, nonzero lifetimes are compared.
+// Like OptIANA.Valid, this is not enforced while parsing.
+func (op *OptIAAddress) Valid() error {
+	if err := checkIP6(op.IPv6Addr); err != nil {
+		return err
+	}
+	pl, vl := op.preferredLifetime, op.validLifetime
+	if pl == 0 || vl == 0 || pl == 0xffffffff || vl == 0xffffffff {
+		return nil
+	}
+	if pl > vl {
+		return fmt.Errorf("Invalid IA Address lifetimes: preferred lifetime (%v) is greater than valid lifetime (%v)",
+			op.PreferredLifetime(), op.ValidLifetime())
+	}
+	return nil
+}
+
 // Length returns the option length
 func (op *OptIAAddress) Length() int {
 	opLen := 24
@@ -47,7 +138,17 @@ func (op *OptIAAddress) Length() int {
 
 func (op *OptIAAddress) String() string {
 	return fmt.Sprintf("OptIAAddress{ipv6addr=%v, preferredlifetime=%v, validlifetime=%v, options=%v}",
-		net.IP(op.IPv6Addr[:]), op.PreferredLifetime, op.ValidLifetime, op.Options)
+		net.IP(op.IPv6Addr[:]), op.preferredLifetime, op.validLifetime, op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptIAAddress) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"ipv6_addr":          op.IPv6Addr.String(),
+		"preferred_lifetime": op.preferredLifetime,
+		"valid_lifetime":     op.validLifetime,
+		"options":            jsonOptions(op.Options),
+	})
 }
 
 // ParseOptIAAddress builds an OptIAAddress structure from a sequence
@@ -57,11 +158,11 @@ func ParseOptIAAddress(data []byte) (*OptIAAddress, error) {
 	var err error
 	opt := OptIAAddress{}
 	if len(data) < 24 {
-		return nil, fmt.Errorf("Invalid IA Address data length. Expected at least 24 bytes, got %v", len(data))
+		return nil, errOptionTooShort("Invalid IA Address data length. Expected at least 24 bytes, got %v", len(data))
 	}
 	opt.IPv6Addr = net.IP(data[:16])
-	opt.PreferredLifetime = binary.BigEndian.Uint32(data[16:20])
-	opt.ValidLifetime = binary.BigEndian.Uint32(data[20:24])
+	opt.preferredLifetime = binary.BigEndian.Uint32(data[16:20])
+	opt.validLifetime = binary.BigEndian.Uint32(data[20:24])
 	opt.Options, err = OptionsFromBytes(data[24:])
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -18,8 +19,8 @@ func TestOptIAAddressParse(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 30, opt.Length())
 	require.Equal(t, net.IP(ipaddr), opt.IPv6Addr)
-	require.Equal(t, uint32(0x0a0b0c0d), opt.PreferredLifetime)
-	require.Equal(t, uint32(0x0e0f0102), opt.ValidLifetime)
+	require.Equal(t, 0x0a0b0c0d*time.Second, opt.PreferredLifetime())
+	require.Equal(t, 0x0e0f0102*time.Second, opt.ValidLifetime())
 }
 
 func TestOptIAAddressParseInvalidTooShort(t *testing.T) {
@@ -57,13 +58,98 @@ func TestOptIAAddressToBytes(t *testing.T) {
 	}...)
 	opt := OptIAAddress{
 		IPv6Addr:          net.IP(ipBytes),
-		PreferredLifetime: 0x0a0b0c0d,
-		ValidLifetime:     0x0e0f0102,
+		preferredLifetime: 0x0a0b0c0d,
+		validLifetime:     0x0e0f0102,
 		Options: []Option{
-			&OptElapsedTime{
-				ElapsedTime: 0xaabb,
-			},
+			&OptElapsedTime{elapsedTime: 0xaabb},
 		},
 	}
 	require.Equal(t, expected, opt.ToBytes())
 }
+
+func TestOptIAAddressInfiniteLifetime(t *testing.T) {
+	ipaddr := []byte{0x24, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	data := append(ipaddr, []byte{
+		0xff, 0xff, 0xff, 0xff, // preferred lifetime
+		0xff, 0xff, 0xff, 0xff, // valid lifetime
+	}...)
+	opt, err := ParseOptIAAddress(data)
+	require.NoError(t, err)
+	require.Equal(t, InfiniteLifetime, opt.PreferredLifetime())
+	require.Equal(t, InfiniteLifetime, opt.ValidLifetime())
+	require.NoError(t, opt.Valid())
+
+	// infinite preferred lifetime with a finite valid lifetime is not checked
+	opt.SetValidLifetime(time.Hour)
+	require.NoError(t, opt.Valid())
+}
+
+func TestOptIAAddressSetLifetime(t *testing.T) {
+	opt := OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")}
+	opt.SetPreferredLifetime(InfiniteLifetime + time.Hour)
+	opt.SetValidLifetime(90 * time.Minute)
+	require.Equal(t, InfiniteLifetime, opt.PreferredLifetime())
+	require.Equal(t, 90*time.Minute, opt.ValidLifetime())
+	require.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0x15, 0x18}, opt.ToBytes()[20:28])
+}
+
+func TestNewOptIAAddress(t *testing.T) {
+	opt, err := NewOptIAAddress(net.ParseIP("2001:db8::1"), time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, net.ParseIP("2001:db8::1"), opt.IPv6Addr)
+	require.Equal(t, time.Hour, opt.PreferredLifetime())
+	require.Equal(t, 2*time.Hour, opt.ValidLifetime())
+
+	for _, ip := range []net.IP{nil, net.IPv4(192, 0, 2, 1), net.ParseIP("::ffff:192.0.2.1"), {1, 2, 3}} {
+		_, err := NewOptIAAddress(ip, time.Hour, time.Hour)
+		require.Error(t, err, "address %v", ip)
+	}
+}
+
+func TestOptIAAddressValid(t *testing.T) {
+	opt := OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")}
+	opt.SetPreferredLifetime(2 * time.Hour)
+	opt.SetValidLifetime(time.Hour)
+	require.Error(t, opt.Valid())
+
+	opt.SetValidLifetime(3 * time.Hour)
+	require.NoError(t, opt.Valid())
+
+	opt.SetValidLifetime(0)
+	require.NoError(t, opt.Valid())
+}
+
+func TestOptIAAddressToBytesIPv4(t *testing.T) {
+	for _, tc := range []struct {
+		ip       net.IP
+		expected net.IP
+	}{
+		{net.IPv4(192, 0, 2, 1).To4(), net.ParseIP("::ffff:192.0.2.1")},
+		{nil, net.IPv6unspecified},
+	} {
+		opt := OptIAAddress{IPv6Addr: tc.ip}
+		require.Error(t, opt.Valid())
+		// the option stays well-formed
+		data := opt.ToBytes()
+		require.Equal(t, 4+opt.Length(), len(data))
+		s := NewSerializer(0)
+		opt.SerializeTo(s)
+		require.Equal(t, data, s.Bytes())
+		parsed, err := ParseOptIAAddress(data[4:])
+		require.NoError(t, err)
+		require.Equal(t, []byte(tc.expected), []byte(parsed.IPv6Addr))
+	}
+}
+
+func TestOptIAAddressIPv4MappedRoundTrip(t *testing.T) {
+	mapped := net.IP{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 1, 2, 3, 4}
+	data := append(append([]byte(nil), mapped...), 0, 0, 0, 1, 0, 0, 0, 2)
+	opt, err := ParseOptIAAddress(data)
+	require.NoError(t, err)
+	require.NoError(t, opt.Valid())
+	require.Equal(t, append([]byte{0, 5, 0, 24}, data...), opt.ToBytes())
+	s := NewSerializer(0)
+	opt.SerializeTo(s)
+	require.Equal(t, opt.ToBytes(), s.Bytes())
+	require.Equal(t, opt.ToBytes(), CloneOption(opt).ToBytes())
+}
@@ -5,8 +5,10 @@ package dhcpv6
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
+	"time"
 )
 
 type OptIAPrefix struct {
@@ -14,7 +16,30 @@ type OptIAPrefix struct {
 	validLifetime     uint32
 	prefixLength      byte
 	ipv6Prefix        [16]byte
-	options           []byte
+	options           Options
+}
+
+// NewOptIAPrefix returns an IA prefix option for the given IPv6 prefix and
+// lifetimes, which are truncated to seconds
+func NewOptIAPrefix(prefix *net.IPNet, preferred, valid time.Duration) (*OptIAPrefix, error) {
+	if prefix == nil {
+		return nil, errors.New("Invalid IA Prefix: prefix cannot be nil")
+	}
+	ip, err := ensureIP6(prefix.IP)
+	if err != nil {
+		return nil, err
+	}
+	ones, bits := prefix.Mask.Size()
+	if bits != 8*net.IPv6len {
+		return nil, fmt.Errorf("Invalid IA Prefix: %v is not an IPv6 prefix", prefix)
+	}
+	opt := OptIAPrefix{
+		preferredLifetime: lifetimeToSeconds(preferred),
+		validLifetime:     lifetimeToSeconds(valid),
+		prefixLength:      byte(ones),
+	}
+	copy(opt.ipv6Prefix[:], ip)
+	return &opt, nil
 }
 
 func (op *OptIAPrefix) Code() OptionCode {
@@ -22,14 +47,16 @@ func (op *OptIAPrefix) Code() OptionCode {
 }
 
 func (op *OptIAPrefix) ToBytes() []byte {
-	buf := make([]byte, 12)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_IAPREFIX))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	binary.BigEndian.PutUint32(buf[4:8], op.preferredLifetime)
-	binary.BigEndian.PutUint32(buf[8:12], op.validLifetime)
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_IAPREFIX))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, op.preferredLifetime)
+	buf = appendUint32(buf, op.validLifetime)
 	buf = append(buf, op.prefixLength)
 	buf = append(buf, op.ipv6Prefix[:]...)
-	buf = append(buf, op.options...)
+	for _, opt := range op.options {
+		buf = append(buf, opt.ToBytes()...)
+	}
 	return buf
 }
 
@@ -65,16 +92,28 @@ func (op *OptIAPrefix) SetIPv6Prefix(p [16]byte) {
 	op.ipv6Prefix = p
 }
 
-func (op *OptIAPrefix) Options() []byte {
+// Prefix returns the delegated prefix, combining the prefix and its length
+func (op *OptIAPrefix) Prefix() *net.IPNet {
+	return &net.IPNet{
+		IP:   append(net.IP(nil), op.ipv6Prefix[:]...),
+		Mask: net.CIDRMask(int(op.prefixLength), 128),
+	}
+}
+
+func (op *OptIAPrefix) Options() Options {
 	return op.options
 }
 
-func (op *OptIAPrefix) SetOptions(options []byte) {
+func (op *OptIAPrefix) SetOptions(options Options) {
 	op.options = options
 }
 
 func (op *OptIAPrefix) Length() int {
-	return 25 + len(op.options)
+	l := 25
+	for _, opt := range op.options {
+		l += 4 + opt.Length()
+	}
+	return l
 }
 
 func (op *OptIAPrefix) String() string {
@@ -82,17 +121,34 @@ func (op *OptIAPrefix) String() string {
 		op.preferredLifetime, op.validLifetime, op.prefixLength, net.IP(op.ipv6Prefix[:]), op.options)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptIAPrefix) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"preferred_lifetime": op.preferredLifetime,
+		"valid_lifetime":     op.validLifetime,
+		"prefix":             fmt.Sprintf("%v/%v", net.IP(op.ipv6Prefix[:]), op.prefixLength),
+		"options":            jsonOptions(op.options),
+	})
+}
+
 // build an OptIAPrefix structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptIAPrefix(data []byte) (*OptIAPrefix, error) {
 	opt := OptIAPrefix{}
-	if len(data) < 12 {
-		return nil, fmt.Errorf("Invalid IA for Prefix Delegation data length. Expected at least 12 bytes, got %v", len(data))
+	if len(data) < 25 {
+		return nil, errOptionTooShort("Invalid IA for Prefix Delegation data length. Expected at least 25 bytes, got %v", len(data))
 	}
 	opt.preferredLifetime = binary.BigEndian.Uint32(data[:4])
 	opt.validLifetime = binary.BigEndian.Uint32(data[4:8])
 	opt.prefixLength = data[8]
+	if opt.prefixLength > 128 {
+		return nil, fmt.Errorf("Invalid IA prefix length %v: must be at most 128", opt.prefixLength)
+	}
 	copy(opt.ipv6Prefix[:], data[9:25])
-	copy(opt.options, data[25:])
+	var err error
+	opt.options, err = OptionsFromBytes(data[25:])
+	if err != nil {
+		return nil, err
+	}
 	return &opt, nil
 }
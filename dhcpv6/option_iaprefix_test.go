@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net"
 	"testing"
+	"time"
 )
 
 func TestOptIAPrefix(t *testing.T) {
@@ -50,3 +51,75 @@ func TestOptIAPrefixToBytes(t *testing.T) {
 		t.Fatalf("Invalid ToBytes result. Expected %v, got %v", expected, toBytes)
 	}
 }
+
+func TestOptIAPrefixParseShort(t *testing.T) {
+	buf := []byte{
+		0xaa, 0xbb, 0xcc, 0xdd, // preferredLifetime
+		0xee, 0xff, 0x00, 0x11, // validLifetime
+		36,                  // prefixLength
+		0, 0, 0, 0, 0, 0, 0, // truncated ipv6Prefix
+	}
+	if _, err := ParseOptIAPrefix(buf); err == nil {
+		t.Fatal("Expected error on truncated IA prefix, got nil")
+	}
+}
+
+func TestOptIAPrefixParseInvalidPrefixLength(t *testing.T) {
+	buf := []byte{
+		0, 0, 0, 0, // preferredLifetime
+		0, 0, 0, 0, // validLifetime
+		129,                                                        // prefixLength
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // ipv6Prefix
+	}
+	if _, err := ParseOptIAPrefix(buf); err == nil {
+		t.Fatal("Expected error on prefix length 129, got nil")
+	}
+}
+
+func TestOptIAPrefixNestedOptions(t *testing.T) {
+	buf := []byte{
+		0, 0, 0x0e, 0x10, // preferredLifetime
+		0, 0, 0x1c, 0x20, // validLifetime
+		56,                                                            // prefixLength
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0, // ipv6Prefix
+		0, 13, 0, 6, 0, 6, 'n', 'o', 'p', 'e', // OPTION_STATUS_CODE
+	}
+	opt, err := ParseOptIAPrefix(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opt.Options()) != 1 {
+		t.Fatalf("Invalid options. Expected 1 option, got %v", opt.Options())
+	}
+	if sc, ok := opt.Options()[0].(*OptStatusCode); !ok || string(sc.StatusMessage) != "nope" {
+		t.Fatalf("Invalid options. Expected OptStatusCode, got %v", opt.Options()[0])
+	}
+	if prefix := opt.Prefix().String(); prefix != "2001:db8:0:ff00::/56" {
+		t.Fatalf("Invalid prefix. Expected 2001:db8:0:ff00::/56, got %v", prefix)
+	}
+	if l := opt.Length(); l != len(buf) {
+		t.Fatalf("Invalid length. Expected %v, got %v", len(buf), l)
+	}
+	expected := append([]byte{0, 26, 0, byte(len(buf))}, buf...)
+	if toBytes := opt.ToBytes(); !bytes.Equal(toBytes, expected) {
+		t.Fatalf("Invalid ToBytes result. Expected %v, got %v", expected, toBytes)
+	}
+
+	// truncated nested option
+	if _, err := ParseOptIAPrefix(buf[:len(buf)-1]); err == nil {
+		t.Fatal("Expected error on truncated nested option, got nil")
+	}
+}
+
+func TestNewOptIAPrefixInvalid(t *testing.T) {
+	_, prefix, err := net.ParseCIDR("192.0.2.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewOptIAPrefix(prefix, time.Hour, time.Hour); err == nil {
+		t.Fatal("Expected an error for an IPv4 prefix, got none")
+	}
+	if _, err := NewOptIAPrefix(nil, time.Hour, time.Hour); err == nil {
+		t.Fatal("Expected an error for a nil prefix, got none")
+	}
+}
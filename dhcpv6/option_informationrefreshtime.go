@@ -0,0 +1,55 @@
+package dhcpv6
+
+// This module defines the OptInformationRefreshTime structure.
+// https://www.ietf.org/rfc/rfc4242.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// OptInformationRefreshTime implements the INFORMATION_REFRESH_TIME option
+type OptInformationRefreshTime struct {
+	InformationRefreshTime time.Duration
+}
+
+// Code returns the option code
+func (op *OptInformationRefreshTime) Code() OptionCode {
+	return INFORMATION_REFRESH_TIME
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptInformationRefreshTime) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(INFORMATION_REFRESH_TIME))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, uint32(op.InformationRefreshTime/time.Second))
+	return buf
+}
+
+// Length returns the option length
+func (op *OptInformationRefreshTime) Length() int {
+	return 4
+}
+
+func (op *OptInformationRefreshTime) String() string {
+	return fmt.Sprintf("InformationRefreshTime: %v", op.InformationRefreshTime)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptInformationRefreshTime) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"refresh_time": uint32(op.InformationRefreshTime / time.Second)})
+}
+
+// ParseOptInformationRefreshTime builds an OptInformationRefreshTime
+// structure from a sequence of bytes. The input data does not include option
+// code and length bytes.
+func ParseOptInformationRefreshTime(data []byte) (*OptInformationRefreshTime, error) {
+	if len(data) != 4 {
+		return nil, fmt.Errorf("Invalid information refresh time data length. Expected 4 bytes, got %v", len(data))
+	}
+	opt := OptInformationRefreshTime{}
+	opt.InformationRefreshTime = time.Duration(binary.BigEndian.Uint32(data)) * time.Second
+	return &opt, nil
+}
@@ -0,0 +1,34 @@
+package dhcpv6
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptInformationRefreshTime(t *testing.T) {
+	opt, err := ParseOptInformationRefreshTime([]byte{0, 0, 0x0e, 0x10})
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, opt.InformationRefreshTime)
+	require.Equal(t, 4, opt.Length())
+	require.Equal(t, "InformationRefreshTime: 1h0m0s", opt.String())
+}
+
+func TestParseOptInformationRefreshTimeInvalidLength(t *testing.T) {
+	_, err := ParseOptInformationRefreshTime([]byte{0, 0, 0x0e})
+	require.Error(t, err)
+	_, err = ParseOptInformationRefreshTime([]byte{0, 0, 0x0e, 0x10, 0})
+	require.Error(t, err)
+}
+
+func TestOptInformationRefreshTimeToBytes(t *testing.T) {
+	expected := []byte{
+		0, 32, // INFORMATION_REFRESH_TIME
+		0, 4, // length
+		0xaa, 0xbb, 0xcc, 0xdd,
+	}
+	opt, err := ParseOptInformationRefreshTime(expected[4:])
+	require.NoError(t, err)
+	require.Equal(t, expected, opt.ToBytes())
+}
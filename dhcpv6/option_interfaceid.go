@@ -4,7 +4,7 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3315.txt
 
 import (
-	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 )
 
@@ -17,9 +17,9 @@ func (op *OptInterfaceId) Code() OptionCode {
 }
 
 func (op *OptInterfaceId) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_INTERFACE_ID))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(len(op.interfaceId)))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_INTERFACE_ID))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, op.interfaceId...)
 	return buf
 }
@@ -36,13 +36,38 @@ func (op *OptInterfaceId) Length() int {
 	return len(op.interfaceId)
 }
 
+// String renders the interface ID as text if it only contains printable
+// ASCII characters, and as hex otherwise
 func (op *OptInterfaceId) String() string {
-	return fmt.Sprintf("OptInterfaceId{interfaceid=%v}", op.interfaceId)
+	if isPrintableASCII(op.interfaceId) {
+		return fmt.Sprintf("OptInterfaceId{interfaceid=%s}", op.interfaceId)
+	}
+	return fmt.Sprintf("OptInterfaceId{interfaceid=0x%s}", hex.EncodeToString(op.interfaceId))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptInterfaceId) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"interface_id": op.interfaceId})
+}
+
+func isPrintableASCII(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	for _, b := range data {
+		if b < 0x20 || b > 0x7e {
+			return false
+		}
+	}
+	return true
 }
 
 // build an OptInterfaceId structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptInterfaceId(data []byte) (*OptInterfaceId, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Invalid interface ID data length. Expected at least 1 byte, got 0")
+	}
 	opt := OptInterfaceId{}
 	opt.interfaceId = append([]byte(nil), data...)
 	return &opt, nil
@@ -31,3 +31,30 @@ func TestOptInterfaceIdToBytes(t *testing.T) {
 		t.Fatalf("Invalid ToBytes result. Expected %v, got %v", expected, toBytes)
 	}
 }
+
+func TestParseOptInterfaceIdEmpty(t *testing.T) {
+	_, err := ParseOptInterfaceId([]byte{})
+	if err == nil {
+		t.Fatal("Expected error on empty interface ID, got nil")
+	}
+}
+
+func TestOptInterfaceIdString(t *testing.T) {
+	opt, err := ParseOptInterfaceId([]byte("GigabitEthernet0/0/0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "OptInterfaceId{interfaceid=GigabitEthernet0/0/0}"
+	if s := opt.String(); s != expected {
+		t.Fatalf("Invalid String. Expected %q, got %q", expected, s)
+	}
+
+	opt, err = ParseOptInterfaceId([]byte{0x00, 0x01, 0xfe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "OptInterfaceId{interfaceid=0x0001fe}"
+	if s := opt.String(); s != expected {
+		t.Fatalf("Invalid String. Expected %q, got %q", expected, s)
+	}
+}
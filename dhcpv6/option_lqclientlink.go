@@ -0,0 +1,60 @@
+package dhcpv6
+
+// This module defines the OptLQClientLink structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptLQClientLink implements the OPTION_LQ_CLIENT_LINK option, listing the
+// links on which the client of a leasequery has bindings
+type OptLQClientLink struct {
+	LinkAddresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptLQClientLink) Code() OptionCode {
+	return OPTION_LQ_CLIENT_LINK
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptLQClientLink) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_LQ_CLIENT_LINK))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.LinkAddresses)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptLQClientLink) Length() int {
+	return len(op.LinkAddresses) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptLQClientLink) Valid() error {
+	return checkIP6List(op.LinkAddresses)
+}
+
+func (op *OptLQClientLink) String() string {
+	return fmt.Sprintf("OptLQClientLink{linkaddresses=%v}", op.LinkAddresses)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptLQClientLink) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"link_addresses": op.LinkAddresses})
+}
+
+// ParseOptLQClientLink builds an OptLQClientLink structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptLQClientLink(data []byte) (*OptLQClientLink, error) {
+	links, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptLQClientLink{LinkAddresses: links}
+	return &opt, nil
+}
@@ -0,0 +1,112 @@
+package dhcpv6
+
+// This module defines the OptLQQuery structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// LQQueryType is the type of query carried by an OPTION_LQ_QUERY option
+type LQQueryType uint8
+
+// Leasequery query types, as defined by RFC 5007 and RFC 5460 (bulk
+// leasequery)
+const (
+	QUERY_BY_ADDRESS      LQQueryType = 1
+	QUERY_BY_CLIENTID     LQQueryType = 2
+	QUERY_BY_RELAY_ID     LQQueryType = 3
+	QUERY_BY_LINK_ADDRESS LQQueryType = 4
+	QUERY_BY_REMOTE_ID    LQQueryType = 5
+)
+
+// LQQueryTypeToString maps a leasequery query type to a mnemonic name
+var LQQueryTypeToString = map[LQQueryType]string{
+	QUERY_BY_ADDRESS:      "QUERY_BY_ADDRESS",
+	QUERY_BY_CLIENTID:     "QUERY_BY_CLIENTID",
+	QUERY_BY_RELAY_ID:     "QUERY_BY_RELAY_ID",
+	QUERY_BY_LINK_ADDRESS: "QUERY_BY_LINK_ADDRESS",
+	QUERY_BY_REMOTE_ID:    "QUERY_BY_REMOTE_ID",
+}
+
+// OptLQQuery implements the OPTION_LQ_QUERY option, carried by LEASEQUERY
+// messages. LinkAddress is the link the query applies to, or the unspecified
+// address, and Options holds the query options, e.g. the OPTION_IAADDR to
+// look up for QUERY_BY_ADDRESS.
+type OptLQQuery struct {
+	QueryType   LQQueryType
+	LinkAddress net.IP
+	Options     Options
+}
+
+// Code returns the option code
+func (op *OptLQQuery) Code() OptionCode {
+	return OPTION_LQ_QUERY
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptLQQuery) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_LQ_QUERY))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, uint8(op.QueryType))
+	buf = appendIP6(buf, op.LinkAddress)
+	for _, opt := range op.Options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptLQQuery) Length() int {
+	l := 17
+	for _, opt := range op.Options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+// Valid returns an error if the link address is not a 16-byte address, see
+// checkIP6
+func (op *OptLQQuery) Valid() error {
+	return checkIP6(op.LinkAddress)
+}
+
+func (op *OptLQQuery) String() string {
+	qtype := LQQueryTypeToString[op.QueryType]
+	if qtype == "" {
+		qtype = "Unknown"
+	}
+	return fmt.Sprintf("OptLQQuery{querytype=%v, linkaddress=%v, options=%v}",
+		qtype, op.LinkAddress, op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptLQQuery) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"query_type":   op.QueryType,
+		"link_address": op.LinkAddress,
+		"options":      jsonOptions(op.Options),
+	})
+}
+
+// ParseOptLQQuery builds an OptLQQuery structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptLQQuery(data []byte) (*OptLQQuery, error) {
+	if len(data) < 17 {
+		return nil, errOptionTooShort("Invalid LQ query data length. Expected at least 17 bytes, got %v", len(data))
+	}
+	opt := OptLQQuery{}
+	opt.QueryType = LQQueryType(data[0])
+	if _, ok := LQQueryTypeToString[opt.QueryType]; !ok {
+		return nil, fmt.Errorf("Invalid LQ query type %d", data[0])
+	}
+	opt.LinkAddress = net.IP(append([]byte(nil), data[1:17]...))
+	var err error
+	opt.Options, err = OptionsFromBytes(data[17:])
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
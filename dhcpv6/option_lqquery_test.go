@@ -0,0 +1,63 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptLQQuery(t *testing.T) {
+	data := []byte{
+		1,                                                          // QUERY_BY_ADDRESS
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // link address
+		0, 5, 0, 24, // OPTION_IAADDR
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x42,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	}
+	opt, err := ParseOptLQQuery(data)
+	require.NoError(t, err)
+	require.Equal(t, QUERY_BY_ADDRESS, opt.QueryType)
+	require.True(t, opt.LinkAddress.Equal(net.ParseIP("2001:db8::")))
+	require.Equal(t, 1, len(opt.Options))
+	addr, ok := opt.Options[0].(*OptIAAddress)
+	require.True(t, ok)
+	require.True(t, addr.IPv6Addr.Equal(net.ParseIP("2001:db8::42")))
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 44, 0, byte(len(data))}, data...), opt.ToBytes())
+}
+
+func TestParseOptLQQueryInvalid(t *testing.T) {
+	// too short
+	_, err := ParseOptLQQuery([]byte{1, 0, 0})
+	require.Error(t, err)
+	// unknown query type
+	_, err = ParseOptLQQuery(append([]byte{6}, make([]byte, 16)...))
+	require.Error(t, err)
+	_, err = ParseOptLQQuery(make([]byte, 17))
+	require.Error(t, err)
+	// truncated query option
+	_, err = ParseOptLQQuery(append(append([]byte{2}, make([]byte, 16)...), 0, 1, 0, 10))
+	require.Error(t, err)
+}
+
+func TestNewLeasequeryByAddress(t *testing.T) {
+	addr := net.ParseIP("2001:db8::42")
+	d, err := NewLeasequeryByAddress(addr, WithClientID(Duid{Type: DUID_LL, LinkLayerAddr: net.HardwareAddr{1, 2, 3, 4, 5, 6}}))
+	require.NoError(t, err)
+	require.Equal(t, LEASEQUERY, d.Type())
+	require.NotNil(t, d.GetOneOption(OPTION_CLIENTID))
+	query, ok := d.GetOneOption(OPTION_LQ_QUERY).(*OptLQQuery)
+	require.True(t, ok)
+	require.Equal(t, QUERY_BY_ADDRESS, query.QueryType)
+	require.True(t, query.LinkAddress.Equal(net.IPv6unspecified))
+	require.True(t, query.Options[0].(*OptIAAddress).IPv6Addr.Equal(addr))
+
+	// must survive a round trip
+	parsed, err := FromBytes(d.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, d.ToBytes(), parsed.ToBytes())
+
+	_, err = NewLeasequeryByAddress(net.ParseIP("192.0.2.1"))
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package dhcpv6
+
+// This module defines the OptLQRelayData structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptLQRelayData implements the OPTION_LQ_RELAY_DATA option. It carries the
+// address of the relay agent the server received the client's last message
+// from, and that RELAY-FORW message in full.
+type OptLQRelayData struct {
+	PeerAddress  net.IP
+	RelayMessage *DHCPv6Relay
+}
+
+// Code returns the option code
+func (op *OptLQRelayData) Code() OptionCode {
+	return OPTION_LQ_RELAY_DATA
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptLQRelayData) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_LQ_RELAY_DATA))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendIP6(buf, op.PeerAddress)
+	if op.RelayMessage != nil {
+		buf = append(buf, op.RelayMessage.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptLQRelayData) Length() int {
+	l := 16
+	if op.RelayMessage != nil {
+		l += op.RelayMessage.Length()
+	}
+	return l
+}
+
+// Valid returns an error if the peer address is not a 16-byte address, see
+// checkIP6
+func (op *OptLQRelayData) Valid() error {
+	return checkIP6(op.PeerAddress)
+}
+
+func (op *OptLQRelayData) String() string {
+	return fmt.Sprintf("OptLQRelayData{peeraddress=%v, relaymessage=%v}",
+		op.PeerAddress, op.RelayMessage)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptLQRelayData) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"peer_address":  op.PeerAddress,
+		"relay_message": op.RelayMessage,
+	})
+}
+
+// ParseOptLQRelayData builds an OptLQRelayData structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptLQRelayData(data []byte) (*OptLQRelayData, error) {
+	return parseOptLQRelayData(data, 0)
+}
+
+// parseOptLQRelayData parses an OPTION_LQ_RELAY_DATA found in a relay message
+// nested at the given depth, or outside of relay messages if depth is zero,
+// so that relay messages nested in it count towards MaxRelayDepth
+func parseOptLQRelayData(data []byte, depth int) (*OptLQRelayData, error) {
+	if len(data) < 16 {
+		return nil, errOptionTooShort("Invalid LQ relay data length. Expected at least 16 bytes, got %v", len(data))
+	}
+	opt := OptLQRelayData{}
+	opt.PeerAddress = net.IP(append([]byte(nil), data[:16]...))
+	var err error
+	opt.RelayMessage, err = relayMessageFromBytes(data[16:], depth+1, true)
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
@@ -0,0 +1,127 @@
+package dhcpv6
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptCLTTime(t *testing.T) {
+	opt, err := ParseOptCLTTime([]byte{0, 0, 0x0e, 0x10})
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, opt.CLTTime)
+	require.Equal(t, []byte{0, 46, 0, 4, 0, 0, 0x0e, 0x10}, opt.ToBytes())
+
+	_, err = ParseOptCLTTime([]byte{0, 0, 1})
+	require.Error(t, err)
+	_, err = ParseOptCLTTime([]byte{0, 0, 0, 0, 1})
+	require.Error(t, err)
+
+	// out of range durations are clamped
+	opt.CLTTime = time.Duration(1<<32) * time.Second
+	require.Equal(t, []byte{0xff, 0xff, 0xff, 0xff}, opt.ToBytes()[4:])
+	opt.CLTTime = -time.Second
+	require.Equal(t, []byte{0, 0, 0, 0}, opt.ToBytes()[4:])
+}
+
+func TestParseOptClientData(t *testing.T) {
+	data := []byte{
+		0, 1, 0, 10, // OPTION_CLIENTID
+		0, 3, 0, 1, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+		0, 46, 0, 4, // OPTION_CLT_TIME
+		0, 0, 0, 60,
+	}
+	opt, err := ParseOptClientData(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.Options))
+	cid, ok := opt.Options[0].(*OptClientId)
+	require.True(t, ok)
+	require.Equal(t, net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}, cid.Cid.LinkLayerAddr)
+	clt, ok := opt.Options[1].(*OptCLTTime)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, clt.CLTTime)
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 45, 0, byte(len(data))}, data...), opt.ToBytes())
+
+	// truncated nested option
+	_, err = ParseOptClientData([]byte{0, 46, 0, 4, 0})
+	require.Error(t, err)
+}
+
+func TestParseOptLQRelayData(t *testing.T) {
+	solicit, err := NewMessage()
+	require.NoError(t, err)
+	relay, err := EncapsulateRelay(solicit, RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	peer := net.ParseIP("2001:db8::2")
+	data := append([]byte(peer), relay.ToBytes()...)
+
+	opt, err := ParseOptLQRelayData(data)
+	require.NoError(t, err)
+	require.True(t, opt.PeerAddress.Equal(peer))
+	require.Equal(t, RELAY_FORW, opt.RelayMessage.Type())
+	require.True(t, opt.RelayMessage.LinkAddr().Equal(net.ParseIP("2001:db8::1")))
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 47, 0, byte(len(data))}, data...), opt.ToBytes())
+
+	// too short for the peer address
+	_, err = ParseOptLQRelayData(make([]byte, 15))
+	require.Error(t, err)
+	// not a relay message
+	_, err = ParseOptLQRelayData(append([]byte(peer), solicit.ToBytes()...))
+	require.Error(t, err)
+}
+
+// nestedLQRelayData returns a LEASEQUERY-REPLY whose OPTION_CLIENT_DATA
+// carries depth relay messages, nested in one another through their
+// OPTION_LQ_RELAY_DATA
+func nestedLQRelayData(depth int) []byte {
+	var data []byte
+	for i := 0; i < depth; i++ {
+		relay := make([]byte, RelayHeaderSize)
+		relay[0] = byte(RELAY_FORW)
+		if data != nil {
+			relay = append(relay, 0, byte(OPTION_LQ_RELAY_DATA), byte((16+len(data))>>8), byte(16+len(data)))
+			relay = append(relay, make([]byte, 16)...)
+			relay = append(relay, data...)
+		}
+		data = relay
+	}
+	lqData := append([]byte{0, byte(OPTION_LQ_RELAY_DATA), byte((16 + len(data)) >> 8), byte(16 + len(data))}, make([]byte, 16)...)
+	lqData = append(lqData, data...)
+	msg := []byte{byte(LEASEQUERY_REPLY), 0xaa, 0xbb, 0xcc, 0, byte(OPTION_CLIENT_DATA), byte(len(lqData) >> 8), byte(len(lqData))}
+	return append(msg, lqData...)
+}
+
+func TestParseOptLQRelayDataMaxDepth(t *testing.T) {
+	_, err := FromBytes(nestedLQRelayData(MaxRelayDepth))
+	require.NoError(t, err)
+
+	_, err = FromBytes(nestedLQRelayData(MaxRelayDepth + 1))
+	require.True(t, errors.Is(err, ErrRelayDepthExceeded), "unexpected error %v", err)
+	_, err = FromBytes(nestedLQRelayData(200))
+	require.True(t, errors.Is(err, ErrRelayDepthExceeded), "unexpected error %v", err)
+}
+
+func TestParseOptLQClientLink(t *testing.T) {
+	data := []byte{
+		0, 48, // OPTION_LQ_CLIENT_LINK
+		0, 32, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0x20, 0x01, 0x0d, 0xb8, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+	o, err := ParseOption(data)
+	require.NoError(t, err)
+	opt, ok := o.(*OptLQClientLink)
+	require.True(t, ok)
+	require.Equal(t, 2, len(opt.LinkAddresses))
+	require.True(t, opt.LinkAddresses[0].Equal(net.ParseIP("2001:db8:1::")))
+	require.True(t, opt.LinkAddresses[1].Equal(net.ParseIP("2001:db8:2::")))
+	require.Equal(t, data, opt.ToBytes())
+
+	_, err = ParseOptLQClientLink(make([]byte, 20))
+	require.Error(t, err)
+}
@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc5970.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 )
 
@@ -37,12 +36,10 @@ func (op *OptNetworkInterfaceId) Code() OptionCode {
 }
 
 func (op *OptNetworkInterfaceId) ToBytes() []byte {
-	buf := make([]byte, 7)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_NII))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	buf[4] = op.type_
-	buf[5] = op.major
-	buf[6] = op.minor
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NII))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.type_, op.major, op.minor)
 	return buf
 }
 
@@ -84,12 +81,17 @@ func (op *OptNetworkInterfaceId) String() string {
 	)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNetworkInterfaceId) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"type": op.type_, "major": op.major, "minor": op.minor})
+}
+
 // build an OptNetworkInterfaceId structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptNetworkInterfaceId(data []byte) (*OptNetworkInterfaceId, error) {
 	opt := OptNetworkInterfaceId{}
 	if len(data) != 3 {
-		return nil, fmt.Errorf("Invalid arch type data length. Expected 3 bytes, got %v", len(data))
+		return nil, fmt.Errorf("Invalid network interface identifier data length. Expected 3 bytes, got %v", len(data))
 	}
 	opt.type_ = data[0]
 	opt.major = data[1]
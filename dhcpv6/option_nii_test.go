@@ -0,0 +1,31 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptNetworkInterfaceId(t *testing.T) {
+	opt, err := ParseOptNetworkInterfaceId([]byte{NII_UNDI_EFI_GEN_II, 3, 10})
+	require.NoError(t, err)
+	require.Equal(t, uint8(NII_UNDI_EFI_GEN_II), opt.Type())
+	require.Equal(t, uint8(3), opt.Major())
+	require.Equal(t, uint8(10), opt.Minor())
+	require.Equal(t, "OptNetworkInterfaceId{type=UNDI 32/64 bit. UEFI runtime 2nd gen, revision=3.10}", opt.String())
+}
+
+func TestParseOptNetworkInterfaceIdInvalidLength(t *testing.T) {
+	_, err := ParseOptNetworkInterfaceId([]byte{NII_UNDI_EFI_GEN_II, 3})
+	require.Error(t, err)
+	_, err = ParseOptNetworkInterfaceId([]byte{NII_UNDI_EFI_GEN_II, 3, 10, 0})
+	require.Error(t, err)
+}
+
+func TestOptNetworkInterfaceIdToBytes(t *testing.T) {
+	opt := OptNetworkInterfaceId{}
+	opt.SetType(NII_PXE_GEN_I)
+	opt.SetMajor(2)
+	opt.SetMinor(1)
+	require.Equal(t, []byte{0, 62, 0, 3, NII_PXE_GEN_I, 2, 1}, opt.ToBytes())
+}
@@ -0,0 +1,82 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var nisDomainNameData = []byte{3, 'n', 'i', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+
+func TestParseOptNISServers(t *testing.T) {
+	data := append(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")...)
+	opt, err := ParseOptNISServers(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.NISServers))
+	require.True(t, opt.NISServers[1].Equal(net.ParseIP("2001:db8::2")))
+	require.Equal(t, append([]byte{0, 27, 0, 32}, data...), opt.ToBytes())
+
+	_, err = ParseOptNISServers(data[:20])
+	require.Error(t, err)
+}
+
+func TestParseOptNISPServers(t *testing.T) {
+	data := []byte(net.ParseIP("2001:db8::1"))
+	opt, err := ParseOptNISPServers(data)
+	require.NoError(t, err)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, opt.NISPServers)
+	require.Equal(t, append([]byte{0, 28, 0, 16}, data...), opt.ToBytes())
+
+	_, err = ParseOptNISPServers(data[:15])
+	require.Error(t, err)
+}
+
+func TestParseOptNISDomainName(t *testing.T) {
+	opt, err := ParseOptNISDomainName(nisDomainNameData)
+	require.NoError(t, err)
+	require.Equal(t, "nis.example.com", opt.DomainName)
+	require.Equal(t, append([]byte{0, 29, 0, byte(len(nisDomainNameData))}, nisDomainNameData...), opt.ToBytes())
+}
+
+func TestParseOptNISPDomainName(t *testing.T) {
+	opt, err := ParseOptNISPDomainName(nisDomainNameData)
+	require.NoError(t, err)
+	require.Equal(t, "nis.example.com", opt.DomainName)
+	require.Equal(t, append([]byte{0, 30, 0, byte(len(nisDomainNameData))}, nisDomainNameData...), opt.ToBytes())
+}
+
+func TestParseOptNISDomainNameInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		// truncated label
+		{3, 'n', 'i'},
+		// not terminated
+		{3, 'n', 'i', 's'},
+		// two domain names
+		{3, 'n', 'i', 's', 0, 3, 'f', 'o', 'o', 0},
+		// trailing data
+		{3, 'n', 'i', 's', 0, 3, 'f', 'o', 'o'},
+	} {
+		_, err := ParseOptNISDomainName(data)
+		require.Error(t, err, "data %v", data)
+		_, err = ParseOptNISPDomainName(data)
+		require.Error(t, err, "data %v", data)
+	}
+}
+
+func TestParseOptionNIS(t *testing.T) {
+	for code, typ := range map[OptionCode]Option{
+		OPTION_NIS_SERVERS:      &OptNISServers{},
+		OPTION_NISP_SERVERS:     &OptNISPServers{},
+		OPTION_NIS_DOMAIN_NAME:  &OptNISDomainName{},
+		OPTION_NISP_DOMAIN_NAME: &OptNISPDomainName{},
+	} {
+		data := nisDomainNameData
+		if code == OPTION_NIS_SERVERS || code == OPTION_NISP_SERVERS {
+			data = net.IPv6loopback
+		}
+		opt, err := ParseOption(append([]byte{0, byte(code), 0, byte(len(data))}, data...))
+		require.NoError(t, err)
+		require.IsType(t, typ, opt)
+	}
+}
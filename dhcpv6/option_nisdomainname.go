@@ -0,0 +1,111 @@
+package dhcpv6
+
+// This module defines the OptNISDomainName and OptNISPDomainName structures.
+// https://www.ietf.org/rfc/rfc3898.txt
+
+import (
+	"fmt"
+)
+
+// OptNISDomainName implements the OPTION_NIS_DOMAIN_NAME option
+type OptNISDomainName struct {
+	DomainName string
+}
+
+// Code returns the option code
+func (op *OptNISDomainName) Code() OptionCode {
+	return OPTION_NIS_DOMAIN_NAME
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISDomainName) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NIS_DOMAIN_NAME))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, LabelToBytes(op.DomainName)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNISDomainName) Length() int {
+	return len(LabelToBytes(op.DomainName))
+}
+
+// Valid returns an error if the domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptNISDomainName) Valid() error {
+	_, err := labelToBytes(op.DomainName)
+	return err
+}
+
+func (op *OptNISDomainName) String() string {
+	return fmt.Sprintf("OptNISDomainName{domainname=%v}", op.DomainName)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNISDomainName) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"domain_name": op.DomainName})
+}
+
+// ParseOptNISDomainName builds an OptNISDomainName structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptNISDomainName(data []byte) (*OptNISDomainName, error) {
+	domainName, err := domainNameFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptNISDomainName{DomainName: domainName}
+	return &opt, nil
+}
+
+// OptNISPDomainName implements the OPTION_NISP_DOMAIN_NAME option
+type OptNISPDomainName struct {
+	DomainName string
+}
+
+// Code returns the option code
+func (op *OptNISPDomainName) Code() OptionCode {
+	return OPTION_NISP_DOMAIN_NAME
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISPDomainName) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NISP_DOMAIN_NAME))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, LabelToBytes(op.DomainName)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNISPDomainName) Length() int {
+	return len(LabelToBytes(op.DomainName))
+}
+
+// Valid returns an error if the domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptNISPDomainName) Valid() error {
+	_, err := labelToBytes(op.DomainName)
+	return err
+}
+
+func (op *OptNISPDomainName) String() string {
+	return fmt.Sprintf("OptNISPDomainName{domainname=%v}", op.DomainName)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNISPDomainName) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"domain_name": op.DomainName})
+}
+
+// ParseOptNISPDomainName builds an OptNISPDomainName structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptNISPDomainName(data []byte) (*OptNISPDomainName, error) {
+	domainName, err := domainNameFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptNISPDomainName{DomainName: domainName}
+	return &opt, nil
+}
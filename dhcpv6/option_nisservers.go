@@ -0,0 +1,109 @@
+package dhcpv6
+
+// This module defines the OptNISServers and OptNISPServers structures.
+// https://www.ietf.org/rfc/rfc3898.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptNISServers implements the OPTION_NIS_SERVERS option
+type OptNISServers struct {
+	NISServers []net.IP
+}
+
+// Code returns the option code
+func (op *OptNISServers) Code() OptionCode {
+	return OPTION_NIS_SERVERS
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISServers) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NIS_SERVERS))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.NISServers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNISServers) Length() int {
+	return len(op.NISServers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptNISServers) Valid() error {
+	return checkIP6List(op.NISServers)
+}
+
+func (op *OptNISServers) String() string {
+	return fmt.Sprintf("OptNISServers{nisservers=%v}", op.NISServers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNISServers) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"servers": op.NISServers})
+}
+
+// ParseOptNISServers builds an OptNISServers structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptNISServers(data []byte) (*OptNISServers, error) {
+	servers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptNISServers{NISServers: servers}
+	return &opt, nil
+}
+
+// OptNISPServers implements the OPTION_NISP_SERVERS option
+type OptNISPServers struct {
+	NISPServers []net.IP
+}
+
+// Code returns the option code
+func (op *OptNISPServers) Code() OptionCode {
+	return OPTION_NISP_SERVERS
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISPServers) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NISP_SERVERS))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.NISPServers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNISPServers) Length() int {
+	return len(op.NISPServers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptNISPServers) Valid() error {
+	return checkIP6List(op.NISPServers)
+}
+
+func (op *OptNISPServers) String() string {
+	return fmt.Sprintf("OptNISPServers{nispservers=%v}", op.NISPServers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNISPServers) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"servers": op.NISPServers})
+}
+
+// ParseOptNISPServers builds an OptNISPServers structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptNISPServers(data []byte) (*OptNISPServers, error) {
+	servers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptNISPServers{NISPServers: servers}
+	return &opt, nil
+}
@@ -6,13 +6,21 @@ package dhcpv6
 import (
 	"encoding/binary"
 	"fmt"
+
+	"github.com/insomniacslk/dhcp/iana"
 )
 
 type OptIANA struct {
-	IaId    [4]byte
+	IaId    IAID
 	T1      uint32
 	T2      uint32
-	Options []Option
+	Options Options
+}
+
+// NewOptIANA returns an IA_NA with the given IAID and nested options. T1 and
+// T2 are zero, leaving their choice to the server.
+func NewOptIANA(iaid IAID, opts ...Option) *OptIANA {
+	return &OptIANA{IaId: iaid, Options: opts}
 }
 
 func (op *OptIANA) Code() OptionCode {
@@ -20,18 +28,34 @@ func (op *OptIANA) Code() OptionCode {
 }
 
 func (op *OptIANA) ToBytes() []byte {
-	buf := make([]byte, 16)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_IA_NA))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	copy(buf[4:8], op.IaId[:])
-	binary.BigEndian.PutUint32(buf[8:12], op.T1)
-	binary.BigEndian.PutUint32(buf[12:16], op.T2)
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_IA_NA))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.IaId[:]...)
+	buf = appendUint32(buf, op.T1)
+	buf = appendUint32(buf, op.T2)
 	for _, opt := range op.Options {
 		buf = append(buf, opt.ToBytes()...)
 	}
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptIANA) Clone() Option {
+	c := *op
+	c.Options = op.Options.Clone()
+	return &c
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptIANA) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_IA_NA, op.Length())
+	s.WriteBytes(op.IaId[:])
+	s.Write32(op.T1)
+	s.Write32(op.T2)
+	s.WriteOptions(op.Options)
+}
+
 func (op *OptIANA) Length() int {
 	l := 12
 	for _, opt := range op.Options {
@@ -40,20 +64,69 @@ func (op *OptIANA) Length() int {
 	return l
 }
 
+// Status returns the status code option carried in the IA_NA, or nil if there
+// is none
+func (op *OptIANA) Status() *OptStatusCode {
+	return op.Options.Status()
+}
+
+// SetStatus adds a status code option to the IA_NA, replacing the existing
+// one if any, e.g. to report StatusNoAddrsAvail
+func (op *OptIANA) SetStatus(code iana.StatusCode, msg string) {
+	op.Options.Update(&OptStatusCode{StatusCode: code, StatusMessage: []byte(msg)})
+}
+
+// GetOneAddress returns the first IA address carried in the IA_NA, or nil if
+// there is none
+func (op *OptIANA) GetOneAddress() *OptIAAddress {
+	for _, opt := range op.Options.Get(OPTION_IAADDR) {
+		if addr, ok := opt.(*OptIAAddress); ok {
+			return addr
+		}
+	}
+	return nil
+}
+
+// Valid checks that T1 is not greater than T2 when both are set, as required
+// by RFC 8415, section 21.4. It is not enforced while parsing because some
+// servers get it wrong, but callers can use it to reject such options.
+func (op *OptIANA) Valid() error {
+	if op.T1 != 0 && op.T2 != 0 && op.T1 > op.T2 {
+		return fmt.Errorf("Invalid IA_NA timers: T1 (%v) is greater than T2 (%v)", op.T1, op.T2)
+	}
+	return nil
+}
+
 func (op *OptIANA) String() string {
 	return fmt.Sprintf("OptIANA{IAID=%v, t1=%v, t2=%v, options=%v}",
 		op.IaId, op.T1, op.T2, op.Options)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptIANA) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"iaid":    op.IaId.String(),
+		"t1":      op.T1,
+		"t2":      op.T2,
+		"options": jsonOptions(op.Options),
+	})
+}
+
 // build an OptIANA structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptIANA(data []byte) (*OptIANA, error) {
 	var err error
 	opt := OptIANA{}
+	if len(data) < 4 {
+		return nil, errOptionTooShort("Invalid IA for Non-temporary Addresses data length. Expected at least 4 bytes for the IAID, got %v", len(data))
+	}
+	opt.IaId, err = IAIDFromBytes(data[:4])
+	if err != nil {
+		return nil, err
+	}
 	if len(data) < 12 {
-		return nil, fmt.Errorf("Invalid IA for Non-temporary Addresses data length. Expected at least 12 bytes, got %v", len(data))
+		return nil, errOptionTooShort("Invalid IA for Non-temporary Addresses data length. Expected at least 12 bytes, got %v", len(data))
 	}
-	copy(opt.IaId[:], data[:4])
 	opt.T1 = binary.BigEndian.Uint32(data[4:8])
 	opt.T2 = binary.BigEndian.Uint32(data[8:12])
 	opt.Options, err = OptionsFromBytes(data[12:])
@@ -1,8 +1,11 @@
 package dhcpv6
 
 import (
+	"net"
 	"testing"
+	"time"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +19,7 @@ func TestOptIANAParseOptIANA(t *testing.T) {
 	opt, err := ParseOptIANA(data)
 	require.NoError(t, err)
 	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, IAID{1, 0, 0, 0}, opt.IaId)
 }
 
 func TestOptIANAParseOptIANAInvalidLength(t *testing.T) {
@@ -28,6 +32,14 @@ func TestOptIANAParseOptIANAInvalidLength(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestOptIANAParseOptIANATruncatedIAID(t *testing.T) {
+	data := []byte{
+		1, 0, 0, // truncated IAID
+	}
+	_, err := ParseOptIANA(data)
+	require.Error(t, err)
+}
+
 func TestOptIANAParseOptIANAInvalidOptions(t *testing.T) {
 	data := []byte{
 		1, 0, 0, 0, // IAID
@@ -45,9 +57,7 @@ func TestOptIANAToBytes(t *testing.T) {
 		T1:   12345,
 		T2:   54321,
 		Options: []Option{
-			&OptElapsedTime{
-				ElapsedTime: 0xaabb,
-			},
+			&OptElapsedTime{elapsedTime: 0xaabb},
 		},
 	}
 	expected := []byte{
@@ -60,3 +70,99 @@ func TestOptIANAToBytes(t *testing.T) {
 	}
 	require.Equal(t, expected, opt.ToBytes())
 }
+
+func TestOptIANAGetOneAddress(t *testing.T) {
+	data := []byte{
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 1, // T1
+		0, 0, 0, 2, // T2
+		0, 5, 0, 0x18, 0x24, 1, 0xdb, 0, 0x30, 0x10, 0xc0, 0x8f, 0xfa, 0xce, 0, 0, 0, 0x44, 0, 0, 0, 0, 0xb2, 0x7a, 0, 0, 0xc0, 0x8a, // options
+	}
+	opt, err := ParseOptIANA(data)
+	require.NoError(t, err)
+	addr := opt.GetOneAddress()
+	require.NotNil(t, addr)
+	require.Equal(t, net.ParseIP("2401:db00:3010:c08f:face:0:44:0"), addr.IPv6Addr)
+	require.Equal(t, data, opt.ToBytes()[4:])
+
+	opt = &OptIANA{}
+	require.Nil(t, opt.GetOneAddress())
+}
+
+func TestOptIANAValid(t *testing.T) {
+	opt := OptIANA{T1: 100, T2: 50}
+	require.Error(t, opt.Valid())
+
+	opt = OptIANA{T1: 0, T2: 100}
+	require.NoError(t, opt.Valid())
+
+	opt = OptIANA{T1: 50, T2: 100}
+	require.NoError(t, opt.Valid())
+}
+
+func TestOptIANAParseDoesNotValidateTimers(t *testing.T) {
+	data := []byte{
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 100, // T1
+		0, 0, 0, 50, // T2
+	}
+	opt, err := ParseOptIANA(data)
+	require.NoError(t, err)
+	require.Error(t, opt.Valid())
+}
+
+func TestNewOptIANASolicitRoundTrip(t *testing.T) {
+	addr, err := NewOptIAAddress(net.ParseIP("2001:db8::1"), time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	iaNA := NewOptIANA(IAID{1, 2, 3, 4}, addr)
+	require.Equal(t, uint32(0), iaNA.T1)
+	require.Equal(t, uint32(0), iaNA.T2)
+
+	solicit, err := NewSolicit(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	solicit.UpdateOption(iaNA)
+	parsed, err := FromBytes(solicit.ToBytes())
+	require.NoError(t, err)
+	got, ok := parsed.GetOneOption(OPTION_IA_NA).(*OptIANA)
+	require.True(t, ok)
+	require.Equal(t, IAID{1, 2, 3, 4}, got.IaId)
+	gotAddr := got.GetOneAddress()
+	require.NotNil(t, gotAddr)
+	require.True(t, gotAddr.IPv6Addr.Equal(net.ParseIP("2001:db8::1")))
+	require.Equal(t, time.Hour, gotAddr.PreferredLifetime())
+	require.Equal(t, 2*time.Hour, gotAddr.ValidLifetime())
+}
+
+func TestOptIANASetStatus(t *testing.T) {
+	addr, err := NewOptIAAddress(net.ParseIP("2001:db8::1"), time.Hour, time.Hour)
+	require.NoError(t, err)
+	iaNA := NewOptIANA(IAID{1, 2, 3, 4}, addr)
+	require.Nil(t, iaNA.Status())
+
+	iaNA.SetStatus(iana.StatusSuccess, "ok")
+	iaNA.SetStatus(iana.StatusNoAddrsAvail, "no addresses")
+	require.Equal(t, 1, len(iaNA.Options.Get(OPTION_STATUS_CODE)))
+	require.Equal(t, 2, len(iaNA.Options))
+	sc := iaNA.Status()
+	require.NotNil(t, sc)
+	require.Equal(t, iana.StatusNoAddrsAvail, sc.StatusCode)
+	require.Equal(t, []byte("no addresses"), sc.StatusMessage)
+
+	parsed, err := ParseOptIANA(iaNA.ToBytes()[4:])
+	require.NoError(t, err)
+	require.Equal(t, iana.StatusNoAddrsAvail, parsed.Status().StatusCode)
+}
+
+func TestOptIANAEmpty(t *testing.T) {
+	// IAID, T1 and T2, without any nested option, as commonly sent in a Solicit
+	data := []byte{1, 2, 3, 4, 0, 0, 0, 1, 0, 0, 0, 2}
+	opt, err := ParseOptIANA(data)
+	require.NoError(t, err)
+	require.Equal(t, IAID{1, 2, 3, 4}, opt.IaId)
+	require.Equal(t, uint32(1), opt.T1)
+	require.Equal(t, uint32(2), opt.T2)
+	require.Equal(t, 0, len(opt.Options))
+	require.Nil(t, opt.GetOneAddress())
+	require.Equal(t, 12, opt.Length())
+	require.Equal(t, append([]byte{0, 3, 0, 12}, data...), opt.ToBytes())
+}
@@ -0,0 +1,200 @@
+package dhcpv6
+
+// This module defines the OptNTPServer structure.
+// https://www.ietf.org/rfc/rfc5908.txt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// NTP server suboption types, as defined by RFC 5908
+const (
+	NTP_SUBOPTION_SRV_ADDR uint16 = 1
+	NTP_SUBOPTION_MC_ADDR  uint16 = 2
+	NTP_SUBOPTION_SRV_FQDN uint16 = 3
+)
+
+// NTPSuboptionTypeToString maps an NTP suboption type to a mnemonic name
+var NTPSuboptionTypeToString = map[uint16]string{
+	NTP_SUBOPTION_SRV_ADDR: "NTP_SUBOPTION_SRV_ADDR",
+	NTP_SUBOPTION_MC_ADDR:  "NTP_SUBOPTION_MC_ADDR",
+	NTP_SUBOPTION_SRV_FQDN: "NTP_SUBOPTION_SRV_FQDN",
+}
+
+// NTPSuboption represents a single suboption of an OPTION_NTP_SERVER. Addr is
+// used for the server and multicast address suboptions, FQDN for the server
+// FQDN suboption, and Data holds the payload of unknown suboption types.
+type NTPSuboption struct {
+	SuboptionType uint16
+	Addr          net.IP
+	FQDN          string
+	Data          []byte
+}
+
+// payload returns the suboption data, without type and length bytes
+func (so *NTPSuboption) payload() []byte {
+	switch so.SuboptionType {
+	case NTP_SUBOPTION_SRV_ADDR, NTP_SUBOPTION_MC_ADDR:
+		return appendIP6(nil, so.Addr)
+	case NTP_SUBOPTION_SRV_FQDN:
+		return LabelToBytes(so.FQDN)
+	default:
+		return so.Data
+	}
+}
+
+// ToBytes serializes the suboption and returns it as a sequence of bytes
+func (so *NTPSuboption) ToBytes() []byte {
+	payload := so.payload()
+	buf := make([]byte, 0, 4+len(payload))
+	buf = appendUint16(buf, so.SuboptionType)
+	buf = appendUint16(buf, uint16(len(payload)))
+	return append(buf, payload...)
+}
+
+// Length returns the suboption length, including type and length bytes
+func (so *NTPSuboption) Length() int {
+	return 4 + len(so.payload())
+}
+
+func (so *NTPSuboption) String() string {
+	name, ok := NTPSuboptionTypeToString[so.SuboptionType]
+	if !ok {
+		name = "Unknown"
+	}
+	switch so.SuboptionType {
+	case NTP_SUBOPTION_SRV_ADDR, NTP_SUBOPTION_MC_ADDR:
+		return fmt.Sprintf("%v -> %v", name, so.Addr)
+	case NTP_SUBOPTION_SRV_FQDN:
+		return fmt.Sprintf("%v -> %v", name, so.FQDN)
+	default:
+		return fmt.Sprintf("%v -> %v", name, so.Data)
+	}
+}
+
+// MarshalJSON returns the JSON representation of the suboption
+func (so *NTPSuboption) MarshalJSON() ([]byte, error) {
+	data := map[string]interface{}{"type": so.SuboptionType}
+	switch so.SuboptionType {
+	case NTP_SUBOPTION_SRV_ADDR, NTP_SUBOPTION_MC_ADDR:
+		data["addr"] = so.Addr
+	case NTP_SUBOPTION_SRV_FQDN:
+		data["fqdn"] = so.FQDN
+	default:
+		data["data"] = so.Data
+	}
+	return json.Marshal(data)
+}
+
+// ParseNTPSuboption builds an NTPSuboption structure from a sequence of bytes.
+// The input data includes suboption type and length bytes, and may contain
+// trailing bytes which are ignored.
+func ParseNTPSuboption(data []byte) (*NTPSuboption, error) {
+	if len(data) < 4 {
+		return nil, errOptionTooShort("Invalid NTP suboption: less than 4 bytes")
+	}
+	so := NTPSuboption{}
+	so.SuboptionType = binary.BigEndian.Uint16(data[0:2])
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if len(data) < length+4 {
+		return nil, errOptionTooShort("Invalid NTP suboption length for suboption %v. Declared %v, actual %v",
+			so.SuboptionType, length, len(data)-4,
+		)
+	}
+	payload := data[4 : 4+length]
+	switch so.SuboptionType {
+	case NTP_SUBOPTION_SRV_ADDR, NTP_SUBOPTION_MC_ADDR:
+		if length != net.IPv6len {
+			return nil, fmt.Errorf("Invalid NTP suboption address length. Expected %v bytes, got %v", net.IPv6len, length)
+		}
+		so.Addr = net.IP(append([]byte(nil), payload...))
+	case NTP_SUBOPTION_SRV_FQDN:
+		names, err := LabelsFromBytes(payload)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) != 1 {
+			return nil, fmt.Errorf("Invalid NTP suboption FQDN: expected one domain name, got %v", len(names))
+		}
+		so.FQDN = names[0]
+	default:
+		so.Data = append([]byte(nil), payload...)
+	}
+	return &so, nil
+}
+
+// OptNTPServer implements the OPTION_NTP_SERVER option
+type OptNTPServer struct {
+	Suboptions []NTPSuboption
+}
+
+// Code returns the option code
+func (op *OptNTPServer) Code() OptionCode {
+	return OPTION_NTP_SERVER
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNTPServer) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NTP_SERVER))
+	buf = appendUint16(buf, uint16(op.Length()))
+	for _, so := range op.Suboptions {
+		buf = append(buf, so.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNTPServer) Length() int {
+	l := 0
+	for _, so := range op.Suboptions {
+		l += so.Length()
+	}
+	return l
+}
+
+// Valid returns an error if the address of a server or multicast address
+// suboption is not a 16-byte address, see checkIP6, or if the domain name of
+// a server FQDN suboption has an empty label or a label longer than 63 bytes
+func (op *OptNTPServer) Valid() error {
+	for _, so := range op.Suboptions {
+		switch so.SuboptionType {
+		case NTP_SUBOPTION_SRV_ADDR, NTP_SUBOPTION_MC_ADDR:
+			if err := checkIP6(so.Addr); err != nil {
+				return err
+			}
+		case NTP_SUBOPTION_SRV_FQDN:
+			if _, err := labelToBytes(so.FQDN); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (op *OptNTPServer) String() string {
+	return fmt.Sprintf("OptNTPServer{suboptions=%v}", op.Suboptions)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptNTPServer) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"suboptions": op.Suboptions})
+}
+
+// ParseOptNTPServer builds an OptNTPServer structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNTPServer(data []byte) (*OptNTPServer, error) {
+	opt := OptNTPServer{}
+	for len(data) > 0 {
+		so, err := ParseNTPSuboption(data)
+		if err != nil {
+			return nil, err
+		}
+		opt.Suboptions = append(opt.Suboptions, *so)
+		data = data[4+int(binary.BigEndian.Uint16(data[2:4])):]
+	}
+	return &opt, nil
+}
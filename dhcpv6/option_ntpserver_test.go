@@ -0,0 +1,52 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var ntpServerData = []byte{
+	0, 1, 0, 16, // NTP_SUBOPTION_SRV_ADDR
+	0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	0, 2, 0, 16, // NTP_SUBOPTION_MC_ADDR
+	0xff, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x01,
+	0, 3, 0, 17, // NTP_SUBOPTION_SRV_FQDN
+	3, 'n', 't', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	0, 42, 0, 2, 0xaa, 0xbb, // unknown suboption
+}
+
+func TestParseOptNTPServer(t *testing.T) {
+	opt, err := ParseOptNTPServer(ntpServerData)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(opt.Suboptions))
+	require.Equal(t, NTP_SUBOPTION_SRV_ADDR, opt.Suboptions[0].SuboptionType)
+	require.True(t, net.ParseIP("2001:db8::1").Equal(opt.Suboptions[0].Addr))
+	require.Equal(t, NTP_SUBOPTION_MC_ADDR, opt.Suboptions[1].SuboptionType)
+	require.True(t, net.ParseIP("ff02::101").Equal(opt.Suboptions[1].Addr))
+	require.Equal(t, NTP_SUBOPTION_SRV_FQDN, opt.Suboptions[2].SuboptionType)
+	require.Equal(t, "ntp.example.com", opt.Suboptions[2].FQDN)
+	require.Equal(t, uint16(42), opt.Suboptions[3].SuboptionType)
+	require.Equal(t, []byte{0xaa, 0xbb}, opt.Suboptions[3].Data)
+	require.Equal(t, len(ntpServerData), opt.Length())
+}
+
+func TestParseOptNTPServerInvalid(t *testing.T) {
+	// short suboption header
+	_, err := ParseOptNTPServer([]byte{0, 1, 0})
+	require.Error(t, err)
+	// declared length runs past the buffer
+	_, err = ParseOptNTPServer([]byte{0, 42, 0, 4, 0xaa})
+	require.Error(t, err)
+	// address of the wrong length
+	_, err = ParseOptNTPServer([]byte{0, 1, 0, 4, 127, 0, 0, 1})
+	require.Error(t, err)
+}
+
+func TestOptNTPServerRoundTrip(t *testing.T) {
+	opt, err := ParseOptNTPServer(ntpServerData)
+	require.NoError(t, err)
+	expected := append([]byte{0, 56, 0, byte(len(ntpServerData))}, ntpServerData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
@@ -0,0 +1,60 @@
+package dhcpv6
+
+// This module defines the OptPanaAgent structure.
+// https://www.ietf.org/rfc/rfc5192.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptPanaAgent represents an OPTION_PANA_AGENT option, listing the addresses
+// of the PANA Authentication Agents
+type OptPanaAgent struct {
+	Agents []net.IP
+}
+
+// Code returns the option code
+func (op *OptPanaAgent) Code() OptionCode {
+	return PANA_AUTHENTICATION_AGENT
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptPanaAgent) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(PANA_AUTHENTICATION_AGENT))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.Agents)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptPanaAgent) Length() int {
+	return len(op.Agents) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptPanaAgent) Valid() error {
+	return checkIP6List(op.Agents)
+}
+
+func (op *OptPanaAgent) String() string {
+	return fmt.Sprintf("OptPanaAgent{agents=%v}", op.Agents)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptPanaAgent) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"agents": op.Agents})
+}
+
+// ParseOptPanaAgent builds an OptPanaAgent structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptPanaAgent(data []byte) (*OptPanaAgent, error) {
+	agents, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptPanaAgent{Agents: agents}
+	return &opt, nil
+}
@@ -0,0 +1,30 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptPanaAgent(t *testing.T) {
+	data := []byte{
+		0, 40, // PANA_AUTHENTICATION_AGENT
+		0, 32, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02,
+	}
+	o, err := ParseOption(data)
+	require.NoError(t, err)
+	opt, ok := o.(*OptPanaAgent)
+	require.True(t, ok)
+	require.Equal(t, 2, len(opt.Agents))
+	require.True(t, opt.Agents[0].Equal(net.ParseIP("2001:db8::1")))
+	require.True(t, opt.Agents[1].Equal(net.ParseIP("2001:db8::2")))
+	require.Equal(t, data, opt.ToBytes())
+}
+
+func TestParseOptPanaAgentInvalidLength(t *testing.T) {
+	_, err := ParseOptPanaAgent(make([]byte, 17))
+	require.Error(t, err)
+}
@@ -6,13 +6,21 @@ package dhcpv6
 import (
 	"encoding/binary"
 	"fmt"
+
+	"github.com/insomniacslk/dhcp/iana"
 )
 
 type OptIAForPrefixDelegation struct {
-	iaId    [4]byte
+	iaId    IAID
 	t1      uint32
 	t2      uint32
-	options []byte
+	options Options
+}
+
+// NewOptIAPD returns an IA_PD with the given IAID and nested options. T1 and
+// T2 are zero, leaving their choice to the server.
+func NewOptIAPD(iaid IAID, opts ...Option) *OptIAForPrefixDelegation {
+	return &OptIAForPrefixDelegation{iaId: iaid, options: opts}
 }
 
 func (op *OptIAForPrefixDelegation) Code() OptionCode {
@@ -20,21 +28,23 @@ func (op *OptIAForPrefixDelegation) Code() OptionCode {
 }
 
 func (op *OptIAForPrefixDelegation) ToBytes() []byte {
-	buf := make([]byte, 16)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_IA_PD))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	copy(buf[4:8], op.iaId[:])
-	binary.BigEndian.PutUint32(buf[8:12], op.t1)
-	binary.BigEndian.PutUint32(buf[12:16], op.t2)
-	buf = append(buf, op.options...)
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_IA_PD))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.iaId[:]...)
+	buf = appendUint32(buf, op.t1)
+	buf = appendUint32(buf, op.t2)
+	for _, opt := range op.options {
+		buf = append(buf, opt.ToBytes()...)
+	}
 	return buf
 }
 
-func (op *OptIAForPrefixDelegation) IAID() []byte {
-	return op.iaId[:]
+func (op *OptIAForPrefixDelegation) IAID() IAID {
+	return op.iaId
 }
 
-func (op *OptIAForPrefixDelegation) SetIAID(iaId [4]byte) {
+func (op *OptIAForPrefixDelegation) SetIAID(iaId IAID) {
 	op.iaId = iaId
 }
 
@@ -54,16 +64,52 @@ func (op *OptIAForPrefixDelegation) SetT2(t2 uint32) {
 	op.t2 = t2
 }
 
-func (op *OptIAForPrefixDelegation) Options() []byte {
+func (op *OptIAForPrefixDelegation) Options() Options {
 	return op.options
 }
 
-func (op *OptIAForPrefixDelegation) SetOptions(options []byte) {
+func (op *OptIAForPrefixDelegation) SetOptions(options Options) {
 	op.options = options
 }
 
+// Status returns the status code option carried in the IA_PD, or nil if there
+// is none
+func (op *OptIAForPrefixDelegation) Status() *OptStatusCode {
+	return op.options.Status()
+}
+
+// SetStatus adds a status code option to the IA_PD, replacing the existing
+// one if any, e.g. to report StatusNoPrefixAvail
+func (op *OptIAForPrefixDelegation) SetStatus(code iana.StatusCode, msg string) {
+	op.options.Update(&OptStatusCode{StatusCode: code, StatusMessage: []byte(msg)})
+}
+
+// GetPrefixes returns the IA prefixes carried in the IA_PD
+func (op *OptIAForPrefixDelegation) GetPrefixes() []*OptIAPrefix {
+	var prefixes []*OptIAPrefix
+	for _, opt := range op.options.Get(OPTION_IAPREFIX) {
+		if prefix, ok := opt.(*OptIAPrefix); ok {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
 func (op *OptIAForPrefixDelegation) Length() int {
-	return 12 + len(op.options)
+	l := 12
+	for _, opt := range op.options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+// Valid checks that T1 is not greater than T2 when both are set, as required
+// by RFC 8415, section 21.21. See OptIANA.Valid.
+func (op *OptIAForPrefixDelegation) Valid() error {
+	if op.t1 != 0 && op.t2 != 0 && op.t1 > op.t2 {
+		return fmt.Errorf("Invalid IA_PD timers: T1 (%v) is greater than T2 (%v)", op.t1, op.t2)
+	}
+	return nil
 }
 
 func (op *OptIAForPrefixDelegation) String() string {
@@ -71,16 +117,36 @@ func (op *OptIAForPrefixDelegation) String() string {
 		op.iaId, op.t1, op.t2, op.options)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptIAForPrefixDelegation) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"iaid":    op.iaId.String(),
+		"t1":      op.t1,
+		"t2":      op.t2,
+		"options": jsonOptions(op.options),
+	})
+}
+
 // build an OptIAForPrefixDelegation structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptIAForPrefixDelegation(data []byte) (*OptIAForPrefixDelegation, error) {
+	var err error
 	opt := OptIAForPrefixDelegation{}
+	if len(data) < 4 {
+		return nil, errOptionTooShort("Invalid IA for Prefix Delegation data length. Expected at least 4 bytes for the IAID, got %v", len(data))
+	}
+	opt.iaId, err = IAIDFromBytes(data[:4])
+	if err != nil {
+		return nil, err
+	}
 	if len(data) < 12 {
-		return nil, fmt.Errorf("Invalid IA for Prefix Delegation data length. Expected at least 12 bytes, got %v", len(data))
+		return nil, errOptionTooShort("Invalid IA for Prefix Delegation data length. Expected at least 12 bytes, got %v", len(data))
 	}
-	copy(opt.iaId[:], data[:4])
 	opt.t1 = binary.BigEndian.Uint32(data[4:8])
 	opt.t2 = binary.BigEndian.Uint32(data[8:12])
-	opt.options = append(opt.options, data[12:]...)
+	opt.options, err = OptionsFromBytes(data[12:])
+	if err != nil {
+		return nil, err
+	}
 	return &opt, nil
 }
@@ -0,0 +1,124 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptIAForPrefixDelegation(t *testing.T) {
+	data := []byte{
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 1, // T1
+		0, 0, 0, 2, // T2
+		0, 26, 0, 25, // OPTION_IAPREFIX
+		0, 0, 0x0e, 0x10, // preferred lifetime
+		0, 0, 0x1c, 0x20, // valid lifetime
+		56,                                                         // prefix length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // prefix
+		0, 13, 0, 2, 0, 0, // OPTION_STATUS_CODE, success
+	}
+	opt, err := ParseOptIAForPrefixDelegation(data)
+	require.NoError(t, err)
+	require.Equal(t, IAID{1, 0, 0, 0}, opt.IAID())
+	require.Equal(t, uint32(1), opt.T1())
+	require.Equal(t, uint32(2), opt.T2())
+	require.Equal(t, 2, len(opt.Options()))
+	require.Equal(t, len(data), opt.Length())
+
+	prefixes := opt.GetPrefixes()
+	require.Equal(t, 1, len(prefixes))
+	require.Equal(t, byte(56), prefixes[0].PrefixLength())
+	require.Equal(t, uint32(3600), prefixes[0].PreferredLifetime())
+}
+
+func TestParseOptIAForPrefixDelegationInvalidOptions(t *testing.T) {
+	data := []byte{
+		1, 0, 0, 0, // IAID
+		0, 0, 0, 1, // T1
+		0, 0, 0, 2, // T2
+		0, 26, 0, 25, 0, 0, // truncated OPTION_IAPREFIX
+	}
+	_, err := ParseOptIAForPrefixDelegation(data)
+	require.Error(t, err)
+}
+
+func TestOptIAForPrefixDelegationToBytes(t *testing.T) {
+	prefix := OptIAPrefix{}
+	prefix.SetPrefixLength(48)
+	prefix.SetIPv6Prefix([16]byte{0x20, 0x01, 0x0d, 0xb8})
+	opt := OptIAForPrefixDelegation{}
+	opt.SetIAID(IAID{1, 2, 3, 4})
+	opt.SetT1(3600)
+	opt.SetT2(5400)
+	opt.SetOptions(Options{&prefix})
+
+	expected := []byte{
+		0, 25, // OPTION_IA_PD
+		0, 41, // length
+		1, 2, 3, 4, // IAID
+		0, 0, 0x0e, 0x10, // T1
+		0, 0, 0x15, 0x18, // T2
+	}
+	expected = append(expected, prefix.ToBytes()...)
+	require.Equal(t, expected, opt.ToBytes())
+	require.Equal(t, 41, opt.Length())
+}
+
+func TestOptIAForPrefixDelegationValid(t *testing.T) {
+	opt := OptIAForPrefixDelegation{}
+	opt.SetT1(100)
+	opt.SetT2(50)
+	require.Error(t, opt.Valid())
+
+	opt.SetT1(0)
+	opt.SetT2(100)
+	require.NoError(t, opt.Valid())
+}
+
+func TestNewOptIAPD(t *testing.T) {
+	_, prefixNet, err := net.ParseCIDR("2001:db8:1::/48")
+	require.NoError(t, err)
+	prefix, err := NewOptIAPrefix(prefixNet, time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	iaPD := NewOptIAPD(IAID{1, 2, 3, 4}, prefix)
+	require.Equal(t, uint32(0), iaPD.T1())
+	require.Equal(t, uint32(0), iaPD.T2())
+
+	parsed, err := ParseOptIAForPrefixDelegation(iaPD.ToBytes()[4:])
+	require.NoError(t, err)
+	require.Equal(t, IAID{1, 2, 3, 4}, parsed.IAID())
+	prefixes := parsed.GetPrefixes()
+	require.Equal(t, 1, len(prefixes))
+	require.Equal(t, prefixNet.String(), prefixes[0].Prefix().String())
+	require.Equal(t, uint32(3600), prefixes[0].PreferredLifetime())
+	require.Equal(t, uint32(7200), prefixes[0].ValidLifetime())
+}
+
+func TestOptIAPDSetStatus(t *testing.T) {
+	iaPD := NewOptIAPD(IAID{1, 2, 3, 4})
+	require.Nil(t, iaPD.Status())
+
+	iaPD.SetStatus(iana.StatusSuccess, "")
+	iaPD.SetStatus(iana.StatusNoPrefixAvail, "no prefixes")
+	require.Equal(t, 1, len(iaPD.Options()))
+	require.Equal(t, iana.StatusNoPrefixAvail, iaPD.Status().StatusCode)
+	require.Equal(t, []byte("no prefixes"), iaPD.Status().StatusMessage)
+}
+
+func TestOptIAForPrefixDelegationEmpty(t *testing.T) {
+	// IAID, T1 and T2, without any nested option, as commonly sent in a Solicit
+	data := []byte{1, 2, 3, 4, 0, 0, 0, 1, 0, 0, 0, 2}
+	opt, err := ParseOptIAForPrefixDelegation(data)
+	require.NoError(t, err)
+	require.Equal(t, IAID{1, 2, 3, 4}, opt.IAID())
+	require.Equal(t, uint32(1), opt.T1())
+	require.Equal(t, uint32(2), opt.T2())
+	require.Equal(t, 0, len(opt.Options()))
+	require.Equal(t, 0, len(opt.GetPrefixes()))
+	require.Equal(t, 12, opt.Length())
+	require.Equal(t, append([]byte{0, 25, 0, 12}, data...), opt.ToBytes())
+}
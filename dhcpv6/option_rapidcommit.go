@@ -0,0 +1,48 @@
+package dhcpv6
+
+// This module defines the OptRapidCommit structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"fmt"
+)
+
+// OptRapidCommit represents a DHCPv6 Rapid Commit option
+type OptRapidCommit struct{}
+
+// Code returns the option code
+func (op *OptRapidCommit) Code() OptionCode {
+	return OPTION_RAPID_COMMIT
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptRapidCommit) ToBytes() []byte {
+	buf := make([]byte, 0, 4)
+	buf = appendUint16(buf, uint16(OPTION_RAPID_COMMIT))
+	buf = appendUint16(buf, 0)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptRapidCommit) Length() int {
+	return 0
+}
+
+func (op *OptRapidCommit) String() string {
+	return "OptRapidCommit{}"
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptRapidCommit) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, struct{}{})
+}
+
+// ParseOptRapidCommit builds an OptRapidCommit structure from a sequence of
+// bytes. The input data does not include option code and length bytes, and
+// must be empty.
+func ParseOptRapidCommit(data []byte) (*OptRapidCommit, error) {
+	if len(data) != 0 {
+		return nil, fmt.Errorf("Invalid rapid commit data length. Expected 0 bytes, got %v", len(data))
+	}
+	return &OptRapidCommit{}, nil
+}
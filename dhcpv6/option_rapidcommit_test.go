@@ -0,0 +1,32 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptRapidCommit(t *testing.T) {
+	opt, err := ParseOptRapidCommit([]byte{})
+	require.NoError(t, err)
+	require.Equal(t, OPTION_RAPID_COMMIT, opt.Code())
+	require.Equal(t, 0, opt.Length())
+
+	_, err = ParseOptRapidCommit([]byte{0})
+	require.Error(t, err)
+}
+
+func TestOptRapidCommitToBytes(t *testing.T) {
+	opt := OptRapidCommit{}
+	require.Equal(t, []byte{0, 14, 0, 0}, opt.ToBytes())
+}
+
+func TestOptionsHasRapidCommit(t *testing.T) {
+	opts, err := OptionsFromBytes([]byte{0, 8, 0, 2, 0, 0, 0, 14, 0, 0})
+	require.NoError(t, err)
+	require.True(t, opts.HasRapidCommit())
+	require.IsType(t, &OptRapidCommit{}, opts.GetOne(OPTION_RAPID_COMMIT))
+
+	opts = Options{&OptElapsedTime{}}
+	require.False(t, opts.HasRapidCommit())
+}
@@ -0,0 +1,48 @@
+package dhcpv6
+
+// This module defines the OptReconfigureAccept structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"fmt"
+)
+
+// OptReconfigureAccept represents a DHCPv6 Reconfigure Accept option
+type OptReconfigureAccept struct{}
+
+// Code returns the option code
+func (op *OptReconfigureAccept) Code() OptionCode {
+	return OPTION_RECONF_ACCEPT
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptReconfigureAccept) ToBytes() []byte {
+	buf := make([]byte, 0, 4)
+	buf = appendUint16(buf, uint16(OPTION_RECONF_ACCEPT))
+	buf = appendUint16(buf, 0)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptReconfigureAccept) Length() int {
+	return 0
+}
+
+func (op *OptReconfigureAccept) String() string {
+	return "OptReconfigureAccept{}"
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptReconfigureAccept) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, struct{}{})
+}
+
+// ParseOptReconfigureAccept builds an OptReconfigureAccept structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes, and must be empty.
+func ParseOptReconfigureAccept(data []byte) (*OptReconfigureAccept, error) {
+	if len(data) != 0 {
+		return nil, fmt.Errorf("Invalid reconfigure accept data length. Expected 0 bytes, got %v", len(data))
+	}
+	return &OptReconfigureAccept{}, nil
+}
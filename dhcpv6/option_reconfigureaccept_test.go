@@ -0,0 +1,22 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptReconfigureAccept(t *testing.T) {
+	opt, err := ParseOptReconfigureAccept([]byte{})
+	require.NoError(t, err)
+	require.Equal(t, OPTION_RECONF_ACCEPT, opt.Code())
+	require.Equal(t, 0, opt.Length())
+
+	_, err = ParseOptReconfigureAccept([]byte{0})
+	require.Error(t, err)
+}
+
+func TestOptReconfigureAcceptToBytes(t *testing.T) {
+	opt := OptReconfigureAccept{}
+	require.Equal(t, []byte{0, 20, 0, 0}, opt.ToBytes())
+}
@@ -0,0 +1,57 @@
+package dhcpv6
+
+// This module defines the OptReconfigureMessage structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"fmt"
+)
+
+// OptReconfigureMessage represents a DHCPv6 Reconfigure Message option
+type OptReconfigureMessage struct {
+	MessageType MessageType
+}
+
+// Code returns the option code
+func (op *OptReconfigureMessage) Code() OptionCode {
+	return OPTION_RECONF_MSG
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptReconfigureMessage) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_RECONF_MSG))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, byte(op.MessageType))
+	return buf
+}
+
+// Length returns the option length
+func (op *OptReconfigureMessage) Length() int {
+	return 1
+}
+
+func (op *OptReconfigureMessage) String() string {
+	return fmt.Sprintf("OptReconfigureMessage{messageType=%v}", MessageTypeToString(op.MessageType))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptReconfigureMessage) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"message_type": MessageTypeToString(op.MessageType)})
+}
+
+// ParseOptReconfigureMessage builds an OptReconfigureMessage structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes. Only RENEW and INFORMATION_REQUEST message types are allowed.
+func ParseOptReconfigureMessage(data []byte) (*OptReconfigureMessage, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("Invalid reconfigure message data length. Expected 1 byte, got %v", len(data))
+	}
+	opt := OptReconfigureMessage{}
+	opt.MessageType = MessageType(data[0])
+	if opt.MessageType != RENEW && opt.MessageType != INFORMATION_REQUEST {
+		return nil, fmt.Errorf("Invalid reconfigure message type %v: must be RENEW or INFORMATION-REQUEST",
+			MessageTypeToString(opt.MessageType))
+	}
+	return &opt, nil
+}
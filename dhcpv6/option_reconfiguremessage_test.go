@@ -0,0 +1,37 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptReconfigureMessage(t *testing.T) {
+	opt, err := ParseOptReconfigureMessage([]byte{byte(RENEW)})
+	require.NoError(t, err)
+	require.Equal(t, RENEW, opt.MessageType)
+	require.Equal(t, 1, opt.Length())
+
+	opt, err = ParseOptReconfigureMessage([]byte{byte(INFORMATION_REQUEST)})
+	require.NoError(t, err)
+	require.Equal(t, INFORMATION_REQUEST, opt.MessageType)
+}
+
+func TestParseOptReconfigureMessageInvalidType(t *testing.T) {
+	_, err := ParseOptReconfigureMessage([]byte{byte(SOLICIT)})
+	require.Error(t, err)
+	_, err = ParseOptReconfigureMessage([]byte{byte(REBIND)})
+	require.Error(t, err)
+}
+
+func TestParseOptReconfigureMessageInvalidLength(t *testing.T) {
+	_, err := ParseOptReconfigureMessage([]byte{})
+	require.Error(t, err)
+	_, err = ParseOptReconfigureMessage([]byte{byte(RENEW), 0})
+	require.Error(t, err)
+}
+
+func TestOptReconfigureMessageToBytes(t *testing.T) {
+	opt := OptReconfigureMessage{MessageType: RENEW}
+	require.Equal(t, []byte{0, 19, 0, 1, 5}, opt.ToBytes())
+}
@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3315.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 )
 
@@ -17,13 +16,23 @@ func (op *OptRelayMsg) Code() OptionCode {
 }
 
 func (op *OptRelayMsg) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_RELAY_MSG))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_RELAY_MSG))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, op.relayMessage.ToBytes()...)
 	return buf
 }
 
+// SerializeTo writes the option to a Serializer
+func (op *OptRelayMsg) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_RELAY_MSG, op.Length())
+	if ms, ok := op.relayMessage.(OptionSerializer); ok {
+		ms.SerializeTo(s)
+		return
+	}
+	s.WriteBytes(op.relayMessage.ToBytes())
+}
+
 func (op *OptRelayMsg) RelayMessage() DHCPv6 {
 	return op.relayMessage
 }
@@ -40,12 +49,28 @@ func (op *OptRelayMsg) String() string {
 	return fmt.Sprintf("OptRelayMsg{relaymsg=%v}", op.relayMessage)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptRelayMsg) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"message": op.relayMessage})
+}
+
 // build an OptRelayMsg structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptRelayMsg(data []byte) (*OptRelayMsg, error) {
+	return parseOptRelayMsg(data, 0)
+}
+
+// parseOptRelayMsg parses a relay message option that belongs to a relay
+// message nested at the given depth, or zero if it is not part of one. See
+// MaxRelayDepth.
+func parseOptRelayMsg(data []byte, depth int) (*OptRelayMsg, error) {
 	var err error
 	opt := OptRelayMsg{}
-	opt.relayMessage, err = FromBytes(data)
+	if len(data) > 0 && (MessageType(data[0]) == RELAY_FORW || MessageType(data[0]) == RELAY_REPL) {
+		opt.relayMessage, err = relayMessageFromBytes(data, depth+1, true)
+	} else {
+		opt.relayMessage, err = FromBytes(data)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestRelayMsgParseOptRelayMsg(t *testing.T) {
@@ -112,8 +113,8 @@ func TestRelayMsgParseOptRelayMsgSingleEncapsulation(t *testing.T) {
 			reflect.TypeOf(innerOpt),
 		)
 	}
-	if eTime := eto.ElapsedTime; eTime != 0x1122 {
-		t.Fatalf("Invalid elapsed time. Expected 0x1122, got 0x%04x", eTime)
+	if eTime := eto.ElapsedTime(); eTime != 0x1122*10*time.Millisecond {
+		t.Fatalf("Invalid elapsed time. Expected 0x1122, got 0x%04x", eTime/(10*time.Millisecond))
 	}
 }
 
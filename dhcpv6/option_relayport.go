@@ -0,0 +1,56 @@
+package dhcpv6
+
+// This module defines the OptRelayPort structure.
+// https://www.ietf.org/rfc/rfc8357.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptRelayPort implements the OPTION_RELAY_PORT option. It is added by relay
+// agents listening on a non-standard UDP port, and carries the source port
+// of the downstream relay agent, or zero if the message was received from a
+// client.
+type OptRelayPort struct {
+	DownstreamSourcePort uint16
+}
+
+// Code returns the option code
+func (op *OptRelayPort) Code() OptionCode {
+	return OPTION_RELAY_PORT
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptRelayPort) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_RELAY_PORT))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint16(buf, op.DownstreamSourcePort)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptRelayPort) Length() int {
+	return 2
+}
+
+func (op *OptRelayPort) String() string {
+	return fmt.Sprintf("OptRelayPort{downstreamsourceport=%v}", op.DownstreamSourcePort)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptRelayPort) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"downstream_source_port": op.DownstreamSourcePort})
+}
+
+// ParseOptRelayPort builds an OptRelayPort structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptRelayPort(data []byte) (*OptRelayPort, error) {
+	if len(data) != 2 {
+		return nil, fmt.Errorf("Invalid relay port data length. Expected 2 bytes, got %v", len(data))
+	}
+	opt := OptRelayPort{}
+	opt.DownstreamSourcePort = binary.BigEndian.Uint16(data)
+	return &opt, nil
+}
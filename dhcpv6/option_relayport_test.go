@@ -0,0 +1,32 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptRelayPort(t *testing.T) {
+	opt, err := ParseOptRelayPort([]byte{0x12, 0x32})
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x1232), opt.DownstreamSourcePort)
+	require.Equal(t, "OptRelayPort{downstreamsourceport=4658}", opt.String())
+}
+
+func TestParseOptRelayPortInvalidLength(t *testing.T) {
+	_, err := ParseOptRelayPort([]byte{0x12})
+	require.Error(t, err)
+	_, err = ParseOptRelayPort([]byte{0x12, 0x32, 0})
+	require.Error(t, err)
+}
+
+func TestOptRelayPortToBytes(t *testing.T) {
+	opt := OptRelayPort{DownstreamSourcePort: 547}
+	require.Equal(t, []byte{0, 135, 0, 2, 0x02, 0x23}, opt.ToBytes())
+}
+
+func TestParseOptionRelayPort(t *testing.T) {
+	opt, err := ParseOption([]byte{0, 135, 0, 2, 0x02, 0x23})
+	require.NoError(t, err)
+	require.Equal(t, &OptRelayPort{DownstreamSourcePort: 547}, opt)
+}
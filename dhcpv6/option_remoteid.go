@@ -5,6 +5,7 @@ package dhcpv6
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 )
 
@@ -18,10 +19,10 @@ func (op *OptRemoteId) Code() OptionCode {
 }
 
 func (op *OptRemoteId) ToBytes() []byte {
-	buf := make([]byte, 8)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_REMOTE_ID))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	binary.BigEndian.PutUint32(buf[4:8], uint32(op.enterpriseNumber))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_REMOTE_ID))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, uint32(op.enterpriseNumber))
 	buf = append(buf, op.remoteId...)
 	return buf
 }
@@ -47,17 +48,22 @@ func (op *OptRemoteId) Length() int {
 }
 
 func (op *OptRemoteId) String() string {
-	return fmt.Sprintf("OptRemoteId{enterprisenum=%v, remoteid=%v}",
-		op.enterpriseNumber, op.remoteId,
+	return fmt.Sprintf("OptRemoteId{enterprisenum=%v, remoteid=%s}",
+		op.enterpriseNumber, hex.EncodeToString(op.remoteId),
 	)
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptRemoteId) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"enterprise_number": op.enterpriseNumber, "remote_id": op.remoteId})
+}
+
 // build an OptRemoteId structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptRemoteId(data []byte) (*OptRemoteId, error) {
 	opt := OptRemoteId{}
 	if len(data) < 4 {
-		return nil, fmt.Errorf("Invalid remote id data length. Expected at least 4 bytes, got %v", len(data))
+		return nil, errOptionTooShort("Invalid remote id data length. Expected at least 4 bytes, got %v", len(data))
 	}
 	opt.enterpriseNumber = binary.BigEndian.Uint32(data[:4])
 	opt.remoteId = append([]byte(nil), data[4:]...)
@@ -36,3 +36,20 @@ func TestOptRemoteIdToBytes(t *testing.T) {
 		t.Fatalf("Invalid ToBytes result. Expected %v, got %v", expected, toBytes)
 	}
 }
+
+func TestOptRemoteIdShortData(t *testing.T) {
+	_, err := ParseOptRemoteId([]byte{0xaa, 0xbb, 0xcc})
+	if err == nil {
+		t.Fatal("Expected error for remote ID shorter than 4 bytes, got nil")
+	}
+}
+
+func TestOptRemoteIdString(t *testing.T) {
+	opt := OptRemoteId{}
+	opt.SetEnterpriseNumber(3561)
+	opt.SetRemoteID([]byte{0xde, 0xad, 0xbe, 0xef})
+	expected := "OptRemoteId{enterprisenum=3561, remoteid=deadbeef}"
+	if s := opt.String(); s != expected {
+		t.Fatalf("Invalid String result. Expected %v, got %v", expected, s)
+	}
+}
@@ -6,6 +6,7 @@ package dhcpv6
 import (
 	"encoding/binary"
 	"fmt"
+	"sort"
 )
 
 type OptRequestedOption struct {
@@ -17,17 +18,28 @@ func (op *OptRequestedOption) Code() OptionCode {
 }
 
 func (op *OptRequestedOption) ToBytes() []byte {
-	buf := make([]byte, 4)
-	roBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_ORO))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_ORO))
+	buf = appendUint16(buf, uint16(op.Length()))
 	for _, ro := range op.requestedOptions {
-		binary.BigEndian.PutUint16(roBytes, uint16(ro))
-		buf = append(buf, roBytes...)
+		buf = appendUint16(buf, uint16(ro))
 	}
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptRequestedOption) Clone() Option {
+	return &OptRequestedOption{requestedOptions: append([]OptionCode(nil), op.requestedOptions...)}
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptRequestedOption) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_ORO, op.Length())
+	for _, ro := range op.requestedOptions {
+		s.Write16(uint16(ro))
+	}
+}
+
 func (op *OptRequestedOption) RequestedOptions() []OptionCode {
 	return op.requestedOptions
 }
@@ -40,31 +52,66 @@ func (op *OptRequestedOption) AddRequestedOption(opt OptionCode) {
 	op.requestedOptions = append(op.requestedOptions, opt)
 }
 
+// Add adds an option code to the requested options, unless it is already
+// present
+func (op *OptRequestedOption) Add(code OptionCode) {
+	if !op.Has(code) {
+		op.requestedOptions = append(op.requestedOptions, code)
+	}
+}
+
+// Has returns true if the given option code is among the requested options
+func (op *OptRequestedOption) Has(code OptionCode) bool {
+	for _, ro := range op.requestedOptions {
+		if ro == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Sorted returns a copy of the requested option codes in ascending order
+func (op *OptRequestedOption) Sorted() []OptionCode {
+	codes := make([]OptionCode, len(op.requestedOptions))
+	copy(codes, op.requestedOptions)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
 func (op *OptRequestedOption) Length() int {
 	return len(op.requestedOptions) * 2
 }
 
 func (op *OptRequestedOption) String() string {
-	roString := "["
-	for idx, code := range op.requestedOptions {
-		if name, ok := OptionCodeToString[OptionCode(code)]; ok {
-			roString += name
+	return fmt.Sprintf("OptRequestedOption{options=%v}", optionCodesToString(op.requestedOptions))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptRequestedOption) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": op.requestedOptions})
+}
+
+// optionCodesToString returns the names of a list of option codes
+func optionCodesToString(codes []OptionCode) string {
+	ret := "["
+	for idx, code := range codes {
+		if name, ok := optionCodeName(code); ok {
+			ret += name
 		} else {
-			roString += "Unknown"
+			ret += "Unknown"
 		}
-		if idx < len(op.requestedOptions)-1 {
-			roString += ", "
+		if idx < len(codes)-1 {
+			ret += ", "
 		}
 	}
-	roString += "]"
-	return fmt.Sprintf("OptRequestedOption{options=%v}", roString)
+	return ret + "]"
 }
 
 // build an OptRequestedOption structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptRequestedOption(data []byte) (*OptRequestedOption, error) {
 	if len(data)%2 != 0 {
-		return nil, fmt.Errorf("Invalid OptRequestedOption data: length is not a multiple of 2")
+		return nil, fmt.Errorf("Invalid OptRequestedOption data length. Expected a multiple of 2 bytes, got %v", len(data))
 	}
 	opt := OptRequestedOption{}
 	var rOpts []OptionCode
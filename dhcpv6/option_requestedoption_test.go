@@ -0,0 +1,48 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptRequestedOption(t *testing.T) {
+	opt, err := ParseOptRequestedOption([]byte{0, 23, 0, 24})
+	require.NoError(t, err)
+	require.Equal(t, []OptionCode{DNS_RECURSIVE_NAME_SERVER, DOMAIN_SEARCH_LIST}, opt.RequestedOptions())
+	require.Equal(t, 4, opt.Length())
+}
+
+func TestParseOptRequestedOptionInvalidLength(t *testing.T) {
+	_, err := ParseOptRequestedOption([]byte{0, 23, 0})
+	require.Error(t, err)
+}
+
+func TestOptRequestedOptionAddHas(t *testing.T) {
+	opt := OptRequestedOption{}
+	opt.Add(DOMAIN_SEARCH_LIST)
+	opt.Add(DNS_RECURSIVE_NAME_SERVER)
+	opt.Add(DOMAIN_SEARCH_LIST)
+	require.Equal(t, []OptionCode{DOMAIN_SEARCH_LIST, DNS_RECURSIVE_NAME_SERVER}, opt.RequestedOptions())
+	require.True(t, opt.Has(DNS_RECURSIVE_NAME_SERVER))
+	require.False(t, opt.Has(OPT_BOOTFILE_URL))
+}
+
+func TestOptRequestedOptionSorted(t *testing.T) {
+	opt := OptRequestedOption{}
+	opt.SetRequestedOptions([]OptionCode{OPT_BOOTFILE_URL, DOMAIN_SEARCH_LIST, DNS_RECURSIVE_NAME_SERVER})
+	require.Equal(t, []OptionCode{DNS_RECURSIVE_NAME_SERVER, DOMAIN_SEARCH_LIST, OPT_BOOTFILE_URL}, opt.Sorted())
+	// Sorted must not reorder the option itself
+	require.Equal(t, OPT_BOOTFILE_URL, opt.RequestedOptions()[0])
+}
+
+func TestOptRequestedOptionToBytes(t *testing.T) {
+	opt := OptRequestedOption{}
+	opt.SetRequestedOptions([]OptionCode{DNS_RECURSIVE_NAME_SERVER, OPT_BOOTFILE_URL})
+	expected := []byte{
+		0, 6, // OPTION_ORO
+		0, 4, // length
+		0, 23, 0, 59,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
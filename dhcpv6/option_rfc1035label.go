@@ -17,6 +17,12 @@ func LabelsFromBytes(buf []byte) ([]string, error) {
 		}
 		length := int(buf[pos])
 		pos++
+		// DHCPv6 forbids DNS name compression (RFC 8415, section 10), so any
+		// length byte with the top two bits set is rejected instead of being
+		// followed as a pointer
+		if length&0xc0 != 0 {
+			return nil, fmt.Errorf("DomainNamesFromBytes: compressed or extended label type 0x%02x not allowed", length&0xc0)
+		}
 		if length == 0 {
 			domains = append(domains, label)
 			label = ""
@@ -24,31 +30,96 @@ func LabelsFromBytes(buf []byte) ([]string, error) {
 		if len(buf)-pos < length {
 			return nil, fmt.Errorf("DomainNamesFromBytes: invalid short label length")
 		}
+		part := string(buf[pos : pos+length])
+		// a dot within a label cannot be told apart from a label separator
+		if strings.Contains(part, ".") {
+			return nil, fmt.Errorf("DomainNamesFromBytes: label %q contains a dot", part)
+		}
 		if label != "" {
 			label += "."
 		}
-		label += string(buf[pos : pos+length])
+		label += part
 		pos += length
 	}
 	return domains, nil
 }
 
+// maxLabelLength is the maximum length of a label, whose length byte must
+// have its top two bits clear
+const maxLabelLength = 63
+
+// LabelToBytes encodes a domain name as a sequence of labels terminated by
+// the root label. The empty name and "." are encoded as the root label alone.
+// Empty labels are skipped, and labels longer than 63 bytes are truncated, so
+// that the encoding is always well-formed: the options check their domain
+// names with labelToBytes in their Valid method.
 func LabelToBytes(label string) []byte {
-	var encodedLabel []byte
-	if len(label) == 0 {
-		return []byte{0}
+	encodedLabel, _ := labelToBytes(label)
+	return encodedLabel
+}
+
+// LabelsToBytes encodes a list of domain names, see LabelToBytes
+func LabelsToBytes(labels []string) []byte {
+	encodedLabels, _ := labelsToBytes(labels)
+	return encodedLabels
+}
+
+// labelToBytes returns the encoding of LabelToBytes, and an error if the
+// domain name has an empty label, e.g. "a..b", or a label longer than 63
+// bytes
+func labelToBytes(label string) ([]byte, error) {
+	var (
+		encodedLabel []byte
+		err          error
+	)
+	if len(label) == 0 || label == "." {
+		return []byte{0}, nil
 	}
-	for _, part := range strings.Split(label, ".") {
+	for _, part := range strings.Split(strings.TrimSuffix(label, "."), ".") {
+		if len(part) == 0 {
+			if err == nil {
+				err = fmt.Errorf("Invalid domain name %q: empty label", label)
+			}
+			continue
+		}
+		if len(part) > maxLabelLength {
+			if err == nil {
+				err = fmt.Errorf("Invalid domain name %q: label longer than %v bytes", label, maxLabelLength)
+			}
+			part = part[:maxLabelLength]
+		}
 		encodedLabel = append(encodedLabel, byte(len(part)))
 		encodedLabel = append(encodedLabel, []byte(part)...)
 	}
-	return append(encodedLabel, 0)
+	return append(encodedLabel, 0), err
 }
 
-func LabelsToBytes(labels []string) []byte {
-	var encodedLabels []byte
+// labelsToBytes returns the encoding of LabelsToBytes, and the first error
+// returned by labelToBytes
+func labelsToBytes(labels []string) ([]byte, error) {
+	var (
+		encodedLabels []byte
+		err           error
+	)
 	for _, label := range labels {
-		encodedLabels = append(encodedLabels, LabelToBytes(label)...)
+		encodedLabel, labelErr := labelToBytes(label)
+		if err == nil {
+			err = labelErr
+		}
+		encodedLabels = append(encodedLabels, encodedLabel...)
 	}
-	return encodedLabels
+	return encodedLabels, err
+}
+
+// domainNameFromBytes parses a single domain name, which must span the whole
+// input
+func domainNameFromBytes(data []byte) (string, error) {
+	labels, err := LabelsFromBytes(data)
+	if err != nil {
+		return "", err
+	}
+	if len(labels) != 1 || len(LabelToBytes(labels[0])) != len(data) {
+		return "", fmt.Errorf("Invalid domain name: expected a single domain name of %v bytes", len(data))
+	}
+	return labels[0], nil
 }
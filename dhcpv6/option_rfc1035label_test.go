@@ -2,6 +2,7 @@ package dhcpv6
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -64,3 +65,87 @@ func TestLabelToBytesZeroLength(t *testing.T) {
 		t.Fatalf("Invalid label. Expected: %v, got: %v", expected, encodedLabel)
 	}
 }
+
+func TestLabelsFromBytesCompressionPointer(t *testing.T) {
+	labels, err := LabelsFromBytes([]byte{
+		0x7, 'e', 'x', 'a', 'm', 'p', 'l', 'e',
+		0x3, 'c', 'o', 'm',
+		0x0,
+		0x3, 'w', 'w', 'w',
+		0xc0, 0x00, // pointer back to "example.com"
+	})
+	if err == nil {
+		t.Fatal("Expected error on compressed label, got nil")
+	}
+	if labels != nil {
+		t.Fatalf("Invalid label. Expected nil, got %v", labels)
+	}
+}
+
+func TestLabelToBytesTrailingDot(t *testing.T) {
+	encodedLabel := LabelToBytes("slackware.it.")
+	expected := []byte{
+		0x9, 's', 'l', 'a', 'c', 'k', 'w', 'a', 'r', 'e',
+		0x2, 'i', 't',
+		0x0,
+	}
+	if !bytes.Equal(encodedLabel, expected) {
+		t.Fatalf("Invalid label. Expected: %v, got: %v", expected, encodedLabel)
+	}
+}
+
+func TestLabelsFromBytesDotInLabel(t *testing.T) {
+	// "000.000" as a single label would be re-encoded as two labels
+	labels, err := LabelsFromBytes([]byte{7, '0', '0', '0', '.', '0', '0', '0', 0})
+	if err == nil {
+		t.Fatal("Expected error on label containing a dot, got nil")
+	}
+	if labels != nil {
+		t.Fatalf("Invalid label. Expected nil, got %v", labels)
+	}
+}
+
+func TestLabelToBytesInvalidLabels(t *testing.T) {
+	long := strings.Repeat("a", 64)
+	for _, name := range []string{"a..b", ".a", "a..", long, long + ".com", "www." + long} {
+		if _, err := labelToBytes(name); err == nil {
+			t.Fatalf("Expected error on domain name %q, got nil", name)
+		}
+		// the lenient encoding stays well-formed
+		labels, err := LabelsFromBytes(LabelToBytes(name))
+		if err != nil {
+			t.Fatalf("Invalid encoding of domain name %q: %v", name, err)
+		}
+		if len(labels) != 1 {
+			t.Fatalf("Invalid labels length for domain name %q. Expected: 1, got: %v", name, len(labels))
+		}
+	}
+	for _, name := range []string{"", ".", "a.b", "a.b.", strings.Repeat("a", 63) + ".com"} {
+		if _, err := labelToBytes(name); err != nil {
+			t.Fatalf("Unexpected error on domain name %q: %v", name, err)
+		}
+	}
+	if _, err := labelsToBytes([]string{"slackware.it", "a..b"}); err == nil {
+		t.Fatal("Expected error on domain name list, got nil")
+	}
+}
+
+func TestOptionsValidDomainNames(t *testing.T) {
+	long := strings.Repeat("a", 64) + ".com"
+	for _, opt := range []OptionValidator{
+		&OptNISDomainName{DomainName: "a..b"},
+		&OptNISPDomainName{DomainName: long},
+		&OptDomainSearchList{DomainSearchList: []string{"example.com", long}},
+		&OptBCMCSControllerDomainNameList{DomainNames: []string{"a..b"}},
+		&OptSIPServersDomainNameList{DomainNames: []string{long}},
+		&OptClientFQDN{DomainName: "host..example.com"},
+		&OptNTPServer{Suboptions: []NTPSuboption{{SuboptionType: NTP_SUBOPTION_SRV_FQDN, FQDN: long}}},
+	} {
+		if err := opt.Valid(); err == nil {
+			t.Fatalf("Expected error on option %v, got nil", opt)
+		}
+	}
+	if err := (&OptDomainSearchList{DomainSearchList: []string{"example.com", ""}}).Valid(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
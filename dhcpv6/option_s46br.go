@@ -0,0 +1,60 @@
+package dhcpv6
+
+// This module defines the OptS46BR structure.
+// https://www.ietf.org/rfc/rfc7598.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptS46BR implements the OPTION_S46_BR option, which carries the IPv6
+// address of a border relay inside an S46 container option
+type OptS46BR struct {
+	BRIPv6Address net.IP
+}
+
+// Code returns the option code
+func (op *OptS46BR) Code() OptionCode {
+	return OPTION_S46_BR
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46BR) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_S46_BR))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendIP6(buf, op.BRIPv6Address)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptS46BR) Length() int {
+	return net.IPv6len
+}
+
+// Valid returns an error if the address is not a 16-byte address, see
+// checkIP6
+func (op *OptS46BR) Valid() error {
+	return checkIP6(op.BRIPv6Address)
+}
+
+func (op *OptS46BR) String() string {
+	return fmt.Sprintf("OptS46BR{br=%v}", op.BRIPv6Address)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptS46BR) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"br": op.BRIPv6Address})
+}
+
+// ParseOptS46BR builds an OptS46BR structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func ParseOptS46BR(data []byte) (*OptS46BR, error) {
+	if len(data) != net.IPv6len {
+		return nil, fmt.Errorf("Invalid S46 BR data length. Expected %v bytes, got %v", net.IPv6len, len(data))
+	}
+	opt := OptS46BR{}
+	opt.BRIPv6Address = net.IP(append([]byte(nil), data...))
+	return &opt, nil
+}
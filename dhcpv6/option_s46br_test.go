@@ -0,0 +1,24 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptS46BR(t *testing.T) {
+	data := s46ContainerData[31:]
+	opt, err := ParseOptS46BR(data)
+	require.NoError(t, err)
+	require.Equal(t, net.ParseIP("2001:db8:ffff::1"), opt.BRIPv6Address)
+	require.Equal(t, s46ContainerData[27:], opt.ToBytes())
+	require.Equal(t, "OptS46BR{br=2001:db8:ffff::1}", opt.String())
+}
+
+func TestParseOptS46BRInvalidLength(t *testing.T) {
+	_, err := ParseOptS46BR([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err)
+	_, err = ParseOptS46BR(make([]byte, 17))
+	require.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package dhcpv6
+
+// This module defines the OptS46ContainerMAPE, OptS46ContainerMAPT and
+// OptS46ContainerLW structures.
+// https://www.ietf.org/rfc/rfc7598.txt
+
+import (
+	"fmt"
+)
+
+// OptS46ContainerMAPE implements the OPTION_S46_CONT_MAPE option, which
+// carries the MAP-E configuration as a list of S46 options
+type OptS46ContainerMAPE struct {
+	Options Options
+}
+
+// Code returns the option code
+func (op *OptS46ContainerMAPE) Code() OptionCode {
+	return OPTION_S46_CONT_MAPE
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46ContainerMAPE) ToBytes() []byte {
+	return s46ContainerToBytes(OPTION_S46_CONT_MAPE, op.Options)
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptS46ContainerMAPE) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_S46_CONT_MAPE, op.Length())
+	s.WriteOptions(op.Options)
+}
+
+// Length returns the option length
+func (op *OptS46ContainerMAPE) Length() int {
+	return s46ContainerLength(op.Options)
+}
+
+func (op *OptS46ContainerMAPE) String() string {
+	return fmt.Sprintf("OptS46ContainerMAPE{options=%v}", op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptS46ContainerMAPE) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": jsonOptions(op.Options)})
+}
+
+// ParseOptS46ContainerMAPE builds an OptS46ContainerMAPE structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptS46ContainerMAPE(data []byte) (*OptS46ContainerMAPE, error) {
+	options, err := OptionsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptS46ContainerMAPE{Options: options}, nil
+}
+
+// OptS46ContainerMAPT implements the OPTION_S46_CONT_MAPT option, which
+// carries the MAP-T configuration as a list of S46 options
+type OptS46ContainerMAPT struct {
+	Options Options
+}
+
+// Code returns the option code
+func (op *OptS46ContainerMAPT) Code() OptionCode {
+	return OPTION_S46_CONT_MAPT
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46ContainerMAPT) ToBytes() []byte {
+	return s46ContainerToBytes(OPTION_S46_CONT_MAPT, op.Options)
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptS46ContainerMAPT) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_S46_CONT_MAPT, op.Length())
+	s.WriteOptions(op.Options)
+}
+
+// Length returns the option length
+func (op *OptS46ContainerMAPT) Length() int {
+	return s46ContainerLength(op.Options)
+}
+
+func (op *OptS46ContainerMAPT) String() string {
+	return fmt.Sprintf("OptS46ContainerMAPT{options=%v}", op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptS46ContainerMAPT) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": jsonOptions(op.Options)})
+}
+
+// ParseOptS46ContainerMAPT builds an OptS46ContainerMAPT structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptS46ContainerMAPT(data []byte) (*OptS46ContainerMAPT, error) {
+	options, err := OptionsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptS46ContainerMAPT{Options: options}, nil
+}
+
+// OptS46ContainerLW implements the OPTION_S46_CONT_LW option, which carries
+// the Lightweight 4over6 configuration as a list of S46 options
+type OptS46ContainerLW struct {
+	Options Options
+}
+
+// Code returns the option code
+func (op *OptS46ContainerLW) Code() OptionCode {
+	return OPTION_S46_CONT_LW
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46ContainerLW) ToBytes() []byte {
+	return s46ContainerToBytes(OPTION_S46_CONT_LW, op.Options)
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptS46ContainerLW) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_S46_CONT_LW, op.Length())
+	s.WriteOptions(op.Options)
+}
+
+// Length returns the option length
+func (op *OptS46ContainerLW) Length() int {
+	return s46ContainerLength(op.Options)
+}
+
+func (op *OptS46ContainerLW) String() string {
+	return fmt.Sprintf("OptS46ContainerLW{options=%v}", op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptS46ContainerLW) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"options": jsonOptions(op.Options)})
+}
+
+// ParseOptS46ContainerLW builds an OptS46ContainerLW structure from a
+// sequence of bytes. The input data does not include option code and length
+// bytes.
+func ParseOptS46ContainerLW(data []byte) (*OptS46ContainerLW, error) {
+	options, err := OptionsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptS46ContainerLW{Options: options}, nil
+}
+
+// s46ContainerToBytes serializes an S46 container option with the given code
+func s46ContainerToBytes(code OptionCode, options Options) []byte {
+	length := s46ContainerLength(options)
+	buf := make([]byte, 0, 4+length)
+	buf = appendUint16(buf, uint16(code))
+	buf = appendUint16(buf, uint16(length))
+	for _, opt := range options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// s46ContainerLength returns the length of an S46 container option
+func s46ContainerLength(options Options) int {
+	l := 0
+	for _, opt := range options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
@@ -0,0 +1,78 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// s46ContainerData is the content of a MAP-E container, with a forwarding
+// mapping rule for 192.0.2.0/24 and 2001:db8:ff00::/56 and a border relay
+var s46ContainerData = []byte{
+	0, 89, 0, 23, // OPTION_S46_RULE
+	0x01,         // flags
+	16,           // ea-len
+	24,           // prefix4-len
+	192, 0, 2, 0, // ipv4-prefix
+	56,                                       // prefix6-len
+	0x20, 0x01, 0x0d, 0xb8, 0xff, 0x00, 0x00, // ipv6-prefix
+	0, 93, 0, 4, 6, 8, 0x34, 0x00, // OPTION_S46_PORTPARAMS
+	0, 90, 0, 16, // OPTION_S46_BR
+	0x20, 0x01, 0x0d, 0xb8, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+}
+
+func TestParseOptS46ContainerMAPE(t *testing.T) {
+	opt, err := ParseOptS46ContainerMAPE(s46ContainerData)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.Options))
+	require.Equal(t, OPTION_S46_RULE, opt.Options[0].Code())
+	require.Equal(t, OPTION_S46_BR, opt.Options[1].Code())
+	require.Equal(t, len(s46ContainerData), opt.Length())
+	expected := append([]byte{0, 94, 0, byte(len(s46ContainerData))}, s46ContainerData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptS46ContainerMAPT(t *testing.T) {
+	opt, err := ParseOptS46ContainerMAPT(s46ContainerData)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.Options))
+	expected := append([]byte{0, 95, 0, byte(len(s46ContainerData))}, s46ContainerData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptS46ContainerLW(t *testing.T) {
+	opt, err := ParseOptS46ContainerLW(s46ContainerData)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.Options))
+	expected := append([]byte{0, 96, 0, byte(len(s46ContainerData))}, s46ContainerData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptS46ContainerInvalid(t *testing.T) {
+	_, err := ParseOptS46ContainerMAPE([]byte{0, 90, 0, 16, 0xaa})
+	require.Error(t, err)
+}
+
+func TestParseOptionS46ContainerRoundTrip(t *testing.T) {
+	for _, code := range []OptionCode{OPTION_S46_CONT_MAPE, OPTION_S46_CONT_MAPT, OPTION_S46_CONT_LW} {
+		data := append([]byte{0, byte(code), 0, byte(len(s46ContainerData))}, s46ContainerData...)
+		opt, err := ParseOption(data)
+		require.NoError(t, err)
+		require.Equal(t, code, opt.Code())
+		require.Equal(t, data, opt.ToBytes())
+		s := NewSerializer(0)
+		s.WriteOption(opt)
+		require.Equal(t, data, s.Bytes())
+	}
+}
+
+func TestParseOptS46ContainerMAPETypedOptions(t *testing.T) {
+	opt, err := ParseOptS46ContainerMAPE(s46ContainerData)
+	require.NoError(t, err)
+	rule, ok := opt.Options[0].(*OptS46Rule)
+	require.True(t, ok)
+	require.Equal(t, "2001:db8:ff00::/56", rule.IPv6Prefix.String())
+	br, ok := opt.Options[1].(*OptS46BR)
+	require.True(t, ok)
+	require.Equal(t, "2001:db8:ffff::1", br.BRIPv6Address.String())
+}
@@ -0,0 +1,124 @@
+package dhcpv6
+
+// This module defines the OptS46Rule structure.
+// https://www.ietf.org/rfc/rfc7598.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// S46RuleFlagFMR is set in the flags of an S46 rule that is also a
+// Forwarding Mapping Rule
+const S46RuleFlagFMR uint8 = 0x01
+
+// OptS46Rule implements the OPTION_S46_RULE option, which describes a MAP
+// rule inside an S46 container option
+type OptS46Rule struct {
+	Flags      uint8
+	EALen      uint8
+	IPv4Prefix net.IPNet
+	IPv6Prefix net.IPNet
+	Options    Options
+}
+
+// Code returns the option code
+func (op *OptS46Rule) Code() OptionCode {
+	return OPTION_S46_RULE
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46Rule) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_S46_RULE))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Flags, op.EALen)
+	prefix4Len, _ := op.IPv4Prefix.Mask.Size()
+	buf = append(buf, uint8(prefix4Len))
+	var ipv4Prefix [net.IPv4len]byte
+	copy(ipv4Prefix[:], op.IPv4Prefix.IP.To4())
+	buf = append(buf, ipv4Prefix[:]...)
+	prefix6Len, _ := op.IPv6Prefix.Mask.Size()
+	buf = append(buf, uint8(prefix6Len))
+	ipv6Prefix := make([]byte, (prefix6Len+7)/8)
+	copy(ipv6Prefix, op.IPv6Prefix.IP.To16())
+	buf = append(buf, ipv6Prefix...)
+	for _, opt := range op.Options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptS46Rule) Length() int {
+	prefix6Len, _ := op.IPv6Prefix.Mask.Size()
+	l := 8 + (prefix6Len+7)/8
+	for _, opt := range op.Options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+// IsFMR returns true if the rule is a Forwarding Mapping Rule
+func (op *OptS46Rule) IsFMR() bool {
+	return op.Flags&S46RuleFlagFMR != 0
+}
+
+func (op *OptS46Rule) String() string {
+	return fmt.Sprintf("OptS46Rule{flags=%v, ealen=%v, ipv4prefix=%v, ipv6prefix=%v, options=%v}",
+		op.Flags, op.EALen, op.IPv4Prefix.String(), op.IPv6Prefix.String(), op.Options)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptS46Rule) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"flags":       op.Flags,
+		"ea_len":      op.EALen,
+		"ipv4_prefix": op.IPv4Prefix.String(),
+		"ipv6_prefix": op.IPv6Prefix.String(),
+		"options":     jsonOptions(op.Options),
+	})
+}
+
+// ParseOptS46Rule builds an OptS46Rule structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptS46Rule(data []byte) (*OptS46Rule, error) {
+	if len(data) < 8 {
+		return nil, errOptionTooShort("Invalid S46 rule data length. Expected at least 8 bytes, got %v", len(data))
+	}
+	opt := OptS46Rule{
+		Flags: data[0],
+		EALen: data[1],
+	}
+	if opt.EALen > 48 {
+		return nil, fmt.Errorf("Invalid S46 rule EA-bits length. Expected at most 48, got %v", opt.EALen)
+	}
+	prefix4Len := int(data[2])
+	if prefix4Len > 32 {
+		return nil, fmt.Errorf("Invalid S46 rule IPv4 prefix length. Expected at most 32, got %v", prefix4Len)
+	}
+	opt.IPv4Prefix = net.IPNet{
+		IP:   net.IP(append([]byte(nil), data[3:7]...)),
+		Mask: net.CIDRMask(prefix4Len, 32),
+	}
+	prefix6Len := int(data[7])
+	if prefix6Len > 128 {
+		return nil, fmt.Errorf("Invalid S46 rule IPv6 prefix length. Expected at most 128, got %v", prefix6Len)
+	}
+	end := 8 + (prefix6Len+7)/8
+	if len(data) < end {
+		return nil, errOptionTooShort("Invalid S46 rule data length. Expected at least %v bytes for a /%v IPv6 prefix, got %v", end, prefix6Len, len(data))
+	}
+	ipv6Prefix := make(net.IP, net.IPv6len)
+	copy(ipv6Prefix, data[8:end])
+	opt.IPv6Prefix = net.IPNet{
+		IP:   ipv6Prefix,
+		Mask: net.CIDRMask(prefix6Len, 128),
+	}
+	var err error
+	opt.Options, err = OptionsFromBytes(data[end:])
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
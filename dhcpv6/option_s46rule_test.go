@@ -0,0 +1,72 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptS46Rule(t *testing.T) {
+	// the rule from s46ContainerData, without the option header
+	data := s46ContainerData[4:27]
+	opt, err := ParseOptS46Rule(data)
+	require.NoError(t, err)
+	require.True(t, opt.IsFMR())
+	require.Equal(t, uint8(16), opt.EALen)
+	require.Equal(t, "192.0.2.0/24", opt.IPv4Prefix.String())
+	require.Equal(t, "2001:db8:ff00::/56", opt.IPv6Prefix.String())
+	require.Equal(t, 1, len(opt.Options))
+	require.Equal(t, OPTION_S46_PORTPARAMS, opt.Options[0].Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, s46ContainerData[:27], opt.ToBytes())
+}
+
+func TestOptS46RuleToBytes(t *testing.T) {
+	_, ipv4Prefix, err := net.ParseCIDR("198.51.100.0/22")
+	require.NoError(t, err)
+	_, ipv6Prefix, err := net.ParseCIDR("2001:db8::/41")
+	require.NoError(t, err)
+	opt := OptS46Rule{
+		EALen:      18,
+		IPv4Prefix: *ipv4Prefix,
+		IPv6Prefix: *ipv6Prefix,
+	}
+	expected := []byte{
+		0, 89, 0, 14,
+		0, 18, 22,
+		198, 51, 100, 0,
+		41,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+	require.False(t, opt.IsFMR())
+}
+
+func TestOptS46RuleZeroLengthIPv6Prefix(t *testing.T) {
+	data := []byte{0, 0, 0, 0, 0, 0, 0, 0}
+	opt, err := ParseOptS46Rule(data)
+	require.NoError(t, err)
+	require.Equal(t, 8, opt.Length())
+	require.Equal(t, append([]byte{0, 89, 0, 8}, data...), opt.ToBytes())
+}
+
+func TestParseOptS46RuleInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		// too short
+		{0x01, 16, 24, 192, 0, 2, 0},
+		// ea-len > 48
+		{0x01, 49, 24, 192, 0, 2, 0, 0},
+		// prefix4-len > 32
+		{0x01, 16, 33, 192, 0, 2, 0, 0},
+		// prefix6-len > 128
+		{0x01, 16, 24, 192, 0, 2, 0, 129},
+		// truncated IPv6 prefix
+		{0x01, 16, 24, 192, 0, 2, 0, 56, 0x20, 0x01, 0x0d, 0xb8},
+		// truncated sub-option
+		{0x01, 16, 24, 192, 0, 2, 0, 8, 0x20, 0, 93, 0, 4, 6},
+	} {
+		_, err := ParseOptS46Rule(data)
+		require.Error(t, err, "data %v", data)
+	}
+}
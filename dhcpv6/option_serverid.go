@@ -4,7 +4,6 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3315.txt
 
 import (
-	"encoding/binary"
 	"fmt"
 )
 
@@ -18,13 +17,30 @@ func (op *OptServerId) Code() OptionCode {
 }
 
 func (op *OptServerId) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_SERVERID))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_SERVERID))
+	buf = appendUint16(buf, uint16(op.Length()))
 	buf = append(buf, op.Sid.ToBytes()...)
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptServerId) Clone() Option {
+	return &OptServerId{Sid: op.Sid.clone()}
+}
+
+// Equal returns true if other is an OptServerId with an equal DUID
+func (op *OptServerId) Equal(other Option) bool {
+	o, ok := other.(*OptServerId)
+	return ok && op.Sid.Equal(&o.Sid)
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptServerId) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_SERVERID, op.Length())
+	op.Sid.SerializeTo(s)
+}
+
 func (op *OptServerId) Length() int {
 	return op.Sid.Length()
 }
@@ -33,12 +49,17 @@ func (op *OptServerId) String() string {
 	return fmt.Sprintf("OptServerId{sid=%v}", op.Sid.String())
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptServerId) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"duid": &op.Sid})
+}
+
 // ParseOptServerId builds an OptServerId structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptServerId(data []byte) (*OptServerId, error) {
 	if len(data) < 2 {
 		// at least the DUID type is necessary to continue
-		return nil, fmt.Errorf("Invalid OptServerId data: shorter than 2 bytes")
+		return nil, errOptionTooShort("Invalid OptServerId data: shorter than 2 bytes")
 	}
 	opt := OptServerId{}
 	sid, err := DuidFromBytes(data)
@@ -0,0 +1,54 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptSIPServersDomainNameList(t *testing.T) {
+	data := []byte{
+		3, 's', 'i', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		4, 's', 'i', 'p', '2', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	opt, err := ParseOptSIPServersDomainNameList(data)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sip.example.com", "sip2.example.com"}, opt.DomainNames)
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 21, 0, byte(len(data))}, data...), opt.ToBytes())
+	require.Equal(t, "OptSIPServersDomainNameList{sipservers=[sip.example.com sip2.example.com]}", opt.String())
+}
+
+func TestParseOptSIPServersDomainNameListInvalid(t *testing.T) {
+	_, err := ParseOptSIPServersDomainNameList([]byte{3, 's', 'i'})
+	require.Error(t, err)
+}
+
+func TestParseOptSIPServersAddressList(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02,
+	}
+	opt, err := ParseOptSIPServersAddressList(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.SIPServers))
+	require.True(t, opt.SIPServers[0].Equal(net.ParseIP("2001:db8::1")))
+	require.True(t, opt.SIPServers[1].Equal(net.ParseIP("2001:db8::2")))
+	require.Equal(t, append([]byte{0, 22, 0, 32}, data...), opt.ToBytes())
+	require.Equal(t, "OptSIPServersAddressList{sipservers=[2001:db8::1 2001:db8::2]}", opt.String())
+}
+
+func TestParseOptSIPServersAddressListInvalidLength(t *testing.T) {
+	_, err := ParseOptSIPServersAddressList(make([]byte, 15))
+	require.Error(t, err)
+}
+
+func TestParseOptionSIPServers(t *testing.T) {
+	opt, err := ParseOption([]byte{0, 21, 0, 5, 3, 's', 'i', 'p', 0})
+	require.NoError(t, err)
+	require.IsType(t, &OptSIPServersDomainNameList{}, opt)
+	opt, err = ParseOption(append([]byte{0, 22, 0, 16}, net.IPv6loopback...))
+	require.NoError(t, err)
+	require.IsType(t, &OptSIPServersAddressList{}, opt)
+}
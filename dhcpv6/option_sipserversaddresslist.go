@@ -0,0 +1,61 @@
+package dhcpv6
+
+// This module defines the OptSIPServersAddressList structure.
+// https://www.ietf.org/rfc/rfc3319.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptSIPServersAddressList implements the SIP_SERVERS_IPV6_ADDRESS_LIST
+// option
+type OptSIPServersAddressList struct {
+	SIPServers []net.IP
+}
+
+// Code returns the option code
+func (op *OptSIPServersAddressList) Code() OptionCode {
+	return SIP_SERVERS_IPV6_ADDRESS_LIST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSIPServersAddressList) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(SIP_SERVERS_IPV6_ADDRESS_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.SIPServers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptSIPServersAddressList) Length() int {
+	return len(op.SIPServers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptSIPServersAddressList) Valid() error {
+	return checkIP6List(op.SIPServers)
+}
+
+func (op *OptSIPServersAddressList) String() string {
+	return fmt.Sprintf("OptSIPServersAddressList{sipservers=%v}", op.SIPServers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptSIPServersAddressList) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"servers": op.SIPServers})
+}
+
+// ParseOptSIPServersAddressList builds an OptSIPServersAddressList structure
+// from a sequence of bytes. The input data does not include option code and
+// length bytes.
+func ParseOptSIPServersAddressList(data []byte) (*OptSIPServersAddressList, error) {
+	sipServers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptSIPServersAddressList{SIPServers: sipServers}
+	return &opt, nil
+}
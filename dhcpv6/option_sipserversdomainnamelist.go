@@ -0,0 +1,61 @@
+package dhcpv6
+
+// This module defines the OptSIPServersDomainNameList structure.
+// https://www.ietf.org/rfc/rfc3319.txt
+
+import (
+	"fmt"
+)
+
+// OptSIPServersDomainNameList implements the SIP_SERVERS_DOMAIN_NAME_LIST
+// option
+type OptSIPServersDomainNameList struct {
+	DomainNames []string
+}
+
+// Code returns the option code
+func (op *OptSIPServersDomainNameList) Code() OptionCode {
+	return SIP_SERVERS_DOMAIN_NAME_LIST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSIPServersDomainNameList) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(SIP_SERVERS_DOMAIN_NAME_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, LabelsToBytes(op.DomainNames)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptSIPServersDomainNameList) Length() int {
+	return len(LabelsToBytes(op.DomainNames))
+}
+
+// Valid returns an error if a domain name has an empty label or a label
+// longer than 63 bytes
+func (op *OptSIPServersDomainNameList) Valid() error {
+	_, err := labelsToBytes(op.DomainNames)
+	return err
+}
+
+func (op *OptSIPServersDomainNameList) String() string {
+	return fmt.Sprintf("OptSIPServersDomainNameList{sipservers=%v}", op.DomainNames)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptSIPServersDomainNameList) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"domains": op.DomainNames})
+}
+
+// ParseOptSIPServersDomainNameList builds an OptSIPServersDomainNameList
+// structure from a sequence of bytes. The input data does not include option
+// code and length bytes.
+func ParseOptSIPServersDomainNameList(data []byte) (*OptSIPServersDomainNameList, error) {
+	domainNames, err := LabelsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptSIPServersDomainNameList{DomainNames: domainNames}
+	return &opt, nil
+}
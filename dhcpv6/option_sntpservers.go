@@ -0,0 +1,59 @@
+package dhcpv6
+
+// This module defines the OptSNTPServers structure.
+// https://www.ietf.org/rfc/rfc4075.txt
+
+import (
+	"fmt"
+	"net"
+)
+
+// OptSNTPServers represents a SNTP_SERVER_LIST option
+type OptSNTPServers struct {
+	NTPServers []net.IP
+}
+
+// Code returns the option code
+func (op *OptSNTPServers) Code() OptionCode {
+	return SNTP_SERVER_LIST
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSNTPServers) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(SNTP_SERVER_LIST))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, ip6ListToBytes(op.NTPServers)...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptSNTPServers) Length() int {
+	return len(op.NTPServers) * net.IPv6len
+}
+
+// Valid returns an error if one of the addresses is not a 16-byte address,
+// see checkIP6
+func (op *OptSNTPServers) Valid() error {
+	return checkIP6List(op.NTPServers)
+}
+
+func (op *OptSNTPServers) String() string {
+	return fmt.Sprintf("OptSNTPServers{ntpservers=%v}", op.NTPServers)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptSNTPServers) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"servers": op.NTPServers})
+}
+
+// ParseOptSNTPServers builds an OptSNTPServers structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptSNTPServers(data []byte) (*OptSNTPServers, error) {
+	ntpServers, err := parseIP6List(data)
+	if err != nil {
+		return nil, err
+	}
+	opt := OptSNTPServers{NTPServers: ntpServers}
+	return &opt, nil
+}
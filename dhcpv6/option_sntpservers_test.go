@@ -0,0 +1,37 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptSNTPServers(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02,
+	}
+	opt, err := ParseOptSNTPServers(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opt.NTPServers))
+	require.True(t, opt.NTPServers[0].Equal(net.ParseIP("2001:db8::1")))
+	require.True(t, opt.NTPServers[1].Equal(net.ParseIP("2001:db8::2")))
+	require.Equal(t, 32, opt.Length())
+}
+
+func TestParseOptSNTPServersInvalidLength(t *testing.T) {
+	_, err := ParseOptSNTPServers(make([]byte, 17))
+	require.Error(t, err)
+}
+
+func TestOptSNTPServersToBytes(t *testing.T) {
+	ntp := net.ParseIP("2001:db8::1")
+	opt := OptSNTPServers{NTPServers: []net.IP{ntp}}
+	expected := []byte{
+		0, 31, // SNTP_SERVER_LIST
+		0, 16, // length
+	}
+	expected = append(expected, ntp...)
+	require.Equal(t, expected, opt.ToBytes())
+}
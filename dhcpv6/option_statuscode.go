@@ -23,30 +23,56 @@ func (op *OptStatusCode) Code() OptionCode {
 
 // ToBytes serializes the option and returns it as a sequence of bytes
 func (op *OptStatusCode) ToBytes() []byte {
-	buf := make([]byte, 6)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_STATUS_CODE))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	binary.BigEndian.PutUint16(buf[4:6], uint16(op.StatusCode))
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_STATUS_CODE))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint16(buf, uint16(op.StatusCode))
 	buf = append(buf, op.StatusMessage...)
 	return buf
 }
 
+// Clone returns a deep copy of the option
+func (op *OptStatusCode) Clone() Option {
+	return &OptStatusCode{StatusCode: op.StatusCode, StatusMessage: append([]byte(nil), op.StatusMessage...)}
+}
+
+// SerializeTo writes the option to a Serializer
+func (op *OptStatusCode) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(OPTION_STATUS_CODE, op.Length())
+	s.Write16(uint16(op.StatusCode))
+	s.WriteBytes(op.StatusMessage)
+}
+
 // Length returns the option length
 func (op *OptStatusCode) Length() int {
 	return 2 + len(op.StatusMessage)
 }
 
+// Succeeded returns true if the status code is StatusSuccess
+func (op *OptStatusCode) Succeeded() bool {
+	return op.StatusCode == iana.StatusSuccess
+}
+
 func (op *OptStatusCode) String() string {
 	return fmt.Sprintf("OptStatusCode{code=%s (%d), message=%v}",
 		iana.StatusCodeToString(op.StatusCode), op.StatusCode,
 		string(op.StatusMessage))
 }
 
+// MarshalJSON returns the JSON representation of the option
+func (op *OptStatusCode) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{
+		"status_code":    op.StatusCode,
+		"status_name":    iana.StatusCodeToString(op.StatusCode),
+		"status_message": string(op.StatusMessage),
+	})
+}
+
 // ParseOptStatusCode builds an OptStatusCode structure from a sequence of
 // bytes. The input data does not include option code and length bytes.
 func ParseOptStatusCode(data []byte) (*OptStatusCode, error) {
 	if len(data) < 2 {
-		return nil, fmt.Errorf("Invalid OptStatusCode data: length is shorter than 2")
+		return nil, errOptionTooShort("Invalid OptStatusCode data: length is shorter than 2")
 	}
 	opt := OptStatusCode{}
 	opt.StatusCode = iana.StatusCode(binary.BigEndian.Uint16(data[0:2]))
@@ -32,3 +32,20 @@ func TestOptStatusCodeToBytes(t *testing.T) {
 	actual := opt.ToBytes()
 	require.Equal(t, expected, actual)
 }
+
+func TestParseOptStatusCodeNoMessage(t *testing.T) {
+	opt, err := ParseOptStatusCode([]byte{0, 6})
+	require.NoError(t, err)
+	require.Equal(t, iana.StatusNoPrefixAvail, opt.StatusCode)
+	require.Empty(t, opt.StatusMessage)
+	require.Equal(t, 2, opt.Length())
+	require.False(t, opt.Succeeded())
+	require.Equal(t, "OptStatusCode{code=NoPrefixAvail (6), message=}", opt.String())
+}
+
+func TestOptStatusCodeSucceeded(t *testing.T) {
+	opt := OptStatusCode{StatusCode: iana.StatusSuccess}
+	require.True(t, opt.Succeeded())
+	opt.StatusCode = iana.StatusNoAddrsAvail
+	require.False(t, opt.Succeeded())
+}
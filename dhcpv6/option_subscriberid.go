@@ -0,0 +1,56 @@
+package dhcpv6
+
+// This module defines the OptSubscriberID structure.
+// https://www.ietf.org/rfc/rfc4580.txt
+
+import (
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+)
+
+// OptSubscriberID implements the RELAY_AGENT_SUBSCRIBER_ID option
+type OptSubscriberID struct {
+	SubscriberID []byte
+}
+
+// Code returns the option code
+func (op *OptSubscriberID) Code() OptionCode {
+	return RELAY_AGENT_SUBSCRIBER_ID
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSubscriberID) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(RELAY_AGENT_SUBSCRIBER_ID))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.SubscriberID...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptSubscriberID) Length() int {
+	return len(op.SubscriberID)
+}
+
+// String returns the subscriber ID as text if it is valid UTF-8, and as hex
+// otherwise
+func (op *OptSubscriberID) String() string {
+	if utf8.Valid(op.SubscriberID) {
+		return fmt.Sprintf("OptSubscriberID{subscriberid=%s}", op.SubscriberID)
+	}
+	return fmt.Sprintf("OptSubscriberID{subscriberid=0x%s}", hex.EncodeToString(op.SubscriberID))
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptSubscriberID) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"subscriber_id": op.SubscriberID})
+}
+
+// ParseOptSubscriberID builds an OptSubscriberID structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptSubscriberID(data []byte) (*OptSubscriberID, error) {
+	opt := OptSubscriberID{}
+	opt.SubscriberID = append([]byte(nil), data...)
+	return &opt, nil
+}
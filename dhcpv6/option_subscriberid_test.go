@@ -0,0 +1,26 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptSubscriberID(t *testing.T) {
+	data := []byte("subscriber-42")
+	opt, err := ParseOptSubscriberID(data)
+	require.NoError(t, err)
+	require.Equal(t, data, opt.SubscriberID)
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, "OptSubscriberID{subscriberid=subscriber-42}", opt.String())
+}
+
+func TestOptSubscriberIDStringHex(t *testing.T) {
+	opt := OptSubscriberID{SubscriberID: []byte{0xff, 0xfe, 0x01}}
+	require.Equal(t, "OptSubscriberID{subscriberid=0xfffe01}", opt.String())
+}
+
+func TestOptSubscriberIDToBytes(t *testing.T) {
+	opt := OptSubscriberID{SubscriberID: []byte("sub")}
+	require.Equal(t, []byte{0, 38, 0, 3, 's', 'u', 'b'}, opt.ToBytes())
+}
@@ -0,0 +1,97 @@
+package dhcpv6
+
+// This module defines the OptPosixTimezone and OptTZDBTimezone structures.
+// https://www.ietf.org/rfc/rfc4833.txt
+
+import (
+	"fmt"
+)
+
+// OptPosixTimezone implements the OPTION_NEW_POSIX_TIMEZONE option, which
+// carries a POSIX TZ string, e.g. "EST5EDT,M3.2.0/2,M11.1.0/2"
+type OptPosixTimezone struct {
+	Timezone string
+}
+
+// Code returns the option code
+func (op *OptPosixTimezone) Code() OptionCode {
+	return OPTION_NEW_POSIX_TIMEZONE
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptPosixTimezone) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NEW_POSIX_TIMEZONE))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Timezone...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptPosixTimezone) Length() int {
+	return len(op.Timezone)
+}
+
+func (op *OptPosixTimezone) String() string {
+	return fmt.Sprintf("OptPosixTimezone{timezone=%v}", op.Timezone)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptPosixTimezone) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"timezone": op.Timezone})
+}
+
+// ParseOptPosixTimezone builds an OptPosixTimezone structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptPosixTimezone(data []byte) (*OptPosixTimezone, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Invalid POSIX timezone data length. Expected at least 1 byte, got 0")
+	}
+	opt := OptPosixTimezone{Timezone: string(data)}
+	return &opt, nil
+}
+
+// OptTZDBTimezone implements the OPTION_NEW_TZDB_TIMEZONE option, which
+// carries the name of a timezone in the IANA tz database, e.g.
+// "America/New_York"
+type OptTZDBTimezone struct {
+	Timezone string
+}
+
+// Code returns the option code
+func (op *OptTZDBTimezone) Code() OptionCode {
+	return OPTION_NEW_TZDB_TIMEZONE
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptTZDBTimezone) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_NEW_TZDB_TIMEZONE))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = append(buf, op.Timezone...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptTZDBTimezone) Length() int {
+	return len(op.Timezone)
+}
+
+func (op *OptTZDBTimezone) String() string {
+	return fmt.Sprintf("OptTZDBTimezone{timezone=%v}", op.Timezone)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptTZDBTimezone) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"timezone": op.Timezone})
+}
+
+// ParseOptTZDBTimezone builds an OptTZDBTimezone structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptTZDBTimezone(data []byte) (*OptTZDBTimezone, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Invalid TZDB timezone data length. Expected at least 1 byte, got 0")
+	}
+	opt := OptTZDBTimezone{Timezone: string(data)}
+	return &opt, nil
+}
@@ -0,0 +1,41 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptPosixTimezone(t *testing.T) {
+	data := []byte("EST5EDT,M3.2.0/2,M11.1.0/2")
+	opt, err := ParseOptPosixTimezone(data)
+	require.NoError(t, err)
+	require.Equal(t, "EST5EDT,M3.2.0/2,M11.1.0/2", opt.Timezone)
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, append([]byte{0, 41, 0, byte(len(data))}, data...), opt.ToBytes())
+	require.Equal(t, "OptPosixTimezone{timezone=EST5EDT,M3.2.0/2,M11.1.0/2}", opt.String())
+
+	_, err = ParseOptPosixTimezone([]byte{})
+	require.Error(t, err)
+}
+
+func TestParseOptTZDBTimezone(t *testing.T) {
+	data := []byte("America/New_York")
+	opt, err := ParseOptTZDBTimezone(data)
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", opt.Timezone)
+	require.Equal(t, append([]byte{0, 42, 0, byte(len(data))}, data...), opt.ToBytes())
+	require.Equal(t, "OptTZDBTimezone{timezone=America/New_York}", opt.String())
+
+	_, err = ParseOptTZDBTimezone(nil)
+	require.Error(t, err)
+}
+
+func TestParseOptionTimezone(t *testing.T) {
+	opt, err := ParseOption([]byte{0, 41, 0, 4, 'U', 'T', 'C', '0'})
+	require.NoError(t, err)
+	require.IsType(t, &OptPosixTimezone{}, opt)
+	opt, err = ParseOption([]byte{0, 42, 0, 3, 'U', 'T', 'C'})
+	require.NoError(t, err)
+	require.IsType(t, &OptTZDBTimezone{}, opt)
+}
@@ -1,5 +1,10 @@
 package dhcpv6
 
+import (
+	"fmt"
+	"sync"
+)
+
 // FIXME: rename all the options to have a consistent name, e.g. OPT_<NAME>
 const (
 	OPTION_CLIENTID     OptionCode = 1
@@ -75,59 +80,132 @@ const (
 	MIPV6_HOME_NETWORK_PREFIX                   OptionCode = 71
 	MIPV6_HOME_AGENT_ADDRESS                    OptionCode = 72
 	MIPV6_HOME_AGENT_FQDN                       OptionCode = 73
+	OPTION_RDNSS_SELECTION                      OptionCode = 74
+	OPTION_KRB_PRINCIPAL_NAME                   OptionCode = 75
+	OPTION_KRB_REALM_NAME                       OptionCode = 76
+	OPTION_KRB_DEFAULT_REALM_NAME               OptionCode = 77
+	OPTION_KRB_KDC                              OptionCode = 78
+	OPTION_CLIENT_LINKLAYER_ADDR                OptionCode = 79
+	OPTION_LINK_ADDRESS                         OptionCode = 80
+	OPTION_RADIUS                               OptionCode = 81
+	OPTION_SOL_MAX_RT                           OptionCode = 82
+	OPTION_INF_MAX_RT                           OptionCode = 83
+	OPTION_ADDRSEL                              OptionCode = 84
+	OPTION_ADDRSEL_TABLE                        OptionCode = 85
+	OPTION_V6_PCP_SERVER                        OptionCode = 86
+	OPTION_DHCPV4_MSG                           OptionCode = 87
+	OPTION_DHCP4_O_DHCP6_SERVER                 OptionCode = 88
+	OPTION_S46_RULE                             OptionCode = 89
+	OPTION_S46_BR                               OptionCode = 90
+	OPTION_S46_DMR                              OptionCode = 91
+	OPTION_S46_V4V6BIND                         OptionCode = 92
+	OPTION_S46_PORTPARAMS                       OptionCode = 93
+	OPTION_S46_CONT_MAPE                        OptionCode = 94
+	OPTION_S46_CONT_MAPT                        OptionCode = 95
+	OPTION_S46_CONT_LW                          OptionCode = 96
+	OPTION_4RD                                  OptionCode = 97
+	OPTION_4RD_MAP_RULE                         OptionCode = 98
+	OPTION_4RD_NON_MAP_RULE                     OptionCode = 99
+	OPTION_LQ_BASE_TIME                         OptionCode = 100
+	OPTION_LQ_START_TIME                        OptionCode = 101
+	OPTION_LQ_END_TIME                          OptionCode = 102
+	OPTION_CAPTIVE_PORTAL                       OptionCode = 103
+	OPTION_MPL_PARAMETERS                       OptionCode = 104
+	OPTION_ANI_ATT                              OptionCode = 105
+	OPTION_ANI_NETWORK_NAME                     OptionCode = 106
+	OPTION_ANI_AP_NAME                          OptionCode = 107
+	OPTION_ANI_AP_BSSID                         OptionCode = 108
+	OPTION_ANI_OPERATOR_ID                      OptionCode = 109
+	OPTION_ANI_OPERATOR_REALM                   OptionCode = 110
+	OPTION_S46_PRIORITY                         OptionCode = 111
+	OPTION_MUD_URL_V6                           OptionCode = 112
+	OPTION_V6_PREFIX64                          OptionCode = 113
+	OPTION_F_BINDING_STATUS                     OptionCode = 114
+	OPTION_F_CONNECT_FLAGS                      OptionCode = 115
+	OPTION_F_DNS_REMOVAL_INFO                   OptionCode = 116
+	OPTION_F_DNS_HOST_NAME                      OptionCode = 117
+	OPTION_F_DNS_ZONE_NAME                      OptionCode = 118
+	OPTION_F_DNS_FLAGS                          OptionCode = 119
+	OPTION_F_EXPIRATION_TIME                    OptionCode = 120
+	OPTION_F_MAX_UNACKED_BNDUPD                 OptionCode = 121
+	OPTION_F_MCLT                               OptionCode = 122
+	OPTION_F_PARTNER_LIFETIME                   OptionCode = 123
+	OPTION_F_PARTNER_LIFETIME_SENT              OptionCode = 124
+	OPTION_F_PARTNER_DOWN_TIME                  OptionCode = 125
+	OPTION_F_PARTNER_RAW_CLT_TIME               OptionCode = 126
+	OPTION_F_PROTOCOL_VERSION                   OptionCode = 127
+	OPTION_F_KEEPALIVE_TIME                     OptionCode = 128
+	OPTION_F_RECONFIGURE_DATA                   OptionCode = 129
+	OPTION_F_RELATIONSHIP_NAME                  OptionCode = 130
+	OPTION_F_SERVER_FLAGS                       OptionCode = 131
+	OPTION_F_SERVER_STATE                       OptionCode = 132
+	OPTION_F_START_TIME_OF_STATE                OptionCode = 133
+	OPTION_F_STATE_EXPIRATION_TIME              OptionCode = 134
+	OPTION_RELAY_PORT                           OptionCode = 135
+	OPTION_V6_SZTP_REDIRECT                     OptionCode = 136
+	OPTION_S46_BIND_IPV6_PREFIX                 OptionCode = 137
+	OPTION_IA_LL                                OptionCode = 138
+	OPTION_LLADDR                               OptionCode = 139
+	OPTION_SLAP_QUAD                            OptionCode = 140
+	OPTION_V6_DOTS_RI                           OptionCode = 141
+	OPTION_V6_DOTS_ADDRESS                      OptionCode = 142
+	OPTION_IPV6_ADDRESS_ANDSF                   OptionCode = 143
 )
 
+// OptionCodeToString maps option codes to their built-in names. It is
+// read-only: it must not be modified, and does not include the names added at
+// runtime with RegisterOptionCode.
 var OptionCodeToString = map[OptionCode]string{
-	OPTION_CLIENTID:                    "OPTION_CLIENTID",
-	OPTION_SERVERID:                    "OPTION_SERVERID",
-	OPTION_IA_NA:                       "OPTION_IA_NA",
-	OPTION_IA_TA:                       "OPTION_IA_TA",
-	OPTION_IAADDR:                      "OPTION_IAADDR",
-	OPTION_ORO:                         "OPTION_ORO",
-	OPTION_PREFERENCE:                  "OPTION_PREFERENCE",
-	OPTION_ELAPSED_TIME:                "OPTION_ELAPSED_TIME",
-	OPTION_RELAY_MSG:                   "OPTION_RELAY_MSG",
-	OPTION_AUTH:                        "OPTION_AUTH",
-	OPTION_UNICAST:                     "OPTION_UNICAST",
-	OPTION_STATUS_CODE:                 "OPTION_STATUS_CODE",
-	OPTION_RAPID_COMMIT:                "OPTION_RAPID_COMMIT",
-	OPTION_USER_CLASS:                  "OPTION_USER_CLASS",
-	OPTION_VENDOR_CLASS:                "OPTION_VENDOR_CLASS",
-	OPTION_VENDOR_OPTS:                 "OPTION_VENDOR_OPTS",
-	OPTION_INTERFACE_ID:                "OPTION_INTERFACE_ID",
-	OPTION_RECONF_MSG:                  "OPTION_RECONF_MSG",
-	OPTION_RECONF_ACCEPT:               "OPTION_RECONF_ACCEPT",
-	SIP_SERVERS_DOMAIN_NAME_LIST:       "SIP Servers Domain Name List",
-	SIP_SERVERS_IPV6_ADDRESS_LIST:      "SIP Servers IPv6 Address List",
-	DNS_RECURSIVE_NAME_SERVER:          "DNS Recursive Name Server",
-	DOMAIN_SEARCH_LIST:                 "Domain Search List",
-	OPTION_IA_PD:                       "OPTION_IA_PD",
-	OPTION_IAPREFIX:                    "OPTION_IAPREFIX",
-	OPTION_NIS_SERVERS:                 "OPTION_NIS_SERVERS",
-	OPTION_NISP_SERVERS:                "OPTION_NISP_SERVERS",
-	OPTION_NIS_DOMAIN_NAME:             "OPTION_NIS_DOMAIN_NAME",
-	OPTION_NISP_DOMAIN_NAME:            "OPTION_NISP_DOMAIN_NAME",
-	SNTP_SERVER_LIST:                   "SNTP Server List",
-	INFORMATION_REFRESH_TIME:           "Information Refresh Time",
-	BCMCS_CONTROLLER_DOMAIN_NAME_LIST:  "BCMCS Controller Domain Name List",
-	BCMCS_CONTROLLER_IPV6_ADDRESS_LIST: "BCMCS Controller IPv6 Address List",
-	OPTION_GEOCONF_CIVIC:               "OPTION_GEOCONF",
-	OPTION_REMOTE_ID:                   "OPTION_REMOTE_ID",
-	RELAY_AGENT_SUBSCRIBER_ID:          "Relay-Agent Subscriber ID",
-	FQDN: "FQDN",
-	PANA_AUTHENTICATION_AGENT:              "PANA Authentication Agent",
-	OPTION_NEW_POSIX_TIMEZONE:              "OPTION_NEW_POSIX_TIME_ZONE",
-	OPTION_NEW_TZDB_TIMEZONE:               "OPTION_NEW_TZDB_TIMEZONE",
-	ECHO_REQUEST:                           "Echo Request",
-	OPTION_LQ_QUERY:                        "OPTION_LQ_QUERY",
-	OPTION_CLIENT_DATA:                     "OPTION_CLIENT_DATA",
-	OPTION_CLT_TIME:                        "OPTION_CLT_TIME",
-	OPTION_LQ_RELAY_DATA:                   "OPTION_LQ_RELAY_DATA",
-	OPTION_LQ_CLIENT_LINK:                  "OPTION_LQ_CLIENT_LINK",
-	MIPV6_HOME_NETWORK_ID_FQDN:             "MIPv6 Home Network ID FQDN",
-	MIPV6_VISITED_HOME_NETWORK_INFORMATION: "MIPv6 Visited Home Network Information",
-	LOST_SERVER:                            "LoST Server",
-	CAPWAP_ACCESS_CONTROLLER_ADDRESSES:     "CAPWAP Access Controller Addresses",
+	OPTION_CLIENTID:                             "OPTION_CLIENTID",
+	OPTION_SERVERID:                             "OPTION_SERVERID",
+	OPTION_IA_NA:                                "OPTION_IA_NA",
+	OPTION_IA_TA:                                "OPTION_IA_TA",
+	OPTION_IAADDR:                               "OPTION_IAADDR",
+	OPTION_ORO:                                  "OPTION_ORO",
+	OPTION_PREFERENCE:                           "OPTION_PREFERENCE",
+	OPTION_ELAPSED_TIME:                         "OPTION_ELAPSED_TIME",
+	OPTION_RELAY_MSG:                            "OPTION_RELAY_MSG",
+	OPTION_AUTH:                                 "OPTION_AUTH",
+	OPTION_UNICAST:                              "OPTION_UNICAST",
+	OPTION_STATUS_CODE:                          "OPTION_STATUS_CODE",
+	OPTION_RAPID_COMMIT:                         "OPTION_RAPID_COMMIT",
+	OPTION_USER_CLASS:                           "OPTION_USER_CLASS",
+	OPTION_VENDOR_CLASS:                         "OPTION_VENDOR_CLASS",
+	OPTION_VENDOR_OPTS:                          "OPTION_VENDOR_OPTS",
+	OPTION_INTERFACE_ID:                         "OPTION_INTERFACE_ID",
+	OPTION_RECONF_MSG:                           "OPTION_RECONF_MSG",
+	OPTION_RECONF_ACCEPT:                        "OPTION_RECONF_ACCEPT",
+	SIP_SERVERS_DOMAIN_NAME_LIST:                "SIP Servers Domain Name List",
+	SIP_SERVERS_IPV6_ADDRESS_LIST:               "SIP Servers IPv6 Address List",
+	DNS_RECURSIVE_NAME_SERVER:                   "DNS Recursive Name Server",
+	DOMAIN_SEARCH_LIST:                          "Domain Search List",
+	OPTION_IA_PD:                                "OPTION_IA_PD",
+	OPTION_IAPREFIX:                             "OPTION_IAPREFIX",
+	OPTION_NIS_SERVERS:                          "OPTION_NIS_SERVERS",
+	OPTION_NISP_SERVERS:                         "OPTION_NISP_SERVERS",
+	OPTION_NIS_DOMAIN_NAME:                      "OPTION_NIS_DOMAIN_NAME",
+	OPTION_NISP_DOMAIN_NAME:                     "OPTION_NISP_DOMAIN_NAME",
+	SNTP_SERVER_LIST:                            "SNTP Server List",
+	INFORMATION_REFRESH_TIME:                    "Information Refresh Time",
+	BCMCS_CONTROLLER_DOMAIN_NAME_LIST:           "BCMCS Controller Domain Name List",
+	BCMCS_CONTROLLER_IPV6_ADDRESS_LIST:          "BCMCS Controller IPv6 Address List",
+	OPTION_GEOCONF_CIVIC:                        "OPTION_GEOCONF_CIVIC",
+	OPTION_REMOTE_ID:                            "OPTION_REMOTE_ID",
+	RELAY_AGENT_SUBSCRIBER_ID:                   "Relay-Agent Subscriber ID",
+	FQDN:                                        "FQDN",
+	PANA_AUTHENTICATION_AGENT:                   "PANA Authentication Agent",
+	OPTION_NEW_POSIX_TIMEZONE:                   "OPTION_NEW_POSIX_TIME_ZONE",
+	OPTION_NEW_TZDB_TIMEZONE:                    "OPTION_NEW_TZDB_TIMEZONE",
+	ECHO_REQUEST:                                "Echo Request",
+	OPTION_LQ_QUERY:                             "OPTION_LQ_QUERY",
+	OPTION_CLIENT_DATA:                          "OPTION_CLIENT_DATA",
+	OPTION_CLT_TIME:                             "OPTION_CLT_TIME",
+	OPTION_LQ_RELAY_DATA:                        "OPTION_LQ_RELAY_DATA",
+	OPTION_LQ_CLIENT_LINK:                       "OPTION_LQ_CLIENT_LINK",
+	MIPV6_HOME_NETWORK_ID_FQDN:                  "MIPv6 Home Network ID FQDN",
+	MIPV6_VISITED_HOME_NETWORK_INFORMATION:      "MIPv6 Visited Home Network Information",
+	LOST_SERVER:                                 "LoST Server",
+	CAPWAP_ACCESS_CONTROLLER_ADDRESSES:          "CAPWAP Access Controller Addresses",
 	RELAY_ID:                                    "RELAY_ID",
 	OPTION_IPV6_ADDRESS_MOS:                     "OPTION-IPv6_Address-MoS",
 	OPTION_IPV6_FQDN_MOS:                        "OPTION-IPv6-FQDN-MoS",
@@ -149,4 +227,135 @@ var OptionCodeToString = map[OptionCode]string{
 	MIPV6_HOME_NETWORK_PREFIX:                   "MIPv6 Home Network Prefix",
 	MIPV6_HOME_AGENT_ADDRESS:                    "MIPv6 Home Agent Address",
 	MIPV6_HOME_AGENT_FQDN:                       "MIPv6 Home Agent FQDN",
+	OPTION_RDNSS_SELECTION:                      "OPTION_RDNSS_SELECTION",
+	OPTION_KRB_PRINCIPAL_NAME:                   "OPTION_KRB_PRINCIPAL_NAME",
+	OPTION_KRB_REALM_NAME:                       "OPTION_KRB_REALM_NAME",
+	OPTION_KRB_DEFAULT_REALM_NAME:               "OPTION_KRB_DEFAULT_REALM_NAME",
+	OPTION_KRB_KDC:                              "OPTION_KRB_KDC",
+	OPTION_CLIENT_LINKLAYER_ADDR:                "OPTION_CLIENT_LINKLAYER_ADDR",
+	OPTION_LINK_ADDRESS:                         "OPTION_LINK_ADDRESS",
+	OPTION_RADIUS:                               "OPTION_RADIUS",
+	OPTION_SOL_MAX_RT:                           "OPTION_SOL_MAX_RT",
+	OPTION_INF_MAX_RT:                           "OPTION_INF_MAX_RT",
+	OPTION_ADDRSEL:                              "OPTION_ADDRSEL",
+	OPTION_ADDRSEL_TABLE:                        "OPTION_ADDRSEL_TABLE",
+	OPTION_V6_PCP_SERVER:                        "OPTION_V6_PCP_SERVER",
+	OPTION_DHCPV4_MSG:                           "OPTION_DHCPV4_MSG",
+	OPTION_DHCP4_O_DHCP6_SERVER:                 "OPTION_DHCP4_O_DHCP6_SERVER",
+	OPTION_S46_RULE:                             "OPTION_S46_RULE",
+	OPTION_S46_BR:                               "OPTION_S46_BR",
+	OPTION_S46_DMR:                              "OPTION_S46_DMR",
+	OPTION_S46_V4V6BIND:                         "OPTION_S46_V4V6BIND",
+	OPTION_S46_PORTPARAMS:                       "OPTION_S46_PORTPARAMS",
+	OPTION_S46_CONT_MAPE:                        "OPTION_S46_CONT_MAPE",
+	OPTION_S46_CONT_MAPT:                        "OPTION_S46_CONT_MAPT",
+	OPTION_S46_CONT_LW:                          "OPTION_S46_CONT_LW",
+	OPTION_4RD:                                  "OPTION_4RD",
+	OPTION_4RD_MAP_RULE:                         "OPTION_4RD_MAP_RULE",
+	OPTION_4RD_NON_MAP_RULE:                     "OPTION_4RD_NON_MAP_RULE",
+	OPTION_LQ_BASE_TIME:                         "OPTION_LQ_BASE_TIME",
+	OPTION_LQ_START_TIME:                        "OPTION_LQ_START_TIME",
+	OPTION_LQ_END_TIME:                          "OPTION_LQ_END_TIME",
+	OPTION_CAPTIVE_PORTAL:                       "OPTION_CAPTIVE_PORTAL",
+	OPTION_MPL_PARAMETERS:                       "OPTION_MPL_PARAMETERS",
+	OPTION_ANI_ATT:                              "OPTION_ANI_ATT",
+	OPTION_ANI_NETWORK_NAME:                     "OPTION_ANI_NETWORK_NAME",
+	OPTION_ANI_AP_NAME:                          "OPTION_ANI_AP_NAME",
+	OPTION_ANI_AP_BSSID:                         "OPTION_ANI_AP_BSSID",
+	OPTION_ANI_OPERATOR_ID:                      "OPTION_ANI_OPERATOR_ID",
+	OPTION_ANI_OPERATOR_REALM:                   "OPTION_ANI_OPERATOR_REALM",
+	OPTION_S46_PRIORITY:                         "OPTION_S46_PRIORITY",
+	OPTION_MUD_URL_V6:                           "OPTION_MUD_URL_V6",
+	OPTION_V6_PREFIX64:                          "OPTION_V6_PREFIX64",
+	OPTION_F_BINDING_STATUS:                     "OPTION_F_BINDING_STATUS",
+	OPTION_F_CONNECT_FLAGS:                      "OPTION_F_CONNECT_FLAGS",
+	OPTION_F_DNS_REMOVAL_INFO:                   "OPTION_F_DNS_REMOVAL_INFO",
+	OPTION_F_DNS_HOST_NAME:                      "OPTION_F_DNS_HOST_NAME",
+	OPTION_F_DNS_ZONE_NAME:                      "OPTION_F_DNS_ZONE_NAME",
+	OPTION_F_DNS_FLAGS:                          "OPTION_F_DNS_FLAGS",
+	OPTION_F_EXPIRATION_TIME:                    "OPTION_F_EXPIRATION_TIME",
+	OPTION_F_MAX_UNACKED_BNDUPD:                 "OPTION_F_MAX_UNACKED_BNDUPD",
+	OPTION_F_MCLT:                               "OPTION_F_MCLT",
+	OPTION_F_PARTNER_LIFETIME:                   "OPTION_F_PARTNER_LIFETIME",
+	OPTION_F_PARTNER_LIFETIME_SENT:              "OPTION_F_PARTNER_LIFETIME_SENT",
+	OPTION_F_PARTNER_DOWN_TIME:                  "OPTION_F_PARTNER_DOWN_TIME",
+	OPTION_F_PARTNER_RAW_CLT_TIME:               "OPTION_F_PARTNER_RAW_CLT_TIME",
+	OPTION_F_PROTOCOL_VERSION:                   "OPTION_F_PROTOCOL_VERSION",
+	OPTION_F_KEEPALIVE_TIME:                     "OPTION_F_KEEPALIVE_TIME",
+	OPTION_F_RECONFIGURE_DATA:                   "OPTION_F_RECONFIGURE_DATA",
+	OPTION_F_RELATIONSHIP_NAME:                  "OPTION_F_RELATIONSHIP_NAME",
+	OPTION_F_SERVER_FLAGS:                       "OPTION_F_SERVER_FLAGS",
+	OPTION_F_SERVER_STATE:                       "OPTION_F_SERVER_STATE",
+	OPTION_F_START_TIME_OF_STATE:                "OPTION_F_START_TIME_OF_STATE",
+	OPTION_F_STATE_EXPIRATION_TIME:              "OPTION_F_STATE_EXPIRATION_TIME",
+	OPTION_RELAY_PORT:                           "OPTION_RELAY_PORT",
+	OPTION_V6_SZTP_REDIRECT:                     "OPTION_V6_SZTP_REDIRECT",
+	OPTION_S46_BIND_IPV6_PREFIX:                 "OPTION_S46_BIND_IPV6_PREFIX",
+	OPTION_IA_LL:                                "OPTION_IA_LL",
+	OPTION_LLADDR:                               "OPTION_LLADDR",
+	OPTION_SLAP_QUAD:                            "OPTION_SLAP_QUAD",
+	OPTION_V6_DOTS_RI:                           "OPTION_V6_DOTS_RI",
+	OPTION_V6_DOTS_ADDRESS:                      "OPTION_V6_DOTS_ADDRESS",
+	OPTION_IPV6_ADDRESS_ANDSF:                   "OPTION-IPv6_Address-ANDSF",
+}
+
+var (
+	optionCodeMu sync.RWMutex
+	// optionCodeToString holds the built-in names of OptionCodeToString and
+	// the ones registered with RegisterOptionCode
+	optionCodeToString = make(map[OptionCode]string, len(OptionCodeToString))
+	// stringToOptionCode is the reverse of optionCodeToString
+	stringToOptionCode = make(map[string]OptionCode, len(OptionCodeToString))
+)
+
+func init() {
+	for code, name := range OptionCodeToString {
+		optionCodeToString[code] = name
+		stringToOptionCode[name] = code
+	}
+}
+
+// RegisterOptionCode registers the name of an option code, e.g. a vendor or
+// experimental one, replacing any name previously registered for it. It is
+// safe to call concurrently with lookups.
+func RegisterOptionCode(code OptionCode, name string) {
+	optionCodeMu.Lock()
+	defer optionCodeMu.Unlock()
+	if old, ok := optionCodeToString[code]; ok && stringToOptionCode[old] == code {
+		delete(stringToOptionCode, old)
+	}
+	optionCodeToString[code] = name
+	stringToOptionCode[name] = code
+}
+
+// unregisterOptionCode removes the name registered for an option code. It is
+// used by the tests to undo RegisterOptionCode.
+func unregisterOptionCode(code OptionCode) {
+	optionCodeMu.Lock()
+	defer optionCodeMu.Unlock()
+	if name, ok := optionCodeToString[code]; ok {
+		if stringToOptionCode[name] == code {
+			delete(stringToOptionCode, name)
+		}
+		delete(optionCodeToString, code)
+	}
+}
+
+// optionCodeName returns the registered name of an option code
+func optionCodeName(code OptionCode) (string, bool) {
+	optionCodeMu.RLock()
+	defer optionCodeMu.RUnlock()
+	name, ok := optionCodeToString[code]
+	return name, ok
+}
+
+// StringToOptionCode returns the option code registered with the given name
+func StringToOptionCode(name string) (OptionCode, error) {
+	optionCodeMu.RLock()
+	defer optionCodeMu.RUnlock()
+	code, ok := stringToOptionCode[name]
+	if !ok {
+		return 0, fmt.Errorf("Unknown option name %q", name)
+	}
+	return code, nil
 }
@@ -0,0 +1,80 @@
+package dhcpv6
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringToOptionCode(t *testing.T) {
+	code, err := StringToOptionCode("OPTION_CLIENTID")
+	require.NoError(t, err)
+	require.Equal(t, OPTION_CLIENTID, code)
+
+	_, err = StringToOptionCode("OPTION_DOES_NOT_EXIST")
+	require.Error(t, err)
+}
+
+func TestRegisterOptionCode(t *testing.T) {
+	code := OptionCode(65001)
+	t.Cleanup(func() { unregisterOptionCode(code) })
+	RegisterOptionCode(code, "OPTION_TEST_EXPERIMENTAL")
+	got, err := StringToOptionCode("OPTION_TEST_EXPERIMENTAL")
+	require.NoError(t, err)
+	require.Equal(t, code, got)
+
+	opt := OptionGeneric{OptionCode: code, OptionData: []byte{1}}
+	require.Equal(t, "OPTION_TEST_EXPERIMENTAL -> [1]", opt.String())
+	// the built-in names are left alone
+	_, ok := OptionCodeToString[code]
+	require.False(t, ok)
+
+	// registering a new name replaces the old one
+	RegisterOptionCode(code, "OPTION_TEST_RENAMED")
+	_, err = StringToOptionCode("OPTION_TEST_EXPERIMENTAL")
+	require.Error(t, err)
+	require.Equal(t, "OPTION_TEST_RENAMED -> [1]", opt.String())
+
+	unregisterOptionCode(code)
+	_, err = StringToOptionCode("OPTION_TEST_RENAMED")
+	require.Error(t, err)
+	require.Equal(t, "UnknownOption -> [1]", opt.String())
+}
+
+func TestRegisterOptionCodeConcurrent(t *testing.T) {
+	t.Cleanup(func() {
+		for i := 0; i < 10; i++ {
+			unregisterOptionCode(OptionCode(65100 + i))
+		}
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterOptionCode(OptionCode(65100+i), "OPTION_TEST_CONCURRENT")
+		}(i)
+		go func() {
+			defer wg.Done()
+			StringToOptionCode("OPTION_TEST_CONCURRENT")
+		}()
+	}
+	wg.Wait()
+	_, err := StringToOptionCode("OPTION_TEST_CONCURRENT")
+	require.NoError(t, err)
+}
+
+func TestOptionCodeToStringComplete(t *testing.T) {
+	for code := OptionCode(1); code <= OPTION_IPV6_ADDRESS_ANDSF; code++ {
+		if code == 10 || code == 35 {
+			// unassigned
+			continue
+		}
+		name, ok := optionCodeName(code)
+		require.True(t, ok, "no name for option code %d", code)
+		got, err := StringToOptionCode(name)
+		require.NoError(t, err)
+		require.Equal(t, code, got)
+	}
+}
@@ -4,8 +4,8 @@ package dhcpv6
 // https://www.ietf.org/rfc/rfc3315.txt
 
 import (
+	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"strings"
 )
@@ -22,13 +22,11 @@ func (op *OptUserClass) Code() OptionCode {
 
 // ToBytes serializes the option and returns it as a sequence of bytes
 func (op *OptUserClass) ToBytes() []byte {
-	buf := make([]byte, 4)
-	binary.BigEndian.PutUint16(buf[0:2], uint16(OPTION_USER_CLASS))
-	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
-	u16 := make([]byte, 2)
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_USER_CLASS))
+	buf = appendUint16(buf, uint16(op.Length()))
 	for _, uc := range op.UserClasses {
-		binary.BigEndian.PutUint16(u16, uint16(len(uc)))
-		buf = append(buf, u16...)
+		buf = appendUint16(buf, uint16(len(uc)))
 		buf = append(buf, uc...)
 	}
 	return buf
@@ -44,11 +42,32 @@ func (op *OptUserClass) Length() int {
 }
 
 func (op *OptUserClass) String() string {
-	ucStrings := make([]string, 0)
+	return fmt.Sprintf("OptUserClass{userclass=[%s]}", strings.Join(op.EntriesAsStrings(), ", "))
+}
+
+// Contains returns true if one of the user classes is equal to class. The
+// comparison is byte by byte, and user classes are not required to be text.
+func (op *OptUserClass) Contains(class []byte) bool {
+	for _, uc := range op.UserClasses {
+		if bytes.Equal(uc, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntriesAsStrings returns the user classes converted to strings
+func (op *OptUserClass) EntriesAsStrings() []string {
+	ucStrings := make([]string, 0, len(op.UserClasses))
 	for _, uc := range op.UserClasses {
 		ucStrings = append(ucStrings, string(uc))
 	}
-	return fmt.Sprintf("OptUserClass{userclass=[%s]}", strings.Join(ucStrings, ", "))
+	return ucStrings
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptUserClass) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"user_classes": op.UserClasses})
 }
 
 // ParseOptUserClass builds an OptUserClass structure from a sequence of
@@ -60,17 +79,17 @@ func ParseOptUserClass(data []byte) (*OptUserClass, error) {
 			break
 		}
 		if len(data) < 2 {
-			return nil, errors.New("ParseOptUserClass: short data: missing length field")
+			return nil, errOptionTooShort("ParseOptUserClass: short data: missing length field")
 		}
 		ucLen := int(binary.BigEndian.Uint16(data[:2]))
 		if len(data) < ucLen+2 {
-			return nil, fmt.Errorf("ParseOptUserClass: short data: less than %d bytes", ucLen+2)
+			return nil, errOptionTooShort("ParseOptUserClass: short data: less than %d bytes", ucLen+2)
 		}
 		opt.UserClasses = append(opt.UserClasses, data[2:ucLen+2])
 		data = data[2+ucLen:]
 	}
 	if len(opt.UserClasses) < 1 {
-		return nil, errors.New("ParseOptUserClass: at least one user class is required")
+		return nil, errOptionTooShort("ParseOptUserClass: at least one user class is required")
 	}
 	return &opt, nil
 }
@@ -63,3 +63,46 @@ func TestOptUserClassToBytesMultiple(t *testing.T) {
 	}
 	require.Equal(t, expected, data)
 }
+
+func TestOptUserClassRoundTripMultiple(t *testing.T) {
+	data := []byte{
+		0, 3, 'a', 'b', 'c',
+		0, 9, 'l', 'i', 'n', 'u', 'x', 'b', 'o', 'o', 't',
+		0, 1, 'x',
+	}
+	opt, err := ParseOptUserClass(data)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("abc"), []byte("linuxboot"), []byte("x")}, opt.UserClasses)
+	require.Equal(t, len(data), opt.Length())
+	expected := append([]byte{0, 15, 0, byte(len(data))}, data...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptUserClassOverrun(t *testing.T) {
+	data := []byte{
+		0, 3, 'a', 'b', 'c',
+		0, 9, 'l', 'i', 'n', 'u', 'x', // declared length overruns the buffer
+	}
+	_, err := ParseOptUserClass(data)
+	require.Error(t, err)
+}
+
+func TestOptUserClassContains(t *testing.T) {
+	data := []byte{
+		0, 9, 'l', 'i', 'n', 'u', 'x', 'b', 'o', 'o', 't',
+		0, 3, 0xff, 0x00, 0xfe,
+	}
+	opt, err := ParseOptUserClass(data)
+	require.NoError(t, err)
+	require.True(t, opt.Contains([]byte("linuxboot")))
+	require.True(t, opt.Contains([]byte{0xff, 0x00, 0xfe}))
+	// the comparison is byte-exact
+	require.False(t, opt.Contains([]byte("LinuxBoot")))
+	require.False(t, opt.Contains([]byte("linux")))
+	require.False(t, opt.Contains([]byte{0xff, 0x00}))
+	require.False(t, opt.Contains([]byte("\xef\xbf\xbd\x00\xef\xbf\xbd")))
+	require.False(t, opt.Contains(nil))
+
+	require.Equal(t, []string{"linuxboot", "\xff\x00\xfe"}, opt.EntriesAsStrings())
+	require.Equal(t, []string{}, (&OptUserClass{}).EntriesAsStrings())
+}
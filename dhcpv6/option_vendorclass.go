@@ -0,0 +1,106 @@
+package dhcpv6
+
+// This module defines the OptVendorClass structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// OptVendorClass represents a DHCPv6 Vendor Class option
+type OptVendorClass struct {
+	EnterpriseNumber uint32
+	Data             [][]byte
+}
+
+// Code returns the option code
+func (op *OptVendorClass) Code() OptionCode {
+	return OPTION_VENDOR_CLASS
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptVendorClass) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_VENDOR_CLASS))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, op.EnterpriseNumber)
+	for _, data := range op.Data {
+		buf = appendUint16(buf, uint16(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptVendorClass) Length() int {
+	ret := 4
+	for _, data := range op.Data {
+		ret += 2 + len(data)
+	}
+	return ret
+}
+
+func (op *OptVendorClass) String() string {
+	return fmt.Sprintf("OptVendorClass{enterprisenum=%d, data=[%s]}", op.EnterpriseNumber, strings.Join(op.EntriesAsStrings(), ", "))
+}
+
+// HasEnterprise returns true if the option was sent by a vendor with the
+// given enterprise number
+func (op *OptVendorClass) HasEnterprise(num uint32) bool {
+	return op.EnterpriseNumber == num
+}
+
+// Contains returns true if one of the vendor class data entries is equal to
+// class. The comparison is byte by byte, and entries are not required to be
+// text.
+func (op *OptVendorClass) Contains(class []byte) bool {
+	for _, data := range op.Data {
+		if bytes.Equal(data, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntriesAsStrings returns the vendor class data entries converted to strings
+func (op *OptVendorClass) EntriesAsStrings() []string {
+	vcStrings := make([]string, 0, len(op.Data))
+	for _, data := range op.Data {
+		vcStrings = append(vcStrings, string(data))
+	}
+	return vcStrings
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptVendorClass) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"enterprise_number": op.EnterpriseNumber, "data": op.Data})
+}
+
+// ParseOptVendorClass builds an OptVendorClass structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptVendorClass(data []byte) (*OptVendorClass, error) {
+	opt := OptVendorClass{}
+	if len(data) < 4 {
+		return nil, errOptionTooShort("Invalid vendor class data length. Expected at least 4 bytes, got %v", len(data))
+	}
+	opt.EnterpriseNumber = binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	for {
+		if len(data) == 0 {
+			break
+		}
+		if len(data) < 2 {
+			return nil, errOptionTooShort("ParseOptVendorClass: short data: missing length field")
+		}
+		vcLen := int(binary.BigEndian.Uint16(data[:2]))
+		if len(data) < vcLen+2 {
+			return nil, errOptionTooShort("ParseOptVendorClass: short data: less than %d bytes", vcLen+2)
+		}
+		opt.Data = append(opt.Data, data[2:vcLen+2])
+		data = data[2+vcLen:]
+	}
+	return &opt, nil
+}
@@ -0,0 +1,82 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptVendorClass(t *testing.T) {
+	data := []byte{
+		0xaa, 0xbb, 0xcc, 0xdd, // EnterpriseNumber
+		0, 10, 'H', 'T', 'T', 'P', 'C', 'l', 'i', 'e', 'n', 't',
+		0, 4, 't', 'e', 's', 't',
+	}
+	opt, err := ParseOptVendorClass(data)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xaabbccdd), opt.EnterpriseNumber)
+	require.Equal(t, 2, len(opt.Data))
+	require.Equal(t, []byte("HTTPClient"), opt.Data[0])
+	require.Equal(t, []byte("test"), opt.Data[1])
+}
+
+func TestParseOptVendorClassNoData(t *testing.T) {
+	opt, err := ParseOptVendorClass([]byte{0xaa, 0xbb, 0xcc, 0xdd})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xaabbccdd), opt.EnterpriseNumber)
+	require.Empty(t, opt.Data)
+	require.Equal(t, 4, opt.Length())
+}
+
+func TestParseOptVendorClassShortData(t *testing.T) {
+	_, err := ParseOptVendorClass([]byte{0xaa, 0xbb, 0xcc})
+	require.Error(t, err)
+
+	// declared data length runs past the buffer
+	_, err = ParseOptVendorClass([]byte{0xaa, 0xbb, 0xcc, 0xdd, 0, 10, 'a'})
+	require.Error(t, err)
+
+	// truncated length field
+	_, err = ParseOptVendorClass([]byte{0xaa, 0xbb, 0xcc, 0xdd, 0})
+	require.Error(t, err)
+}
+
+func TestOptVendorClassToBytes(t *testing.T) {
+	opt := OptVendorClass{
+		EnterpriseNumber: 0xaabbccdd,
+		Data: [][]byte{
+			[]byte("HTTPClient"),
+			[]byte("test"),
+		},
+	}
+	data := opt.ToBytes()
+	expected := []byte{
+		0, 16, // OPTION_VENDOR_CLASS
+		0, 22, // length
+		0xaa, 0xbb, 0xcc, 0xdd, // EnterpriseNumber
+		0, 10, 'H', 'T', 'T', 'P', 'C', 'l', 'i', 'e', 'n', 't',
+		0, 4, 't', 'e', 's', 't',
+	}
+	require.Equal(t, expected, data)
+	require.Equal(t, 22, opt.Length())
+}
+
+func TestOptVendorClassPredicates(t *testing.T) {
+	data := []byte{
+		0xaa, 0xbb, 0xcc, 0xdd, // EnterpriseNumber
+		0, 10, 'H', 'T', 'T', 'P', 'C', 'l', 'i', 'e', 'n', 't',
+		0, 2, 0x80, 0x00,
+	}
+	opt, err := ParseOptVendorClass(data)
+	require.NoError(t, err)
+	require.True(t, opt.HasEnterprise(0xaabbccdd))
+	require.False(t, opt.HasEnterprise(0xddccbbaa))
+	require.True(t, opt.Contains([]byte("HTTPClient")))
+	require.True(t, opt.Contains([]byte{0x80, 0x00}))
+	// the comparison is byte-exact
+	require.False(t, opt.Contains([]byte("httpclient")))
+	require.False(t, opt.Contains([]byte{0x80}))
+	require.False(t, opt.Contains([]byte("\xef\xbf\xbd\x00")))
+
+	require.Equal(t, []string{"HTTPClient", "\x80\x00"}, opt.EntriesAsStrings())
+}
@@ -0,0 +1,89 @@
+package dhcpv6
+
+// This module defines the OptVendorOpts structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptVendorOpts represents a DHCPv6 Vendor-specific Information option
+type OptVendorOpts struct {
+	EnterpriseNumber uint32
+	VendorOpts       []Option
+}
+
+// Code returns the option code
+func (op *OptVendorOpts) Code() OptionCode {
+	return OPTION_VENDOR_OPTS
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptVendorOpts) ToBytes() []byte {
+	buf := make([]byte, 0, 4+op.Length())
+	buf = appendUint16(buf, uint16(OPTION_VENDOR_OPTS))
+	buf = appendUint16(buf, uint16(op.Length()))
+	buf = appendUint32(buf, op.EnterpriseNumber)
+	for _, opt := range op.VendorOpts {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptVendorOpts) Length() int {
+	l := 4
+	for _, opt := range op.VendorOpts {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+func (op *OptVendorOpts) String() string {
+	return fmt.Sprintf("OptVendorOpts{enterprisenum=%v, vendorOpts=%v}", op.EnterpriseNumber, op.VendorOpts)
+}
+
+// MarshalJSON returns the JSON representation of the option
+func (op *OptVendorOpts) MarshalJSON() ([]byte, error) {
+	return marshalOptionJSON(op, map[string]interface{}{"enterprise_number": op.EnterpriseNumber, "options": jsonOptions(op.VendorOpts)})
+}
+
+// ParseOptVendorOpts builds an OptVendorOpts structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptVendorOpts(data []byte) (*OptVendorOpts, error) {
+	opt := OptVendorOpts{}
+	if len(data) < 4 {
+		return nil, errOptionTooShort("Invalid vendor opts data length. Expected at least 4 bytes, got %v", len(data))
+	}
+	opt.EnterpriseNumber = binary.BigEndian.Uint32(data[:4])
+	var err error
+	opt.VendorOpts, err = vendorOptsFromBytes(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
+
+// vendorOptsFromBytes parses the sub-options of an OptVendorOpts. The
+// sub-option codes are defined by each vendor, hence they always parse as
+// OptionGeneric instead of going through ParseOption, which would interpret
+// them as global DHCPv6 option codes.
+func vendorOptsFromBytes(data []byte) ([]Option, error) {
+	options := make([]Option, 0)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errOptionTooShort("Invalid vendor sub-option: less than 4 bytes")
+		}
+		code := OptionCode(binary.BigEndian.Uint16(data[:2]))
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if len(data) < length+4 {
+			return nil, errOptionTooShort("Invalid option length for vendor sub-option %v. Declared %v, actual %v",
+				code, length, len(data)-4,
+			)
+		}
+		options = append(options, &OptionGeneric{OptionCode: code, OptionData: data[4 : 4+length]})
+		data = data[4+length:]
+	}
+	return options, nil
+}
@@ -0,0 +1,52 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptVendorOpts(t *testing.T) {
+	data := []byte{
+		0xaa, 0xbb, 0xcc, 0xdd, // EnterpriseNumber
+		0, 1, 0, 4, 't', 'e', 's', 't', // sub-option 1, looks like a client ID
+		0, 2, 0, 0, // empty sub-option 2
+	}
+	opt, err := ParseOptVendorOpts(data)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xaabbccdd), opt.EnterpriseNumber)
+	require.Equal(t, 2, len(opt.VendorOpts))
+	sub, ok := opt.VendorOpts[0].(*OptionGeneric)
+	require.True(t, ok, "vendor sub-options must be parsed as OptionGeneric")
+	require.Equal(t, OptionCode(1), sub.Code())
+	require.Equal(t, []byte("test"), sub.OptionData)
+	require.Equal(t, OptionCode(2), opt.VendorOpts[1].Code())
+	require.Equal(t, len(data), opt.Length())
+}
+
+func TestParseOptVendorOptsShortData(t *testing.T) {
+	_, err := ParseOptVendorOpts([]byte{0xaa, 0xbb, 0xcc})
+	require.Error(t, err)
+
+	_, err = ParseOptVendorOpts([]byte{0xaa, 0xbb, 0xcc, 0xdd, 0, 1, 0})
+	require.Error(t, err)
+
+	_, err = ParseOptVendorOpts([]byte{0xaa, 0xbb, 0xcc, 0xdd, 0, 1, 0, 4, 't'})
+	require.Error(t, err)
+}
+
+func TestOptVendorOptsToBytes(t *testing.T) {
+	opt := OptVendorOpts{
+		EnterpriseNumber: 0xaabbccdd,
+		VendorOpts: []Option{
+			&OptionGeneric{OptionCode: 1, OptionData: []byte("test")},
+		},
+	}
+	expected := []byte{
+		0, 17, // OPTION_VENDOR_OPTS
+		0, 12, // length
+		0xaa, 0xbb, 0xcc, 0xdd, // EnterpriseNumber
+		0, 1, 0, 4, 't', 'e', 's', 't',
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
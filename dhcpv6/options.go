@@ -1,8 +1,12 @@
 package dhcpv6
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"reflect"
+	"sort"
+	"sync"
 )
 
 // OptionCode is a single byte representing the code for a given Option.
@@ -16,6 +20,318 @@ type Option interface {
 	String() string
 }
 
+// OptionCloner is implemented by the options that can copy themselves without
+// going through their serialized form, see CloneOption
+type OptionCloner interface {
+	Clone() Option
+}
+
+// OptionValidator is implemented by the options that can hold values they
+// cannot serialize faithfully, or that break a constraint of the RFCs, e.g.
+// an IPv4 or nil address in an IPv6 address field. Parsing does not enforce
+// these checks, while DHCPv6Message.Validate, MarshalBinary and the Client do,
+// see ValidOption.
+type OptionValidator interface {
+	Valid() error
+}
+
+// OptionEqualer is implemented by the options that can compare themselves to
+// an option of the same type without serializing it, see EqualOptions
+type OptionEqualer interface {
+	Equal(other Option) bool
+}
+
+// Options is a collection of options.
+type Options []Option
+
+// Get returns all the options with the given code, or nil if none is found.
+func (o Options) Get(code OptionCode) []Option {
+	var ret []Option
+	for _, opt := range o {
+		if opt.Code() == code {
+			ret = append(ret, opt)
+		}
+	}
+	return ret
+}
+
+// ForEach calls fn for each option with the given code, in order, until fn
+// returns false. Unlike Get, it does not allocate.
+func (o Options) ForEach(code OptionCode, fn func(Option) bool) {
+	for _, opt := range o {
+		if opt.Code() == code && !fn(opt) {
+			return
+		}
+	}
+}
+
+// GetOne returns the first option with the given code, or nil if none is
+// found.
+func (o Options) GetOne(code OptionCode) Option {
+	for _, opt := range o {
+		if opt.Code() == code {
+			return opt
+		}
+	}
+	return nil
+}
+
+// Add appends an option to the collection.
+func (o *Options) Add(option Option) {
+	*o = append(*o, option)
+}
+
+// Update replaces the first option of the same type as the specified one, or
+// appends it if no such option exists.
+func (o *Options) Update(option Option) {
+	for idx, opt := range *o {
+		if opt.Code() == option.Code() {
+			(*o)[idx] = option
+			// don't look further
+			return
+		}
+	}
+	// if not found, add it
+	o.Add(option)
+}
+
+// Status returns the first status code option, or nil if there is none
+func (o Options) Status() *OptStatusCode {
+	for _, opt := range o.Get(OPTION_STATUS_CODE) {
+		if sc, ok := opt.(*OptStatusCode); ok {
+			return sc
+		}
+	}
+	return nil
+}
+
+// ClientID returns the DUID of the first client ID option, or nil if there is
+// none
+func (o Options) ClientID() *Duid {
+	for _, opt := range o.Get(OPTION_CLIENTID) {
+		if cid, ok := opt.(*OptClientId); ok {
+			return &cid.Cid
+		}
+	}
+	return nil
+}
+
+// ServerID returns the DUID of the first server ID option, or nil if there is
+// none
+func (o Options) ServerID() *Duid {
+	for _, opt := range o.Get(OPTION_SERVERID) {
+		if sid, ok := opt.(*OptServerId); ok {
+			return &sid.Sid
+		}
+	}
+	return nil
+}
+
+// HasRapidCommit returns true if a rapid commit option is present.
+func (o Options) HasRapidCommit() bool {
+	return o.GetOne(OPTION_RAPID_COMMIT) != nil
+}
+
+// Length returns the length of the serialized options, including the code
+// and length bytes of each option.
+func (o Options) Length() int {
+	length := 0
+	for _, opt := range o {
+		length += 4 + opt.Length()
+	}
+	return length
+}
+
+// ToBytes serializes the options in insertion order. This is the order used
+// by the message serializers, so that parsing and reserializing a message
+// does not reorder its options.
+func (o Options) ToBytes() []byte {
+	s := NewSerializer(o.Length())
+	s.WriteOptions(o)
+	return s.Bytes()
+}
+
+// ToBytesSorted serializes the options in ascending option code order, with
+// options of the same code kept in insertion order. The output does not
+// depend on the order the options were added in, which makes it suitable for
+// signing or comparing option sets. Options nested in other options are not
+// reordered.
+func (o Options) ToBytesSorted() []byte {
+	sorted := make(Options, len(o))
+	copy(sorted, o)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Code() < sorted[j].Code() })
+	return sorted.ToBytes()
+}
+
+// Clone returns a deep copy of the options, which can be modified without
+// affecting the original ones, e.g. to customize a template per response.
+// See CloneOption.
+func (o Options) Clone() Options {
+	if o == nil {
+		return nil
+	}
+	clone := make(Options, 0, len(o))
+	for _, opt := range o {
+		clone = append(clone, CloneOption(opt))
+	}
+	return clone
+}
+
+// CloneOption returns a deep copy of an option. Options implementing
+// OptionCloner copy themselves; the others are copied by serializing and
+// parsing them again, and an option that does not parse back is copied as an
+// OptionGeneric.
+func CloneOption(opt Option) Option {
+	if c, ok := opt.(OptionCloner); ok {
+		return c.Clone()
+	}
+	// ToBytes returns a new buffer, which the parsed option can alias
+	data := opt.ToBytes()
+	clone, err := ParseOption(data)
+	if err != nil {
+		return &OptionGeneric{OptionCode: opt.Code(), OptionData: data[4:]}
+	}
+	return clone
+}
+
+// EqualOptions returns true if the two options are equal. Options of the same
+// type implementing OptionEqualer are compared with their Equal method, the
+// others by their serialized form, so that e.g. an OptionGeneric equals the
+// typed option with the same bytes.
+func EqualOptions(a, b Option) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Code() != b.Code() {
+		return false
+	}
+	if e, ok := a.(OptionEqualer); ok && reflect.TypeOf(a) == reflect.TypeOf(b) {
+		return e.Equal(b)
+	}
+	return bytes.Equal(a.ToBytes(), b.ToBytes())
+}
+
+// Equal returns true if the two lists hold equal options in the same order,
+// see EqualOptions
+func (o Options) Equal(other Options) bool {
+	if len(o) != len(other) {
+		return false
+	}
+	for i := range o {
+		if !EqualOptions(o[i], other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidOption calls the Valid method of opt if it implements
+// OptionValidator, and of the options nested in it, including those of the
+// messages carried by OPTION_RELAY_MSG and OPTION_LQ_RELAY_DATA. It returns the
+// first error found.
+func ValidOption(opt Option) error {
+	if v, ok := opt.(OptionValidator); ok {
+		if err := v.Valid(); err != nil {
+			return err
+		}
+	}
+	var nested Options
+	switch o := opt.(type) {
+	case *OptRelayMsg:
+		if o.RelayMessage() != nil {
+			nested = o.RelayMessage().Options()
+		}
+	case *OptIANA:
+		nested = o.Options
+	case *OptIAForPrefixDelegation:
+		nested = o.Options()
+	case *OptIAAddress:
+		nested = o.Options
+	case *OptIAPrefix:
+		nested = o.Options()
+	case *OptLQQuery:
+		nested = o.Options
+	case *OptLQRelayData:
+		if o.RelayMessage != nil {
+			nested = o.RelayMessage.Options()
+		}
+	case *OptClientData:
+		nested = o.Options
+	case *OptS46ContainerMAPE:
+		nested = o.Options
+	case *OptS46ContainerMAPT:
+		nested = o.Options
+	case *OptS46ContainerLW:
+		nested = o.Options
+	case *OptS46Rule:
+		nested = o.Options
+	}
+	return nested.Valid()
+}
+
+// Valid returns the first error returned by ValidOption for the options of
+// the list
+func (o Options) Valid() error {
+	for _, opt := range o {
+		if err := ValidOption(opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options and the messages holding them are not safe for concurrent use.
+// SyncOptions wraps Options with a lock, for options shared between
+// goroutines, such as a server's template for its replies. Options returned
+// by its methods are still shared, and must not be modified: use Snapshot to
+// get a private copy.
+type SyncOptions struct {
+	mu      sync.RWMutex
+	options Options
+}
+
+// NewSyncOptions returns a SyncOptions holding a copy of the given options
+func NewSyncOptions(options Options) *SyncOptions {
+	return &SyncOptions{options: options.Clone()}
+}
+
+// Get returns all the options with the given code, or nil if none is found.
+func (so *SyncOptions) Get(code OptionCode) []Option {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
+	return so.options.Get(code)
+}
+
+// GetOne returns the first option with the given code, or nil if none is
+// found.
+func (so *SyncOptions) GetOne(code OptionCode) Option {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
+	return so.options.GetOne(code)
+}
+
+// Add appends an option to the collection.
+func (so *SyncOptions) Add(option Option) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	so.options.Add(option)
+}
+
+// Update replaces the first option of the same type as the specified one, or
+// appends it if no such option exists.
+func (so *SyncOptions) Update(option Option) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	so.options.Update(option)
+}
+
+// Snapshot returns a deep copy of the options
+func (so *SyncOptions) Snapshot() Options {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
+	return so.options.Clone()
+}
+
 type OptionGeneric struct {
 	OptionCode OptionCode
 	OptionData []byte
@@ -26,19 +342,31 @@ func (og *OptionGeneric) Code() OptionCode {
 }
 
 func (og *OptionGeneric) ToBytes() []byte {
-	var ret []byte
-	codeBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(codeBytes, uint16(og.OptionCode))
-	ret = append(ret, codeBytes...)
-	lengthBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(lengthBytes, uint16(len(og.OptionData)))
-	ret = append(ret, lengthBytes...)
-	ret = append(ret, og.OptionData...)
-	return ret
+	buf := make([]byte, 0, 4+len(og.OptionData))
+	buf = appendUint16(buf, uint16(og.OptionCode))
+	buf = appendUint16(buf, uint16(len(og.OptionData)))
+	return append(buf, og.OptionData...)
+}
+
+// Clone returns a deep copy of the option
+func (og *OptionGeneric) Clone() Option {
+	return &OptionGeneric{OptionCode: og.OptionCode, OptionData: append([]byte(nil), og.OptionData...)}
+}
+
+// Equal returns true if other is an OptionGeneric with the same code and data
+func (og *OptionGeneric) Equal(other Option) bool {
+	o, ok := other.(*OptionGeneric)
+	return ok && og.OptionCode == o.OptionCode && bytes.Equal(og.OptionData, o.OptionData)
+}
+
+// SerializeTo writes the option to a Serializer
+func (og *OptionGeneric) SerializeTo(s *Serializer) {
+	s.WriteOptionHeader(og.OptionCode, len(og.OptionData))
+	s.WriteBytes(og.OptionData)
 }
 
 func (og *OptionGeneric) String() string {
-	code, ok := OptionCodeToString[og.OptionCode]
+	code, ok := optionCodeName(og.OptionCode)
 	if !ok {
 		code = "UnknownOption"
 	}
@@ -49,101 +377,244 @@ func (og *OptionGeneric) Length() int {
 	return len(og.OptionData)
 }
 
+// ParseOption parses a sequence of bytes as a single DHCPv6 option.
+// Returns the option structure, or an error if any.
+//
+// Like OptionsFromBytes, ParseOption does not copy the input data, use
+// ParseOptionCopy if the buffer is going to be reused.
 func ParseOption(dataStart []byte) (Option, error) {
-	// Parse a sequence of bytes as a single DHCPv6 option.
-	// Returns the option structure, or an error if any.
 	if len(dataStart) < 4 {
-		return nil, fmt.Errorf("Invalid DHCPv6 option: less than 4 bytes")
+		return nil, newOptionParseError(0, ErrOptionTooShort, "Invalid DHCPv6 option: less than 4 bytes")
 	}
 	code := OptionCode(binary.BigEndian.Uint16(dataStart[:2]))
 	length := int(binary.BigEndian.Uint16(dataStart[2:4]))
 	if len(dataStart) < length+4 {
-		return nil, fmt.Errorf("Invalid option length for option %v. Declared %v, actual %v",
+		return nil, newOptionParseError(code, ErrOptionTooShort, "Invalid option length for option %v. Declared %v, actual %v",
 			code, length, len(dataStart)-4,
 		)
 	}
+	return parseOption(code, dataStart[4:4+length], 0)
+}
+
+// ParseOptionCopy is like ParseOption, but the returned option does not alias
+// the input data, which can be safely modified or reused afterwards.
+func ParseOptionCopy(dataStart []byte) (Option, error) {
+	if len(dataStart) >= 4 {
+		length := int(binary.BigEndian.Uint16(dataStart[2:4]))
+		if len(dataStart) > length+4 {
+			dataStart = dataStart[:length+4]
+		}
+	}
+	return ParseOption(append([]byte(nil), dataStart...))
+}
+
+// OptionParser parses the data of an option, not including the option code
+// and length bytes
+type OptionParser func(data []byte) (Option, error)
+
+var (
+	optionParsersMu sync.RWMutex
+	optionParsers   = make(map[OptionCode]OptionParser)
+)
+
+// RegisterParser registers the parser for an option code, replacing any
+// parser previously registered for it, including the built-in ones. Options
+// with no registered parser are parsed as OptionGeneric. Passing a nil
+// parser unregisters the code.
+func RegisterParser(code OptionCode, fn OptionParser) {
+	optionParsersMu.Lock()
+	defer optionParsersMu.Unlock()
+	if fn == nil {
+		delete(optionParsers, code)
+		return
+	}
+	optionParsers[code] = fn
+}
+
+func init() {
+	RegisterParser(OPTION_CLIENTID, func(data []byte) (Option, error) { return ParseOptClientId(data) })
+	RegisterParser(OPTION_SERVERID, func(data []byte) (Option, error) { return ParseOptServerId(data) })
+	RegisterParser(OPTION_ELAPSED_TIME, func(data []byte) (Option, error) { return ParseOptElapsedTime(data) })
+	RegisterParser(OPTION_ORO, func(data []byte) (Option, error) { return ParseOptRequestedOption(data) })
+	RegisterParser(SIP_SERVERS_DOMAIN_NAME_LIST, func(data []byte) (Option, error) { return ParseOptSIPServersDomainNameList(data) })
+	RegisterParser(SIP_SERVERS_IPV6_ADDRESS_LIST, func(data []byte) (Option, error) { return ParseOptSIPServersAddressList(data) })
+	RegisterParser(SNTP_SERVER_LIST, func(data []byte) (Option, error) { return ParseOptSNTPServers(data) })
+	RegisterParser(DNS_RECURSIVE_NAME_SERVER, func(data []byte) (Option, error) { return ParseOptDNSRecursiveNameServer(data) })
+	RegisterParser(DOMAIN_SEARCH_LIST, func(data []byte) (Option, error) { return ParseOptDomainSearchList(data) })
+	RegisterParser(OPTION_IA_NA, func(data []byte) (Option, error) { return ParseOptIANA(data) })
+	RegisterParser(OPTION_IA_PD, func(data []byte) (Option, error) { return ParseOptIAForPrefixDelegation(data) })
+	RegisterParser(OPTION_NIS_SERVERS, func(data []byte) (Option, error) { return ParseOptNISServers(data) })
+	RegisterParser(OPTION_NISP_SERVERS, func(data []byte) (Option, error) { return ParseOptNISPServers(data) })
+	RegisterParser(OPTION_NIS_DOMAIN_NAME, func(data []byte) (Option, error) { return ParseOptNISDomainName(data) })
+	RegisterParser(OPTION_NISP_DOMAIN_NAME, func(data []byte) (Option, error) { return ParseOptNISPDomainName(data) })
+	RegisterParser(OPTION_IAADDR, func(data []byte) (Option, error) { return ParseOptIAAddress(data) })
+	RegisterParser(OPTION_IAPREFIX, func(data []byte) (Option, error) { return ParseOptIAPrefix(data) })
+	RegisterParser(OPTION_AUTH, func(data []byte) (Option, error) { return ParseOptAuthentication(data) })
+	RegisterParser(OPTION_STATUS_CODE, func(data []byte) (Option, error) { return ParseOptStatusCode(data) })
+	RegisterParser(OPTION_RELAY_MSG, func(data []byte) (Option, error) { return ParseOptRelayMsg(data) })
+	RegisterParser(PANA_AUTHENTICATION_AGENT, func(data []byte) (Option, error) { return ParseOptPanaAgent(data) })
+	RegisterParser(OPTION_NEW_POSIX_TIMEZONE, func(data []byte) (Option, error) { return ParseOptPosixTimezone(data) })
+	RegisterParser(OPTION_NEW_TZDB_TIMEZONE, func(data []byte) (Option, error) { return ParseOptTZDBTimezone(data) })
+	RegisterParser(ECHO_REQUEST, func(data []byte) (Option, error) { return ParseOptEchoRequest(data) })
+	RegisterParser(OPTION_LQ_QUERY, func(data []byte) (Option, error) { return ParseOptLQQuery(data) })
+	RegisterParser(OPTION_CLIENT_DATA, func(data []byte) (Option, error) { return ParseOptClientData(data) })
+	RegisterParser(OPTION_CLT_TIME, func(data []byte) (Option, error) { return ParseOptCLTTime(data) })
+	RegisterParser(OPTION_LQ_RELAY_DATA, func(data []byte) (Option, error) { return ParseOptLQRelayData(data) })
+	RegisterParser(OPTION_LQ_CLIENT_LINK, func(data []byte) (Option, error) { return ParseOptLQClientLink(data) })
+	RegisterParser(BCMCS_CONTROLLER_DOMAIN_NAME_LIST, func(data []byte) (Option, error) { return ParseOptBCMCSControllerDomainNameList(data) })
+	RegisterParser(BCMCS_CONTROLLER_IPV6_ADDRESS_LIST, func(data []byte) (Option, error) { return ParseOptBCMCSControllerIPv6AddressList(data) })
+	RegisterParser(OPTION_GEOCONF_CIVIC, func(data []byte) (Option, error) { return ParseOptGeoConfCivic(data) })
+	RegisterParser(OPTION_REMOTE_ID, func(data []byte) (Option, error) { return ParseOptRemoteId(data) })
+	RegisterParser(RELAY_AGENT_SUBSCRIBER_ID, func(data []byte) (Option, error) { return ParseOptSubscriberID(data) })
+	RegisterParser(OPTION_INTERFACE_ID, func(data []byte) (Option, error) { return ParseOptInterfaceId(data) })
+	RegisterParser(OPTION_CLIENT_ARCH_TYPE, func(data []byte) (Option, error) { return ParseOptClientArchType(data) })
+	RegisterParser(OPTION_NII, func(data []byte) (Option, error) { return ParseOptNetworkInterfaceId(data) })
+	RegisterParser(OPT_BOOTFILE_URL, func(data []byte) (Option, error) { return ParseOptBootFileURL(data) })
+	RegisterParser(OPT_BOOTFILE_PARAM, func(data []byte) (Option, error) { return ParseOptBootFileParam(data) })
+	RegisterParser(OPTION_USER_CLASS, func(data []byte) (Option, error) { return ParseOptUserClass(data) })
+	RegisterParser(OPTION_VENDOR_CLASS, func(data []byte) (Option, error) { return ParseOptVendorClass(data) })
+	RegisterParser(OPTION_VENDOR_OPTS, func(data []byte) (Option, error) { return ParseOptVendorOpts(data) })
+	RegisterParser(INFORMATION_REFRESH_TIME, func(data []byte) (Option, error) { return ParseOptInformationRefreshTime(data) })
+	RegisterParser(FQDN, func(data []byte) (Option, error) { return ParseOptClientFQDN(data) })
+	RegisterParser(OPTION_NTP_SERVER, func(data []byte) (Option, error) { return ParseOptNTPServer(data) })
+	RegisterParser(OPTION_RAPID_COMMIT, func(data []byte) (Option, error) { return ParseOptRapidCommit(data) })
+	RegisterParser(OPTION_RECONF_MSG, func(data []byte) (Option, error) { return ParseOptReconfigureMessage(data) })
+	RegisterParser(OPTION_RECONF_ACCEPT, func(data []byte) (Option, error) { return ParseOptReconfigureAccept(data) })
+	RegisterParser(OPTION_DHCPV4_MSG, func(data []byte) (Option, error) { return ParseOptDHCPv4Msg(data) })
+	RegisterParser(OPTION_DHCP4_O_DHCP6_SERVER, func(data []byte) (Option, error) { return ParseOptDHCP4oDHCP6Server(data) })
+	RegisterParser(OPTION_S46_RULE, func(data []byte) (Option, error) { return ParseOptS46Rule(data) })
+	RegisterParser(OPTION_S46_BR, func(data []byte) (Option, error) { return ParseOptS46BR(data) })
+	RegisterParser(OPTION_S46_CONT_MAPE, func(data []byte) (Option, error) { return ParseOptS46ContainerMAPE(data) })
+	RegisterParser(OPTION_S46_CONT_MAPT, func(data []byte) (Option, error) { return ParseOptS46ContainerMAPT(data) })
+	RegisterParser(OPTION_S46_CONT_LW, func(data []byte) (Option, error) { return ParseOptS46ContainerLW(data) })
+	RegisterParser(OPTION_RELAY_PORT, func(data []byte) (Option, error) { return ParseOptRelayPort(data) })
+}
+
+// parseOption builds an option from its code and data, dispatching to the
+// registered parser. depth is the nesting level of the relay message the
+// option belongs to, or zero if the option is not part of a relay message.
+// Within relay messages, relay message options are always parsed by
+// parseOptRelayMsg, so that the nesting depth can be enforced. For the same
+// reason, OPTION_LQ_RELAY_DATA and the OPTION_CLIENT_DATA carrying it are
+// always parsed by parseOptLQRelayData and parseOptClientData.
+func parseOption(code OptionCode, optData []byte, depth int) (Option, error) {
 	var (
 		err error
 		opt Option
 	)
-	optData := dataStart[4 : 4+length]
-	switch code {
-	case OPTION_CLIENTID:
-		opt, err = ParseOptClientId(optData)
-	case OPTION_SERVERID:
-		opt, err = ParseOptServerId(optData)
-	case OPTION_ELAPSED_TIME:
-		opt, err = ParseOptElapsedTime(optData)
-	case OPTION_ORO:
-		opt, err = ParseOptRequestedOption(optData)
-	case DNS_RECURSIVE_NAME_SERVER:
-		opt, err = ParseOptDNSRecursiveNameServer(optData)
-	case DOMAIN_SEARCH_LIST:
-		opt, err = ParseOptDomainSearchList(optData)
-	case OPTION_IA_NA:
-		opt, err = ParseOptIANA(optData)
-	case OPTION_IA_PD:
-		opt, err = ParseOptIAForPrefixDelegation(optData)
-	case OPTION_IAADDR:
-		opt, err = ParseOptIAAddress(optData)
-	case OPTION_IAPREFIX:
-		opt, err = ParseOptIAPrefix(optData)
-	case OPTION_STATUS_CODE:
-		opt, err = ParseOptStatusCode(optData)
-	case OPTION_RELAY_MSG:
-		opt, err = ParseOptRelayMsg(optData)
-	case OPTION_REMOTE_ID:
-		opt, err = ParseOptRemoteId(optData)
-	case OPTION_INTERFACE_ID:
-		opt, err = ParseOptInterfaceId(optData)
-	case OPTION_CLIENT_ARCH_TYPE:
-		opt, err = ParseOptClientArchType(optData)
-	case OPTION_NII:
-		opt, err = ParseOptNetworkInterfaceId(optData)
-	case OPT_BOOTFILE_URL:
-		opt, err = ParseOptBootFileURL(optData)
-	case OPTION_USER_CLASS:
-		opt, err = ParseOptUserClass(optData)
-	default:
+	optionParsersMu.RLock()
+	parse, ok := optionParsers[code]
+	optionParsersMu.RUnlock()
+	if depth > 0 && code == OPTION_RELAY_MSG {
+		opt, err = parseOptRelayMsg(optData, depth)
+	} else if code == OPTION_LQ_RELAY_DATA {
+		opt, err = parseOptLQRelayData(optData, depth)
+	} else if code == OPTION_CLIENT_DATA {
+		opt, err = parseOptClientData(optData, depth)
+	} else if ok {
+		opt, err = parse(optData)
+	} else {
 		opt = &OptionGeneric{OptionCode: code, OptionData: optData}
 	}
 	if err != nil {
-		return nil, err
+		return nil, &OptionParseError{Code: code, Err: err}
 	}
-	if length != opt.Length() {
-		return nil, fmt.Errorf("Error: declared length is different from actual length for option %d: %d != %d",
-			code, opt.Length(), length)
+	if len(optData) != opt.Length() {
+		return nil, newOptionParseError(code, ErrLengthMismatch, "Error: declared length is different from actual length for option %d: %d != %d",
+			code, opt.Length(), len(optData))
 	}
 	return opt, nil
 }
 
-func OptionsFromBytes(data []byte) ([]Option, error) {
-	// Parse a sequence of bytes until the end and build a list of options from
-	// it. Returns an error if any invalid option or length is found.
-	options := make([]Option, 0, 10)
+// OptionsFromBytes parses a sequence of bytes until the end and builds a list
+// of options from it. Returns an error if any invalid option or length is
+// found.
+//
+// Parsing does not copy the input data: OptionGeneric data, the DUIDs of
+// OptClientId and OptServerId, IP addresses and user and vendor classes all
+// alias the input buffer, which must not be modified while the options are in
+// use. Options that need to own their data (e.g. OptRemoteId, OptInterfaceId,
+// OptBootFileURL, OptStatusCode) copy it.
+func OptionsFromBytes(data []byte) (Options, error) {
+	return OptionsFromBytesLimit(data, MaxOptions)
+}
+
+// MaxOptions is the maximum number of options OptionsFromBytes and the
+// message parsers accept in a single list of options, bounding the memory a
+// packet made of many empty options can make the parser allocate. Options
+// nested in other options are counted separately for each option. A value of
+// zero or less disables the limit.
+var MaxOptions = 1024
+
+// OptionsFromBytesLimit parses a sequence of bytes like OptionsFromBytes, but
+// returns ErrTooManyOptions as soon as more than maxOptions options are found.
+// A maxOptions of zero or less disables the limit.
+func OptionsFromBytesLimit(data []byte, maxOptions int) (Options, error) {
+	options, errs := optionsFromBytes(data, true, 0, maxOptions)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return options, nil
+}
+
+// OptionsFromBytesMode parses a sequence of bytes like OptionsFromBytes. If
+// strict is true, parsing stops at the first invalid option, and no options
+// are returned. Otherwise, an option whose value fails to parse is returned
+// as an OptionGeneric, its error is collected and parsing continues with the
+// next option; parsing only stops when the option headers are truncated.
+// Parsing always stops once more than MaxOptions options are found. The
+// returned errors are all *OptionParseError.
+func OptionsFromBytesMode(data []byte, strict bool) (Options, []error) {
+	return optionsFromBytes(data, strict, 0, MaxOptions)
+}
+
+// optionsFromBytes implements OptionsFromBytesMode for the options of a relay
+// message nested at the given depth, see parseOption, accepting at most
+// maxOptions options
+func optionsFromBytes(data []byte, strict bool, depth int, maxOptions int) (Options, []error) {
+	var errs []error
+	options := make(Options, 0, 10)
 	if len(data) == 0 {
 		// no options, no party
 		return options, nil
 	}
 	if len(data) < 4 {
 		// cannot be shorter than option code (2 bytes) + length (2 bytes)
-		return nil, fmt.Errorf("Invalid options: shorter than 4 bytes")
+		return nil, []error{newOptionParseError(0, ErrOptionTooShort, "Invalid options: shorter than 4 bytes")}
 	}
-	idx := 0
-	for {
-		if idx == len(data) {
+	dec := NewDecoder(data)
+	for dec.Len() > 0 {
+		if dec.Len() < 4 {
+			// the options are followed by 1 to 3 stray bytes, too short to
+			// be an option header
+			errs = append(errs, newOptionParseError(0, ErrTrailingData, "Invalid options: %v trailing bytes after the last option", dec.Len()))
 			break
 		}
-		if idx > len(data) {
-			// this should never happen
-			return nil, fmt.Errorf("Error: reading past the end of options")
+		code, _ := dec.Read16()
+		length, _ := dec.Read16()
+		if maxOptions > 0 && len(options) >= maxOptions {
+			errs = append(errs, newOptionParseError(OptionCode(code), ErrTooManyOptions, "Invalid options: more than %v options", maxOptions))
+			break
 		}
-		opt, err := ParseOption(data[idx:])
+		optData, err := dec.ReadN(int(length))
 		if err != nil {
-			return nil, err
+			errs = append(errs, newOptionParseError(OptionCode(code), ErrOptionTooShort, "Invalid option length for option %v. Declared %v, actual %v",
+				OptionCode(code), length, dec.Len(),
+			))
+			break
+		}
+		opt, err := parseOption(OptionCode(code), optData, depth)
+		if err != nil {
+			errs = append(errs, err)
+			if strict {
+				break
+			}
+			opt = &OptionGeneric{OptionCode: OptionCode(code), OptionData: optData}
 		}
 		options = append(options, opt)
-		idx += opt.Length() + 4 // 4 bytes for type + length
 	}
-	return options, nil
+	if strict && len(errs) > 0 {
+		return nil, errs
+	}
+	return options, errs
 }
@@ -0,0 +1,471 @@
+package dhcpv6
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsGet(t *testing.T) {
+	iana1 := &OptIANA{IaId: IAID{1, 0, 0, 0}}
+	iana2 := &OptIANA{IaId: IAID{2, 0, 0, 0}}
+	opts := Options{&OptElapsedTime{}, iana1, iana2}
+	require.Equal(t, []Option{iana1, iana2}, opts.Get(OPTION_IA_NA))
+	require.Nil(t, opts.Get(OPTION_IA_PD))
+	require.Equal(t, iana1, opts.GetOne(OPTION_IA_NA))
+	require.Nil(t, opts.GetOne(OPTION_IA_PD))
+}
+
+func TestOptionsAddAndUpdate(t *testing.T) {
+	var opts Options
+	opts.Add(&OptElapsedTime{elapsedTime: 1})
+	require.Equal(t, 1, len(opts))
+
+	// replaces the existing option
+	opts.Update(&OptElapsedTime{elapsedTime: 2})
+	require.Equal(t, 1, len(opts))
+	require.Equal(t, 20*time.Millisecond, opts.GetOne(OPTION_ELAPSED_TIME).(*OptElapsedTime).ElapsedTime())
+
+	// appends when absent
+	opts.Update(&OptStatusCode{})
+	require.Equal(t, 2, len(opts))
+}
+
+func TestOptionsForEach(t *testing.T) {
+	opts := Options{
+		&OptIANA{IaId: [4]byte{1}},
+		&OptElapsedTime{},
+		&OptIANA{IaId: [4]byte{2}},
+		&OptIANA{IaId: [4]byte{3}},
+	}
+	var iaids []IAID
+	opts.ForEach(OPTION_IA_NA, func(opt Option) bool {
+		iaids = append(iaids, opt.(*OptIANA).IaId)
+		return true
+	})
+	require.Equal(t, []IAID{{1}, {2}, {3}}, iaids)
+
+	// returning false stops the iteration
+	count := 0
+	opts.ForEach(OPTION_IA_NA, func(opt Option) bool {
+		count++
+		return opt.(*OptIANA).IaId != IAID{2}
+	})
+	require.Equal(t, 2, count)
+
+	opts.ForEach(OPTION_IA_PD, func(Option) bool {
+		t.Fatal("unexpected option")
+		return true
+	})
+}
+
+// benchmarkIANAOptions returns a list of options holding several IA_NA
+func benchmarkIANAOptions() Options {
+	opts := Options{&OptElapsedTime{}}
+	for i := 0; i < 4; i++ {
+		opts = append(opts, &OptIANA{IaId: [4]byte{byte(i)}})
+	}
+	return opts
+}
+
+func BenchmarkOptionsGet(b *testing.B) {
+	opts := benchmarkIANAOptions()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for _, opt := range opts.Get(OPTION_IA_NA) {
+			count += len(opt.(*OptIANA).Options)
+		}
+	}
+}
+
+func BenchmarkOptionsForEach(b *testing.B) {
+	opts := benchmarkIANAOptions()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		opts.ForEach(OPTION_IA_NA, func(opt Option) bool {
+			count += len(opt.(*OptIANA).Options)
+			return true
+		})
+	}
+}
+
+func BenchmarkOptionsFromBytes(b *testing.B) {
+	data := []byte{
+		0, 1, 0, 10, 0, 3, 0, 1, 0xfa, 0xce, 0xb0, 0x00, 0x00, 0x0c, // OptClientId
+		0, 8, 0, 2, 0, 0, // OptElapsedTime
+		0, 6, 0, 4, 0, 23, 0, 24, // OptRequestedOption
+		0, 15, 0, 11, 0, 9, 'l', 'i', 'n', 'u', 'x', 'b', 'o', 'o', 't', // OptUserClass
+		0, 3, 0, 12, 1, 2, 3, 4, 0, 0, 0, 0, 0, 0, 0, 0, // OptIANA
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := OptionsFromBytes(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// optTest is a custom option used to test the parser registry
+type optTest struct {
+	data []byte
+}
+
+func (op *optTest) Code() OptionCode { return OptionCode(65002) }
+func (op *optTest) ToBytes() []byte {
+	return append([]byte{0xfd, 0xea, 0, byte(len(op.data))}, op.data...)
+}
+func (op *optTest) Length() int    { return len(op.data) }
+func (op *optTest) String() string { return "optTest" }
+
+func TestRegisterParser(t *testing.T) {
+	data := []byte{0xfd, 0xea, 0, 2, 'h', 'i'}
+	opt, err := ParseOption(data)
+	require.NoError(t, err)
+	require.IsType(t, &OptionGeneric{}, opt)
+
+	RegisterParser(OptionCode(65002), func(data []byte) (Option, error) {
+		return &optTest{data: data}, nil
+	})
+	defer RegisterParser(OptionCode(65002), nil)
+	opt, err = ParseOption(data)
+	require.NoError(t, err)
+	require.Equal(t, &optTest{data: []byte("hi")}, opt)
+	require.Equal(t, data, opt.ToBytes())
+
+	RegisterParser(OptionCode(65002), nil)
+	opt, err = ParseOption(data)
+	require.NoError(t, err)
+	require.IsType(t, &OptionGeneric{}, opt)
+}
+
+func TestRegisterParserError(t *testing.T) {
+	RegisterParser(OptionCode(65003), func(data []byte) (Option, error) {
+		return nil, fmt.Errorf("always fails")
+	})
+	defer RegisterParser(OptionCode(65003), nil)
+	_, err := ParseOption([]byte{0xfd, 0xeb, 0, 0})
+	require.Error(t, err)
+}
+
+func TestBuiltinParsersRegistered(t *testing.T) {
+	opt, err := ParseOption([]byte{0, 8, 0, 2, 0, 0})
+	require.NoError(t, err)
+	require.IsType(t, &OptElapsedTime{}, opt)
+}
+
+func TestOptionsFromBytesModeLenient(t *testing.T) {
+	data := []byte{
+		0, 8, 0, 3, 0, 0, 0, // malformed elapsed time, 3 bytes
+		0, 6, 0, 2, 0, 23, // ORO
+		0, 14, 0, 1, 0xaa, // malformed rapid commit, 1 byte
+	}
+	opts, errs := OptionsFromBytesMode(data, false)
+	require.Equal(t, 2, len(errs))
+	require.Equal(t, 3, len(opts))
+	require.Equal(t, &OptionGeneric{OptionCode: OPTION_ELAPSED_TIME, OptionData: []byte{0, 0, 0}}, opts[0])
+	require.IsType(t, &OptRequestedOption{}, opts[1])
+	require.IsType(t, &OptionGeneric{}, opts[2])
+	// the malformed options serialize back to the original bytes
+	s := NewSerializer(len(data))
+	s.WriteOptions(opts)
+	require.Equal(t, data, s.Bytes())
+
+	opts, errs = OptionsFromBytesMode(data, true)
+	require.Nil(t, opts)
+	require.Equal(t, 1, len(errs))
+}
+
+func TestOptionsFromBytesModeTruncated(t *testing.T) {
+	data := []byte{
+		0, 6, 0, 2, 0, 23, // ORO
+		0, 8, 0, 4, 0, 0, // truncated elapsed time
+	}
+	opts, errs := OptionsFromBytesMode(data, false)
+	require.Equal(t, 1, len(errs))
+	require.True(t, errors.Is(errs[0], ErrOptionTooShort))
+	require.Equal(t, 1, len(opts))
+}
+
+func TestParseOptionCopy(t *testing.T) {
+	buf := []byte{0xfd, 0xe9, 0, 3, 'a', 'b', 'c', 0xff}
+	opt, err := ParseOptionCopy(buf)
+	require.NoError(t, err)
+	aliased, err := ParseOption(buf)
+	require.NoError(t, err)
+	// reuse the buffer, as a read loop would
+	copy(buf, []byte{0xfd, 0xe9, 0, 3, 'x', 'y', 'z'})
+	require.Equal(t, []byte("abc"), opt.(*OptionGeneric).OptionData)
+	require.Equal(t, []byte("xyz"), aliased.(*OptionGeneric).OptionData)
+}
+
+func TestParseOptionCopyClientId(t *testing.T) {
+	buf := []byte{0, 1, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5}
+	opt, err := ParseOptionCopy(buf)
+	require.NoError(t, err)
+	for i := range buf {
+		buf[i] = 0
+	}
+	cid := opt.(*OptClientId).Cid
+	require.Equal(t, net.HardwareAddr{0, 1, 2, 3, 4, 5}, cid.LinkLayerAddr)
+}
+
+func TestOptionsFromBytesLimit(t *testing.T) {
+	// 10000 empty OPTION_RAPID_COMMIT options
+	var data []byte
+	for i := 0; i < 10000; i++ {
+		data = append(data, 0, 14, 0, 0)
+	}
+	_, err := OptionsFromBytesLimit(data, 100)
+	require.True(t, errors.Is(err, ErrTooManyOptions))
+	opts, err := OptionsFromBytesLimit(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, 10000, len(opts))
+	opts, err = OptionsFromBytesLimit(data[:400], 100)
+	require.NoError(t, err)
+	require.Equal(t, 100, len(opts))
+
+	// the default limit applies to the message parsers
+	_, err = OptionsFromBytes(data)
+	require.True(t, errors.Is(err, ErrTooManyOptions))
+	_, err = MessageFromBytes(append([]byte{byte(SOLICIT), 1, 2, 3}, data...))
+	require.True(t, errors.Is(err, ErrTooManyOptions))
+	relay := make([]byte, RelayHeaderSize)
+	relay[0] = byte(RELAY_FORW)
+	_, err = RelayMessageFromBytes(append(relay, data...))
+	require.True(t, errors.Is(err, ErrTooManyOptions))
+	_, errs := OptionsFromBytesMode(data, false)
+	require.Equal(t, 1, len(errs))
+	require.True(t, errors.Is(errs[0], ErrTooManyOptions))
+}
+
+func TestOptionsFromBytesExactFit(t *testing.T) {
+	// the last option ends exactly at the end of the buffer
+	data := []byte{
+		0, 6, 0, 2, 0, 23, // ORO
+		0, 14, 0, 0, // rapid commit
+	}
+	opts, err := OptionsFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(opts))
+	require.IsType(t, &OptRapidCommit{}, opts[1])
+}
+
+func TestOptionsFromBytesTrailingData(t *testing.T) {
+	data := []byte{
+		0, 6, 0, 2, 0, 23, // ORO
+		0, 14, // stray bytes
+	}
+	_, err := OptionsFromBytes(data)
+	require.True(t, errors.Is(err, ErrTrailingData))
+	require.False(t, errors.Is(err, ErrOptionTooShort))
+	require.Equal(t, "Invalid options: 2 trailing bytes after the last option", err.Error())
+
+	opts, errs := OptionsFromBytesMode(data, false)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, 1, len(opts))
+}
+
+func TestOptionsToBytes(t *testing.T) {
+	opts := Options{
+		&OptRapidCommit{},
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1}},
+		&OptElapsedTime{elapsedTime: 0xaabb},
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{2}},
+	}
+	expected := []byte{
+		0, 14, 0, 0, // OPTION_RAPID_COMMIT
+		0xfd, 0xe9, 0, 1, 1,
+		0, 8, 0, 2, 0xaa, 0xbb, // OPTION_ELAPSED_TIME
+		0xfd, 0xe9, 0, 1, 2,
+	}
+	require.Equal(t, len(expected), opts.Length())
+	require.Equal(t, expected, opts.ToBytes())
+
+	// messages keep the insertion order too
+	msg := DHCPv6Message{messageType: SOLICIT}
+	msg.SetOptions(opts)
+	require.Equal(t, expected, msg.ToBytes()[4:])
+}
+
+func TestOptionsToBytesSorted(t *testing.T) {
+	opts := Options{
+		&OptRapidCommit{},
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1}},
+		&OptElapsedTime{elapsedTime: 0xaabb},
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{2}},
+	}
+	expected := []byte{
+		0, 8, 0, 2, 0xaa, 0xbb, // OPTION_ELAPSED_TIME
+		0, 14, 0, 0, // OPTION_RAPID_COMMIT
+		// options with the same code keep their relative order
+		0xfd, 0xe9, 0, 1, 1,
+		0xfd, 0xe9, 0, 1, 2,
+	}
+	require.Equal(t, expected, opts.ToBytesSorted())
+	// the options themselves are not reordered
+	require.Equal(t, OPTION_RAPID_COMMIT, opts[0].Code())
+
+	// the output does not depend on the insertion order
+	reversed := Options{opts[2], opts[1], opts[3], opts[0]}
+	require.Equal(t, expected, reversed.ToBytesSorted())
+}
+
+func TestOptionsClone(t *testing.T) {
+	oro := &OptRequestedOption{}
+	oro.SetRequestedOptions([]OptionCode{DNS_RECURSIVE_NAME_SERVER})
+	iaNA := &OptIANA{IaId: [4]byte{1, 2, 3, 4}}
+	iaNA.Options.Add(&OptStatusCode{StatusCode: 0, StatusMessage: []byte("ok")})
+	opts := Options{
+		oro,
+		iaNA,
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte("abc")},
+	}
+	clone := opts.Clone()
+	require.Equal(t, len(opts), len(clone))
+	for i := range opts {
+		require.Equal(t, opts[i].ToBytes(), clone[i].ToBytes())
+	}
+
+	// mutations of the clone don't affect the original
+	clone.GetOne(OPTION_ORO).(*OptRequestedOption).AddRequestedOption(DOMAIN_SEARCH_LIST)
+	clone.GetOne(OPTION_IA_NA).(*OptIANA).Options.Update(&OptStatusCode{StatusCode: 2})
+	clone.GetOne(0xfde9).(*OptionGeneric).OptionData[0] = 'x'
+	clone.Update(&OptElapsedTime{})
+	require.Equal(t, []OptionCode{DNS_RECURSIVE_NAME_SERVER}, oro.RequestedOptions())
+	require.Equal(t, []byte("ok"), iaNA.Options.Status().StatusMessage)
+	require.Equal(t, []byte("abc"), opts[2].(*OptionGeneric).OptionData)
+	require.Equal(t, 3, len(opts))
+
+	require.Nil(t, Options(nil).Clone())
+}
+
+func TestSyncOptions(t *testing.T) {
+	template := NewSyncOptions(Options{&OptElapsedTime{}})
+	var wg sync.WaitGroup
+	elapsed := make([]time.Duration, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply := template.Snapshot()
+			reply.Update(NewOptElapsedTime(time.Duration(i) * time.Second))
+			elapsed[i] = reply.GetOne(OPTION_ELAPSED_TIME).(*OptElapsedTime).ElapsedTime()
+			template.Add(&OptRapidCommit{})
+			template.GetOne(OPTION_RAPID_COMMIT)
+		}(i)
+	}
+	wg.Wait()
+	for i, e := range elapsed {
+		require.Equal(t, time.Duration(i)*time.Second, e)
+	}
+	require.Equal(t, 10, len(template.Get(OPTION_RAPID_COMMIT)))
+	require.Equal(t, time.Duration(0), template.GetOne(OPTION_ELAPSED_TIME).(*OptElapsedTime).ElapsedTime())
+}
+
+func TestCloneOption(t *testing.T) {
+	addr := &OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1"), preferredLifetime: 3600, validLifetime: 7200}
+	addr.Options = append(addr.Options, &OptStatusCode{StatusMessage: []byte("ok")})
+	cid := &OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6})}
+	for _, opt := range []Option{
+		addr,
+		&OptIANA{IaId: IAID{1, 2, 3, 4}, T1: 1, T2: 2, Options: Options{addr}},
+		cid,
+		&OptServerId{Sid: *NewDuidEN(0x137, []byte{1, 2, 3})},
+		&OptElapsedTime{elapsedTime: 3},
+		&OptRequestedOption{requestedOptions: []OptionCode{DNS_RECURSIVE_NAME_SERVER}},
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte("abc")},
+		// copied through its serialized form
+		&OptRemoteId{enterpriseNumber: 1, remoteId: []byte("remote")},
+	} {
+		clone := CloneOption(opt)
+		require.IsType(t, opt, clone)
+		require.Equal(t, opt.ToBytes(), clone.ToBytes())
+	}
+
+	// the clones don't share data with the source
+	addrClone := CloneOption(addr).(*OptIAAddress)
+	addrClone.IPv6Addr[15] = 2
+	addrClone.Options[0].(*OptStatusCode).StatusMessage[0] = 'x'
+	require.Equal(t, net.ParseIP("2001:db8::1"), addr.IPv6Addr)
+	require.Equal(t, []byte("ok"), addr.Options[0].(*OptStatusCode).StatusMessage)
+	cidClone := CloneOption(cid).(*OptClientId)
+	cidClone.Cid.LinkLayerAddr[0] = 0xff
+	require.Equal(t, net.HardwareAddr{1, 2, 3, 4, 5, 6}, cid.Cid.LinkLayerAddr)
+}
+
+func BenchmarkCloneOptionCopy(b *testing.B) {
+	opts := benchmarkMessage().Options()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, opt := range opts {
+			CloneOption(opt)
+		}
+	}
+}
+
+// BenchmarkCloneOptionParse clones the same options as
+// BenchmarkCloneOptionCopy through their serialized form
+func BenchmarkCloneOptionParse(b *testing.B) {
+	opts := benchmarkMessage().Options()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, opt := range opts {
+			if _, err := ParseOption(opt.ToBytes()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestEqualOptions(t *testing.T) {
+	cid := &OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 6})}
+	for _, tc := range []struct {
+		a, b  Option
+		equal bool
+	}{
+		{cid, CloneOption(cid), true},
+		{cid, &OptClientId{Cid: *NewDuidLL(iana.HwTypeEthernet, net.HardwareAddr{1, 2, 3, 4, 5, 7})}, false},
+		// same DUID, different options
+		{cid, &OptServerId{Sid: cid.Cid}, false},
+		{NewOptElapsedTime(time.Second), NewOptElapsedTime(time.Second), true},
+		{NewOptElapsedTime(time.Second), NewOptElapsedTime(2 * time.Second), false},
+		{&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 2}}, &OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 2}}, true},
+		{&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 2}}, &OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 3}}, false},
+		{&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1, 2}}, &OptionGeneric{OptionCode: 0xfdea, OptionData: []byte{1, 2}}, false},
+		// an unparsed option equals the typed one with the same bytes
+		{&OptionGeneric{OptionCode: OPTION_CLIENTID, OptionData: cid.ToBytes()[4:]}, cid, true},
+		{cid, &OptionGeneric{OptionCode: OPTION_CLIENTID, OptionData: cid.ToBytes()[4:]}, true},
+		// options without an Equal method are compared by their bytes
+		{&OptRapidCommit{}, &OptRapidCommit{}, true},
+		{&OptUserClass{UserClasses: [][]byte{{0xff}}}, &OptUserClass{UserClasses: [][]byte{{0xfe}}}, false},
+		{nil, nil, true},
+		{cid, nil, false},
+		{nil, cid, false},
+	} {
+		require.Equal(t, tc.equal, EqualOptions(tc.a, tc.b), "%v and %v", tc.a, tc.b)
+	}
+}
+
+func TestOptionsEqual(t *testing.T) {
+	opts := Options{
+		&OptRapidCommit{},
+		NewOptElapsedTime(time.Second),
+		&OptionGeneric{OptionCode: 0xfde9, OptionData: []byte{1}},
+	}
+	require.True(t, opts.Equal(opts.Clone()))
+	require.True(t, Options(nil).Equal(Options{}))
+	require.False(t, opts.Equal(opts[:2]))
+	// the comparison is order-sensitive
+	require.False(t, opts.Equal(Options{opts[1], opts[0], opts[2]}))
+	clone := opts.Clone()
+	clone[2].(*OptionGeneric).OptionData[0] = 2
+	require.False(t, opts.Equal(clone))
+}
@@ -0,0 +1,252 @@
+// Package pcap extracts and parses the DHCPv6 messages of a packet capture,
+// to feed captures of real traffic through the dhcpv6 parsers in tests. It
+// reads the classic libpcap file format, with Ethernet, raw IP, BSD loopback
+// and Linux cooked link types, and does not depend on libpcap.
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// Link types, see https://www.tcpdump.org/linktypes.html
+const (
+	linkTypeNull      = 0
+	linkTypeEthernet  = 1
+	linkTypeRaw       = 101
+	linkTypeLinuxSLL  = 113
+	linkTypeLinuxSLL2 = 276
+)
+
+const (
+	etherTypeIPv6  = 0x86dd
+	etherTypeVLAN  = 0x8100
+	etherTypeQinQ  = 0x88a8
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+	protocolUDP    = 17
+	// maxSnapLen is the largest snapshot length accepted by libpcap, used
+	// when the snapshot length of a capture is zero or larger
+	maxSnapLen = 262144
+)
+
+// ParseErrors collects the errors of the DHCPv6 packets of a capture that
+// could not be parsed, and of the options that could not be parsed in the
+// returned messages
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d packets could not be parsed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// ParsePcapFile reads a capture file, see ParsePcap
+func ParsePcapFile(path string) ([]*dhcpv6.DHCPv6Message, []*dhcpv6.DHCPv6Relay, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParsePcap(bytes.NewReader(data))
+}
+
+// ParsePcap parses the UDP payloads sent from or to the DHCPv6 client and
+// server ports of a capture. Messages are parsed with
+// dhcpv6.MessageFromBytesMode and their options with
+// dhcpv6.OptionsFromBytesMode, both in non-strict mode, so that an invalid
+// option is returned as a dhcpv6.OptionGeneric instead of dropping the
+// message. Relay messages are parsed with dhcpv6.RelayMessageFromBytes.
+// Packets that are not DHCPv6 are skipped, and parsing continues after the
+// DHCPv6 packets that cannot be parsed: the returned error is then a
+// ParseErrors, returned along with the parsed messages. Any other error means
+// that the capture itself is invalid, e.g. a packet is larger than the
+// snapshot length of the capture.
+func ParsePcap(r io.Reader) ([]*dhcpv6.DHCPv6Message, []*dhcpv6.DHCPv6Relay, error) {
+	var header [24]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, nil, fmt.Errorf("Invalid pcap header: %v", err)
+	}
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(header[0:4]) {
+	case 0xa1b2c3d4, 0xa1b23c4d:
+		order = binary.LittleEndian
+	case 0xd4c3b2a1, 0x4d3cb2a1:
+		order = binary.BigEndian
+	default:
+		return nil, nil, errors.New("Invalid pcap header: unknown magic number")
+	}
+	linkType := order.Uint32(header[20:24]) & 0x0fffffff
+	snapLen := order.Uint32(header[16:20])
+	if snapLen == 0 || snapLen > maxSnapLen {
+		snapLen = maxSnapLen
+	}
+
+	var (
+		messages []*dhcpv6.DHCPv6Message
+		relays   []*dhcpv6.DHCPv6Relay
+		errs     ParseErrors
+	)
+	for index := 1; ; index++ {
+		var record [16]byte
+		if _, err := io.ReadFull(r, record[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, nil, fmt.Errorf("Invalid header for packet %d: %v", index, err)
+		}
+		length := order.Uint32(record[8:12])
+		if length > snapLen {
+			return nil, nil, fmt.Errorf("Invalid header for packet %d: length %d larger than the snapshot length %d", index, length, snapLen)
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, nil, fmt.Errorf("Invalid data for packet %d: %v", index, err)
+		}
+		payload := dhcpv6Payload(linkType, frame)
+		if payload == nil {
+			continue
+		}
+		d, perrs := parseMessage(payload)
+		for _, err := range perrs {
+			errs = append(errs, fmt.Errorf("packet %d: %v", index, err))
+		}
+		if d == nil {
+			continue
+		}
+		switch m := d.(type) {
+		case *dhcpv6.DHCPv6Message:
+			messages = append(messages, m)
+		case *dhcpv6.DHCPv6Relay:
+			relays = append(relays, m)
+		}
+	}
+	if len(errs) > 0 {
+		return messages, relays, errs
+	}
+	return messages, relays, nil
+}
+
+// parseMessage parses a message or relay message. It returns a nil message
+// if the packet header cannot be parsed, and a message along with the errors
+// of its invalid options otherwise.
+func parseMessage(data []byte) (dhcpv6.DHCPv6, []error) {
+	if len(data) > 0 {
+		if t := dhcpv6.MessageType(data[0]); t == dhcpv6.RELAY_FORW || t == dhcpv6.RELAY_REPL {
+			relay, errs := dhcpv6.RelayMessageFromBytesLenient(data)
+			if relay == nil {
+				return nil, errs
+			}
+			return relay, errs
+		}
+	}
+	if len(data) < dhcpv6.MessageHeaderSize {
+		return nil, []error{fmt.Errorf("Invalid header size: shorter than %v bytes", dhcpv6.MessageHeaderSize)}
+	}
+	msg, err := dhcpv6.MessageFromBytesMode(data[:dhcpv6.MessageHeaderSize], false)
+	if err != nil {
+		return nil, []error{err}
+	}
+	options, errs := dhcpv6.OptionsFromBytesMode(data[dhcpv6.MessageHeaderSize:], false)
+	msg.SetOptions(options)
+	return msg, errs
+}
+
+// dhcpv6Payload returns the UDP payload of a frame if it is a DHCPv6 packet,
+// i.e. an IPv6 UDP packet from or to the client or server port, and nil
+// otherwise
+func dhcpv6Payload(linkType uint32, frame []byte) []byte {
+	var packet []byte
+	switch linkType {
+	case linkTypeNull:
+		// the address family is in host byte order, and its value for IPv6
+		// differs across systems
+		if len(frame) < 4 {
+			return nil
+		}
+		packet = frame[4:]
+	case linkTypeEthernet:
+		if len(frame) < 14 {
+			return nil
+		}
+		etherType, off := binary.BigEndian.Uint16(frame[12:14]), 14
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(frame) < off+4 {
+				return nil
+			}
+			etherType = binary.BigEndian.Uint16(frame[off+2 : off+4])
+			off += 4
+		}
+		if etherType != etherTypeIPv6 {
+			return nil
+		}
+		packet = frame[off:]
+	case linkTypeRaw:
+		packet = frame
+	case linkTypeLinuxSLL:
+		if len(frame) < 16 || binary.BigEndian.Uint16(frame[14:16]) != etherTypeIPv6 {
+			return nil
+		}
+		packet = frame[16:]
+	case linkTypeLinuxSLL2:
+		if len(frame) < 20 || binary.BigEndian.Uint16(frame[0:2]) != etherTypeIPv6 {
+			return nil
+		}
+		packet = frame[20:]
+	default:
+		return nil
+	}
+	return udpPayload(packet)
+}
+
+// udpPayload returns the payload of an IPv6 UDP packet from or to the
+// DHCPv6 ports, skipping the IPv6 extension headers. Fragments are not
+// reassembled and are skipped.
+func udpPayload(packet []byte) []byte {
+	if len(packet) < ipv6HeaderSize || packet[0]>>4 != 6 {
+		return nil
+	}
+	end := ipv6HeaderSize + int(binary.BigEndian.Uint16(packet[4:6]))
+	if end > len(packet) {
+		// truncated capture
+		return nil
+	}
+	next, off := packet[6], ipv6HeaderSize
+	for next != protocolUDP {
+		switch next {
+		case 0, 43, 60: // hop-by-hop, routing and destination options
+			if end < off+8 {
+				return nil
+			}
+			next, off = packet[off], off+8*(int(packet[off+1])+1)
+		default:
+			return nil
+		}
+	}
+	if end < off+udpHeaderSize {
+		return nil
+	}
+	udp := packet[off:end]
+	srcPort := binary.BigEndian.Uint16(udp[0:2])
+	dstPort := binary.BigEndian.Uint16(udp[2:4])
+	if !isDHCPv6Port(srcPort) && !isDHCPv6Port(dstPort) {
+		return nil
+	}
+	length := int(binary.BigEndian.Uint16(udp[4:6]))
+	if length < udpHeaderSize || length > len(udp) {
+		return nil
+	}
+	return udp[udpHeaderSize:length]
+}
+
+func isDHCPv6Port(port uint16) bool {
+	return port == dhcpv6.ClientPort || port == dhcpv6.ServerPort
+}
@@ -0,0 +1,170 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/stretchr/testify/require"
+)
+
+// writePcap builds a little-endian pcap file with Ethernet frames
+func writePcap(t *testing.T, frames ...[]byte) string {
+	var buf []byte
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:4], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(header[4:6], 2)
+	binary.LittleEndian.PutUint16(header[6:8], 4)
+	binary.LittleEndian.PutUint32(header[16:20], 65535)
+	binary.LittleEndian.PutUint32(header[20:24], linkTypeEthernet)
+	buf = append(buf, header...)
+	for _, frame := range frames {
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record[8:12], uint32(len(frame)))
+		binary.LittleEndian.PutUint32(record[12:16], uint32(len(frame)))
+		buf = append(buf, record...)
+		buf = append(buf, frame...)
+	}
+	dir, err := ioutil.TempDir("", "pcap")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "capture.pcap")
+	require.NoError(t, ioutil.WriteFile(path, buf, 0644))
+	return path
+}
+
+// udpFrame builds an Ethernet frame carrying an IPv6 UDP packet
+func udpFrame(srcPort, dstPort uint16, payload []byte) []byte {
+	frame := make([]byte, 14+40+8)
+	binary.BigEndian.PutUint16(frame[12:14], etherTypeIPv6)
+	ip := frame[14:]
+	ip[0] = 6 << 4
+	binary.BigEndian.PutUint16(ip[4:6], uint16(8+len(payload)))
+	ip[6] = protocolUDP
+	ip[7] = 64
+	copy(ip[8:24], net.ParseIP("fe80::1"))
+	copy(ip[24:40], dhcpv6.AllDHCPRelayAgentsAndServers)
+	udp := ip[40:]
+	binary.BigEndian.PutUint16(udp[0:2], srcPort)
+	binary.BigEndian.PutUint16(udp[2:4], dstPort)
+	binary.BigEndian.PutUint16(udp[4:6], uint16(8+len(payload)))
+	return append(frame, payload...)
+}
+
+func TestParsePcapFile(t *testing.T) {
+	solicit, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	solicit.AddOption(&dhcpv6.OptElapsedTime{})
+	relay, err := dhcpv6.EncapsulateRelay(solicit, dhcpv6.RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+
+	path := writePcap(t,
+		udpFrame(dhcpv6.ClientPort, dhcpv6.ServerPort, solicit.ToBytes()),
+		// not DHCPv6
+		udpFrame(5353, 5353, []byte{1, 2, 3}),
+		// truncated relay message
+		udpFrame(dhcpv6.ServerPort, dhcpv6.ServerPort, []byte{byte(dhcpv6.RELAY_FORW), 0}),
+		udpFrame(dhcpv6.ServerPort, dhcpv6.ServerPort, relay.ToBytes()),
+	)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	messages, relays, err := ParsePcapFile(path)
+	require.Error(t, err)
+	errs, ok := err.(ParseErrors)
+	require.True(t, ok)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, 1, len(messages))
+	require.Equal(t, solicit.ToBytes(), messages[0].ToBytes())
+	require.Equal(t, 1, len(relays))
+	require.Equal(t, relay.ToBytes(), relays[0].ToBytes())
+}
+
+func TestParsePcapFileVLAN(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	frame := udpFrame(dhcpv6.ClientPort, dhcpv6.ServerPort, msg.ToBytes())
+	tagged := append([]byte{}, frame[:12]...)
+	tagged = append(tagged, 0x81, 0x00, 0x00, 0x2a)
+	tagged = append(tagged, frame[12:]...)
+	path := writePcap(t, tagged)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	messages, relays, err := ParsePcapFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(messages))
+	require.Equal(t, 0, len(relays))
+}
+
+func TestParsePcapFileInvalidOption(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	payload := msg.ToBytes()
+	// an elapsed time option with 3 bytes of data
+	payload = append(payload, 0, 8, 0, 3, 0, 0, 0)
+	payload = append(payload, (&dhcpv6.OptElapsedTime{}).ToBytes()...)
+	path := writePcap(t, udpFrame(dhcpv6.ClientPort, dhcpv6.ServerPort, payload))
+	defer os.RemoveAll(filepath.Dir(path))
+
+	messages, _, err := ParsePcapFile(path)
+	require.Error(t, err)
+	errs, ok := err.(ParseErrors)
+	require.True(t, ok)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, 1, len(messages))
+	require.Equal(t, 2, len(messages[0].GetOption(dhcpv6.OPTION_ELAPSED_TIME)))
+	require.Equal(t, payload, messages[0].ToBytes())
+}
+
+func TestParsePcapFileInvalidRelayOption(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	relay, err := dhcpv6.EncapsulateRelay(msg, dhcpv6.RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	payload := relay.ToBytes()
+	// an interface ID option followed by an elapsed time option with 3 bytes
+	// of data
+	payload = append(payload, 0, 18, 0, 2, 'e', '0')
+	payload = append(payload, 0, 8, 0, 3, 0, 0, 0)
+	path := writePcap(t, udpFrame(dhcpv6.ServerPort, dhcpv6.ServerPort, payload))
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, relays, err := ParsePcapFile(path)
+	require.Error(t, err)
+	errs, ok := err.(ParseErrors)
+	require.True(t, ok)
+	require.Equal(t, 1, len(errs))
+	require.Equal(t, 1, len(relays))
+	require.Equal(t, 1, len(relays[0].GetOption(dhcpv6.OPTION_INTERFACE_ID)))
+	require.Equal(t, payload, relays[0].ToBytes())
+}
+
+func TestParsePcapFileTooLarge(t *testing.T) {
+	path := writePcap(t, udpFrame(dhcpv6.ClientPort, dhcpv6.ServerPort, nil))
+	defer os.RemoveAll(filepath.Dir(path))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	// a packet larger than the snapshot length
+	binary.LittleEndian.PutUint32(data[24+8:24+12], 0xffffffff)
+
+	_, _, err = ParsePcap(bytes.NewReader(data))
+	require.Error(t, err)
+	_, ok := err.(ParseErrors)
+	require.False(t, ok)
+}
+
+func TestParsePcapFileInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pcap")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "capture.pcap")
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a capture file at all"), 0644))
+	_, _, err = ParsePcapFile(path)
+	require.Error(t, err)
+
+	_, _, err = ParsePcapFile(filepath.Join(dir, "missing.pcap"))
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package dhcpv6
+
+// This module implements the reconfigure key authentication protocol.
+// https://www.ietf.org/rfc/rfc3315.txt, section 21.5
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"errors"
+	"fmt"
+)
+
+// Types of the reconfigure key authentication information
+const (
+	ReconfigureKeyTypeValue   uint8 = 1
+	ReconfigureKeyTypeHMACMD5 uint8 = 2
+)
+
+// reconfigureKeyAuthInfoLen is the length of the authentication information
+// of the reconfigure key protocol: one type byte followed by a 16-byte value
+const reconfigureKeyAuthInfoLen = 1 + md5.Size
+
+// ErrReconfigureKeyMismatch is returned by VerifyReconfigureKey when the
+// HMAC-MD5 digest carried in the message does not match the key
+var ErrReconfigureKeyMismatch = errors.New("reconfigure key HMAC-MD5 digest mismatch")
+
+// reconfigureKeyDigest computes the HMAC-MD5 digest of msg with the digest of
+// its authentication option set to zero, without modifying msg
+func reconfigureKeyDigest(msg *DHCPv6Message, auth *OptAuthentication, key []byte) []byte {
+	zeroed := *auth
+	zeroed.AuthInfo = make([]byte, reconfigureKeyAuthInfoLen)
+	zeroed.AuthInfo[0] = ReconfigureKeyTypeHMACMD5
+	copyMsg := DHCPv6Message{
+		messageType:   msg.messageType,
+		transactionID: msg.transactionID,
+		options:       make(Options, len(msg.options)),
+	}
+	for idx, opt := range msg.options {
+		if opt == auth {
+			opt = &zeroed
+		}
+		copyMsg.options[idx] = opt
+	}
+	mac := hmac.New(md5.New, key)
+	mac.Write(copyMsg.ToBytes())
+	return mac.Sum(nil)
+}
+
+// getReconfigureKeyAuth returns the reconfigure key authentication option of
+// a message
+func getReconfigureKeyAuth(msg *DHCPv6Message) (*OptAuthentication, error) {
+	opt := msg.GetOneOption(OPTION_AUTH)
+	if opt == nil {
+		return nil, errors.New("No authentication option found")
+	}
+	auth, ok := opt.(*OptAuthentication)
+	if !ok {
+		return nil, fmt.Errorf("Invalid authentication option type %T", opt)
+	}
+	if auth.Protocol != AuthProtocolReconfigureKey {
+		return nil, fmt.Errorf("Unsupported authentication protocol %v, expected reconfigure key", auth.Protocol)
+	}
+	if auth.Algorithm != AuthAlgorithmHMACMD5 {
+		return nil, fmt.Errorf("Unsupported authentication algorithm %v, expected HMAC-MD5", auth.Algorithm)
+	}
+	return auth, nil
+}
+
+// VerifyReconfigureKey checks the HMAC-MD5 digest carried in the
+// authentication option of a Reconfigure message against the reconfigure
+// key. It returns ErrReconfigureKeyMismatch if the digest does not match.
+func VerifyReconfigureKey(msg *DHCPv6Message, key []byte) error {
+	auth, err := getReconfigureKeyAuth(msg)
+	if err != nil {
+		return err
+	}
+	if len(auth.AuthInfo) != reconfigureKeyAuthInfoLen {
+		return fmt.Errorf("Invalid reconfigure key authentication information length. Expected %v bytes, got %v",
+			reconfigureKeyAuthInfoLen, len(auth.AuthInfo))
+	}
+	if auth.AuthInfo[0] != ReconfigureKeyTypeHMACMD5 {
+		return fmt.Errorf("Invalid reconfigure key authentication information type %v, expected HMAC-MD5 digest", auth.AuthInfo[0])
+	}
+	digest := reconfigureKeyDigest(msg, auth, key)
+	if !hmac.Equal(digest, auth.AuthInfo[1:]) {
+		return ErrReconfigureKeyMismatch
+	}
+	return nil
+}
+
+// SignReconfigure computes the HMAC-MD5 digest of a Reconfigure message with
+// the reconfigure key and stores it in the message's authentication option.
+// If the message has no authentication option, one with a zero replay
+// detection value is added.
+func SignReconfigure(msg *DHCPv6Message, key []byte) error {
+	if msg.GetOneOption(OPTION_AUTH) == nil {
+		msg.AddOption(&OptAuthentication{
+			Protocol:  AuthProtocolReconfigureKey,
+			Algorithm: AuthAlgorithmHMACMD5,
+			RDM:       AuthRDMMonotonicCounter,
+		})
+	}
+	auth, err := getReconfigureKeyAuth(msg)
+	if err != nil {
+		return err
+	}
+	digest := reconfigureKeyDigest(msg, auth, key)
+	auth.AuthInfo = append([]byte{ReconfigureKeyTypeHMACMD5}, digest...)
+	return nil
+}
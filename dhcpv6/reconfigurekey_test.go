@@ -0,0 +1,65 @@
+package dhcpv6
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testReconfigureKey = []byte("0123456789abcdef")
+
+func newTestReconfigure() *DHCPv6Message {
+	msg := DHCPv6Message{
+		messageType:   RECONFIGURE,
+		transactionID: 0xaabbcc,
+	}
+	msg.AddOption(&OptReconfigureMessage{MessageType: RENEW})
+	msg.AddOption(&OptAuthentication{
+		Protocol:        AuthProtocolReconfigureKey,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             AuthRDMMonotonicCounter,
+		ReplayDetection: [8]byte{0, 0, 0, 0, 0, 0, 0, 1},
+	})
+	return &msg
+}
+
+func TestSignReconfigure(t *testing.T) {
+	msg := newTestReconfigure()
+	require.NoError(t, SignReconfigure(msg, testReconfigureKey))
+	auth := msg.GetOneOption(OPTION_AUTH).(*OptAuthentication)
+	// HMAC-MD5 over the message with a zeroed digest, computed independently
+	expected, err := hex.DecodeString("54354ec45016f174e2b390c60436900c")
+	require.NoError(t, err)
+	require.Equal(t, ReconfigureKeyTypeHMACMD5, auth.AuthInfo[0])
+	require.Equal(t, expected, auth.AuthInfo[1:])
+}
+
+func TestVerifyReconfigureKey(t *testing.T) {
+	msg := newTestReconfigure()
+	require.NoError(t, SignReconfigure(msg, testReconfigureKey))
+
+	parsed, err := MessageFromBytes(msg.ToBytes())
+	require.NoError(t, err)
+	require.NoError(t, VerifyReconfigureKey(parsed, testReconfigureKey))
+	require.Equal(t, ErrReconfigureKeyMismatch, VerifyReconfigureKey(parsed, []byte("wrong key")))
+}
+
+func TestVerifyReconfigureKeyTampered(t *testing.T) {
+	msg := newTestReconfigure()
+	require.NoError(t, SignReconfigure(msg, testReconfigureKey))
+	msg.UpdateOption(&OptReconfigureMessage{MessageType: INFORMATION_REQUEST})
+	require.Equal(t, ErrReconfigureKeyMismatch, VerifyReconfigureKey(msg, testReconfigureKey))
+}
+
+func TestVerifyReconfigureKeyNoAuth(t *testing.T) {
+	msg := DHCPv6Message{messageType: RECONFIGURE}
+	require.Error(t, VerifyReconfigureKey(&msg, testReconfigureKey))
+}
+
+func TestSignReconfigureAddsAuth(t *testing.T) {
+	msg := DHCPv6Message{messageType: RECONFIGURE}
+	require.NoError(t, SignReconfigure(&msg, testReconfigureKey))
+	require.NotNil(t, msg.GetOneOption(OPTION_AUTH))
+	require.NoError(t, VerifyReconfigureKey(&msg, testReconfigureKey))
+}
@@ -0,0 +1,104 @@
+package dhcpv6
+
+// Serializer writes big-endian values to a growable buffer. It is used to
+// serialize a whole message into a single buffer, instead of allocating and
+// appending a new slice for each option.
+type Serializer struct {
+	buf []byte
+}
+
+// NewSerializer returns a Serializer whose buffer can hold sizeHint bytes
+// without growing.
+func NewSerializer(sizeHint int) *Serializer {
+	return &Serializer{buf: make([]byte, 0, sizeHint)}
+}
+
+// OptionSerializer is implemented by options that can serialize themselves
+// into a Serializer. Options that do not implement it are serialized with
+// ToBytes.
+type OptionSerializer interface {
+	SerializeTo(s *Serializer)
+}
+
+// Bytes returns the serialized data. The returned slice aliases the
+// serializer's buffer until the next call to Reset.
+func (s *Serializer) Bytes() []byte {
+	return s.buf
+}
+
+// Len returns the number of bytes written so far
+func (s *Serializer) Len() int {
+	return len(s.buf)
+}
+
+// Reset empties the buffer, retaining the allocated memory for reuse
+func (s *Serializer) Reset() {
+	s.buf = s.buf[:0]
+}
+
+// Write8 writes a single byte
+func (s *Serializer) Write8(v uint8) {
+	s.buf = append(s.buf, v)
+}
+
+// Write16 writes a big-endian uint16
+func (s *Serializer) Write16(v uint16) {
+	s.buf = appendUint16(s.buf, v)
+}
+
+// Write32 writes a big-endian uint32
+func (s *Serializer) Write32(v uint32) {
+	s.buf = appendUint32(s.buf, v)
+}
+
+// WriteBytes writes a sequence of bytes
+func (s *Serializer) WriteBytes(b []byte) {
+	s.buf = append(s.buf, b...)
+}
+
+// WriteOptionHeader writes the option code and length of an option
+func (s *Serializer) WriteOptionHeader(code OptionCode, length int) {
+	s.Write16(uint16(code))
+	s.Write16(uint16(length))
+}
+
+// WriteOption writes an option, including its code and length
+func (s *Serializer) WriteOption(opt Option) {
+	if os, ok := opt.(OptionSerializer); ok {
+		os.SerializeTo(s)
+		return
+	}
+	s.WriteBytes(opt.ToBytes())
+}
+
+// WriteOptions writes a list of options in insertion order, see also
+// Options.ToBytesSorted
+func (s *Serializer) WriteOptions(opts []Option) {
+	for _, opt := range opts {
+		s.WriteOption(opt)
+	}
+}
+
+// MessageToBytes serializes a DHCPv6 message or relay message using a single
+// buffer for all of its options.
+func MessageToBytes(d DHCPv6) []byte {
+	s := NewSerializer(d.Length())
+	if ms, ok := d.(OptionSerializer); ok {
+		ms.SerializeTo(s)
+		return s.Bytes()
+	}
+	s.WriteBytes(d.ToBytes())
+	return s.Bytes()
+}
+
+// appendUint16 appends v to b in big-endian order and returns the extended
+// slice
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+// appendUint32 appends v to b in big-endian order and returns the extended
+// slice
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -1,56 +1,167 @@
 package dhcpv6
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"sync"
 )
 
-type ResponseWriter interface {
-	LocalAddr() net.Addr
-	RemoteAddr() net.Addr
-	WriteMsg(DHCPv6) error
-	Write([]byte) (int, error)
-	Close() error
-}
+// Handler is called by a Server for each DHCPv6 message it receives. conn is
+// the connection to send the response on, and peer the address it came from.
+// If the message was relayed, m is the innermost message and conn is a
+// *RelayConn that carries the relay chain, see RelayConn.
+type Handler func(conn net.PacketConn, peer net.Addr, m *DHCPv6Message)
 
-type Handler interface {
-	ServeDHCP(w ResponseWriter, m *DHCPv6)
+// RelayConn is the connection passed to a Handler for messages received
+// through one or more relay agents. RelayForw holds the outermost
+// RELAY_FORW message, which can be passed to NewRelayReplFromRelayForw to
+// build a correctly nested RELAY_REPL for the response.
+type RelayConn struct {
+	net.PacketConn
+	RelayForw *DHCPv6Relay
 }
 
+// Server implements a DHCPv6 server that dispatches the received messages to
+// a Handler. If PacketConn is nil, ActivateAndServe listens on the DHCPv6
+// server port, for both unicast and the All_DHCP_Relay_Agents_and_Servers
+// multicast group. If Interface is set, the multicast group is joined on that
+// interface and the socket is bound to it, see BindToInterface, so that
+// messages received on other interfaces are ignored. Otherwise the system
+// default interface is used to join the group, and messages are received on
+// all interfaces.
 type Server struct {
 	PacketConn net.PacketConn
 	Handler    Handler
+	Interface  string
+
+	mu     sync.Mutex
+	closed bool
 }
 
-func (s *Server) ActivateAndServe() error {
-	if s.PacketConn == nil {
-		return fmt.Errorf("Error: no packet connection specified")
+// NewServer returns a Server listening on the given interface and
+// dispatching messages to handler
+func NewServer(ifname string, handler Handler) *Server {
+	return &Server{
+		Handler:   handler,
+		Interface: ifname,
+	}
+}
+
+// listen creates the server connection, unless one was provided
+func (s *Server) listen() (net.PacketConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.New("Server is closed")
+	}
+	if s.PacketConn != nil {
+		return s.PacketConn, nil
+	}
+	var iface *net.Interface
+	if s.Interface != "" {
+		var err error
+		iface, err = net.InterfaceByName(s.Interface)
+		if err != nil {
+			return nil, err
+		}
+	}
+	addr := net.UDPAddr{IP: AllDHCPRelayAgentsAndServers, Port: ServerPort}
+	conn, err := net.ListenMulticastUDP("udp6", iface, &addr)
+	if err != nil {
+		return nil, err
+	}
+	if iface != nil {
+		if err := bindConnToInterface(conn, iface.Name); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	s.PacketConn = conn
+	return conn, nil
+}
+
+// bindConnToInterface binds an open UDP socket to the given interface, see
+// BindToInterface
+func bindConnToInterface(conn *net.UDPConn, ifname string) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var bindErr error
+	err = rc.Control(func(fd uintptr) {
+		bindErr = BindToInterface(int(fd), ifname)
+	})
+	if err != nil {
+		return err
 	}
-	var pc *net.UDPConn
-	var ok bool
-	if pc, ok = s.PacketConn.(*net.UDPConn); !ok {
-		return fmt.Errorf("Error: not an UDPConn")
+	return bindErr
+}
+
+// ActivateAndServe reads DHCPv6 messages and dispatches them to the Handler
+// until the server is closed, in which case it returns nil
+func (s *Server) ActivateAndServe() error {
+	if s.Handler == nil {
+		return fmt.Errorf("ActivateAndServe: no handler specified")
 	}
-	if pc == nil {
-		return fmt.Errorf("ActivateAndServe: Invalid nil PacketConn")
+	pc, err := s.listen()
+	if err != nil {
+		return err
 	}
-	log.Print("Handling requests")
 	for {
-		rbuf := make([]byte, 1024) // FIXME this is bad
+		rbuf := make([]byte, maxUDPReceivedPacketSize)
 		n, peer, err := pc.ReadFrom(rbuf)
 		if err != nil {
-			log.Printf("Error reading from packet conn: %v", err)
-			continue
+			if s.isClosed() {
+				return nil
+			}
+			return err
 		}
-		log.Printf("Handling request from %v", peer)
-		m, err := FromBytes(rbuf[:n])
+		d, err := FromBytes(rbuf[:n])
 		if err != nil {
-			log.Printf("Error parsing DHCPv6 request: %v", err)
+			log.Printf("Error parsing DHCPv6 request from %v: %v", peer, err)
+			continue
+		}
+		conn := pc
+		if relay, ok := d.(*DHCPv6Relay); ok {
+			if relay.Type() != RELAY_FORW {
+				log.Printf("Ignoring %v from %v", relay.Type(), peer)
+				continue
+			}
+			d, err = relay.GetInnerMessage()
+			if err != nil {
+				log.Printf("Error decapsulating relay message from %v: %v", peer, err)
+				continue
+			}
+			conn = &RelayConn{PacketConn: pc, RelayForw: relay}
+		}
+		msg, ok := d.(*DHCPv6Message)
+		if !ok {
+			log.Printf("Ignoring unexpected message from %v: %v", peer, d.Summary())
 			continue
 		}
-		log.Print(m.Summary())
-		// FIXME use s.Handler
+		s.Handler(conn, peer, msg)
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Close stops the server, making ActivateAndServe return, and closes its
+// connection
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.PacketConn == nil {
+		return nil
 	}
-	return nil
+	return s.PacketConn.Close()
 }
@@ -0,0 +1,41 @@
+//go:build linux
+// +build linux
+
+package dhcpv6
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// soBindToIfindex is SO_BINDTOIFINDEX, which is missing from package syscall.
+// Reading it returns the index of the interface a socket is bound to.
+const soBindToIfindex = 0x3e
+
+func TestServerListenBindsToInterface(t *testing.T) {
+	lo, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skipf("no loopback interface: %v", err)
+	}
+	s := NewServer("lo", replyHandler)
+	pc, err := s.listen()
+	if err != nil {
+		t.Skipf("cannot listen on the loopback interface: %v", err)
+	}
+	defer s.Close()
+
+	rc, err := pc.(*net.UDPConn).SyscallConn()
+	require.NoError(t, err)
+	var (
+		ifindex int
+		sockErr error
+	)
+	require.NoError(t, rc.Control(func(fd uintptr) {
+		ifindex, sockErr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, soBindToIfindex)
+	}))
+	require.NoError(t, sockErr)
+	require.Equal(t, lo.Index, ifindex)
+}
@@ -0,0 +1,96 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer runs a Server on the IPv6 loopback with the given handler
+func startTestServer(t *testing.T, handler Handler) (*Server, chan error) {
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	s := &Server{PacketConn: conn, Handler: handler}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ActivateAndServe()
+	}()
+	return s, done
+}
+
+func replyHandler(conn net.PacketConn, peer net.Addr, m *DHCPv6Message) {
+	adv, err := NewAdvertiseFromSolicit(m)
+	if err != nil {
+		return
+	}
+	var reply DHCPv6 = adv
+	if rc, ok := conn.(*RelayConn); ok {
+		reply, err = NewRelayReplFromRelayForw(rc.RelayForw, adv)
+		if err != nil {
+			return
+		}
+	}
+	conn.WriteTo(reply.ToBytes(), peer)
+}
+
+func TestServerDispatch(t *testing.T) {
+	s, done := startTestServer(t, replyHandler)
+	c := NewClient()
+	c.ReadTimeout = 500 * time.Millisecond
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = s.PacketConn.LocalAddr()
+	solicit, err := NewSolicit(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	_, advertise, err := c.Solicit("lo", solicit)
+	require.NoError(t, err)
+	require.Equal(t, ADVERTISE, advertise.Type())
+
+	require.NoError(t, s.Close())
+	require.NoError(t, <-done)
+}
+
+func TestServerDispatchRelayed(t *testing.T) {
+	s, done := startTestServer(t, replyHandler)
+	defer s.Close()
+	solicit, err := NewSolicit(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	inner, err := EncapsulateRelay(solicit, RELAY_FORW, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	outer, err := EncapsulateRelay(inner, RELAY_FORW, net.ParseIP("2001:db8::2"), net.ParseIP("fe80::2"))
+	require.NoError(t, err)
+
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.WriteTo(outer.ToBytes(), s.PacketConn.LocalAddr())
+	require.NoError(t, err)
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	buf := make([]byte, maxUDPReceivedPacketSize)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	reply, err := RelayMessageFromBytes(buf[:n])
+	require.NoError(t, err)
+	require.Equal(t, RELAY_REPL, reply.Type())
+	require.Equal(t, net.ParseIP("fe80::2"), reply.PeerAddr())
+	msg, err := reply.GetInnerMessage()
+	require.NoError(t, err)
+	require.Equal(t, ADVERTISE, msg.Type())
+
+	require.NoError(t, s.Close())
+	require.NoError(t, <-done)
+}
+
+func TestServerNoHandler(t *testing.T) {
+	s := Server{}
+	require.Error(t, s.ActivateAndServe())
+}
+
+func TestServerListenNoInterface(t *testing.T) {
+	s := NewServer("nonexistent0", replyHandler)
+	require.Error(t, s.ActivateAndServe())
+}
@@ -24,8 +24,18 @@ const (
 	LEASEQUERY_REPLY    MessageType = 15
 	LEASEQUERY_DONE     MessageType = 16
 	LEASEQUERY_DATA     MessageType = 17
+	// DHCPv4-over-DHCPv6, see RFC 7341. In these messages the transaction ID
+	// field holds the flags instead.
+	DHCPV4_QUERY    MessageType = 20
+	DHCPV4_RESPONSE MessageType = 21
 )
 
+// IsValid returns true if the message type is one of the known ones, i.e.
+// not MSGTYPE_NONE and not an unassigned value
+func (t MessageType) IsValid() bool {
+	return (t >= SOLICIT && t <= LEASEQUERY_DATA) || t == DHCPV4_QUERY || t == DHCPV4_RESPONSE
+}
+
 func MessageTypeToString(t MessageType) string {
 	if m := MessageTypeToStringMap[t]; m != "" {
 		return m
@@ -51,4 +61,6 @@ var MessageTypeToStringMap = map[MessageType]string{
 	LEASEQUERY_REPLY:    "LEASEQUERY-REPLY",
 	LEASEQUERY_DONE:     "LEASEQUERY-DONE",
 	LEASEQUERY_DATA:     "LEASEQUERY-DATA",
+	DHCPV4_QUERY:        "DHCPV4-QUERY",
+	DHCPV4_RESPONSE:     "DHCPV4-RESPONSE",
 }
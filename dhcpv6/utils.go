@@ -2,6 +2,8 @@ package dhcpv6
 
 import (
 	"strings"
+
+	"github.com/insomniacslk/dhcp/iana"
 )
 
 // IsNetboot function takes a DHCPv6 message and returns true if the machine
@@ -43,9 +45,11 @@ func IsUsingUEFI(msg DHCPv6) bool {
 	//               9    EFI x86-64
 	if opt := msg.GetOneOption(OPTION_CLIENT_ARCH_TYPE); opt != nil {
 		optat := opt.(*OptClientArchType)
-		// TODO investigate if other types are appropriate
-		if optat.ArchType == EFI_BC || optat.ArchType == EFI_X86_64 {
-			return true
+		for _, at := range optat.ArchTypes {
+			// TODO investigate if other types are appropriate
+			if at == iana.EFI_BC || at == iana.EFI_X86_64 {
+				return true
+			}
 		}
 	}
 	if opt := msg.GetOneOption(OPTION_USER_CLASS); opt != nil {
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"testing"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,14 +25,14 @@ func TestIsNetboot(t *testing.T) {
 
 func TestIsUsingUEFIArchTypeTrue(t *testing.T) {
 	msg := DHCPv6Message{}
-	opt := OptClientArchType{ArchType: EFI_BC}
+	opt := OptClientArchType{ArchTypes: []iana.Arch{iana.EFI_BC}}
 	msg.AddOption(&opt)
 	require.True(t, IsUsingUEFI(&msg))
 }
 
 func TestIsUsingUEFIArchTypeFalse(t *testing.T) {
 	msg := DHCPv6Message{}
-	opt := OptClientArchType{ArchType: INTEL_X86PC}
+	opt := OptClientArchType{ArchTypes: []iana.Arch{iana.INTEL_X86PC}}
 	msg.AddOption(&opt)
 	require.False(t, IsUsingUEFI(&msg))
 }
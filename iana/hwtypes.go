@@ -1,6 +1,8 @@
 package iana
 
-type HwTypeType uint8
+// HwTypeType encodes a hardware type, as used by ARP, the DHCPv4 htype field
+// and DHCPv6 DUIDs. See the IANA "Hardware Types" registry.
+type HwTypeType uint16
 
 const (
 	_ HwTypeType = iota // skip 0
@@ -39,8 +41,17 @@ const (
 	HwTypeCAI
 	HwTypeWiegandInterface
 	HwTypePureIP
+	HwTypeHWExp1
+	HwTypeHFI300
 )
 
+// Hardware types outside the sequence above
+const (
+	HwTypeHWExp2    HwTypeType = 256
+	HwTypeAEthernet HwTypeType = 257
+)
+
+// HwTypeToString maps a HwTypeType to a mnemonic name
 var HwTypeToString = map[HwTypeType]string{
 	HwTypeEthernet:             "Ethernet",
 	HwTypeExperimentalEthernet: "Experimental Ethernet",
@@ -77,4 +88,16 @@ var HwTypeToString = map[HwTypeType]string{
 	HwTypeCAI:                  "CAI, TIA-102 Project 125 Common Air Interface",
 	HwTypeWiegandInterface:     "Wiegand Interface",
 	HwTypePureIP:               "Pure IP",
+	HwTypeHWExp1:               "HW_EXP1",
+	HwTypeHFI300:               "HFI-300",
+	HwTypeHWExp2:               "HW_EXP2",
+	HwTypeAEthernet:            "AEthernet",
+}
+
+// String returns a mnemonic name for a given hardware type.
+func (h HwTypeType) String() string {
+	if hw := HwTypeToString[h]; hw != "" {
+		return hw
+	}
+	return "Unknown"
 }
@@ -50,8 +50,8 @@ func GetNetConfFromPacketv6(d *dhcpv6.DHCPv6Message) (*NetConf, error) {
 				IP:   iaaddr.IPv6Addr,
 				Mask: netmask,
 			},
-			PreferredLifetime: int(iaaddr.PreferredLifetime),
-			ValidLifetime:     int(iaaddr.ValidLifetime),
+			PreferredLifetime: int(iaaddr.PreferredLifetime() / time.Second),
+			ValidLifetime:     int(iaaddr.ValidLifetime() / time.Second),
 		})
 	}
 	// get DNS configuration